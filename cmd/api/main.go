@@ -1,31 +1,47 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/willherrera/itau-backend-challenge/internal/api"
+	"github.com/willherrera/itau-backend-challenge/internal/api/grpcserver"
 	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
-	"github.com/willherrera/itau-backend-challenge/internal/api/middleware"
 	"github.com/willherrera/itau-backend-challenge/internal/application"
-	"github.com/willherrera/itau-backend-challenge/internal/domain"
-	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/config"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+	"github.com/willherrera/itau-backend-challenge/internal/health"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+	"github.com/willherrera/itau-backend-challenge/internal/tlsconfig"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+	"github.com/willherrera/itau-backend-challenge/pkg/passwordpolicy"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
 
 	_ "github.com/willherrera/itau-backend-challenge/docs"
 )
 
-const (
-	// Password validation constraints
-	MinPasswordLength   = 9
-	AllowedSpecialChars = "!@#$%^&*()-+"
-
-	// Server configuration
-	DefaultPort = "8080"
-)
-
 // @title Password Validator API
 // @version 1.0
 // @description API para validação de senhas com regras específicas de segurança
@@ -41,48 +57,384 @@ const (
 // @contact.url https://github.com/willherrera/itau-backend-challenge
 
 // @host localhost:8080
-// @schemes http
+// @schemes http https
+
+// @securityDefinitions.apikey APIKey
+// @in header
+// @name X-API-Key
+// @description Chave de API do cliente; exigida quando API_KEYS_FILE está configurado.
 
 func main() {
-	validators := []domain.PasswordValidator{
-		rules.NewMinLengthValidator(MinPasswordLength),
-		rules.NewDigitValidator(),
-		rules.NewLowercaseValidator(),
-		rules.NewUppercaseValidator(),
-		rules.NewSpecialCharValidator(AllowedSpecialChars),
-		rules.NewNoDuplicatesValidator(),
+	cfg, printConfig, err := config.Load(filepath.Base(os.Args[0]), os.Args[1:], os.LookupEnv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(2)
+	}
+	if printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Printing the configuration: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	slog.SetDefault(newLogger(cfg.Log, os.Stderr))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	certs, err := newTLS(cfg.TLS)
+	if err != nil {
+		fatal("Invalid TLS configuration", err)
+	}
+
+	// The HTTP server starts before the policies are loaded, so that the
+	// health endpoints answer (not ready) while they load; the API routes
+	// replace the startup router once the service is built.
+	probe := health.NewProbe()
+	probe.NotReady("policies", "loading")
+	probe.NotReady("dictionaries", "loading")
+
+	routerOpts := []api.Option{api.WithProbe(probe), api.WithCORS(cfg.CORS.Policy())}
+	if cfg.Features.Metrics {
+		routerOpts = append(routerOpts, api.WithMetricsPath(cfg.Metrics.Path))
+	} else {
+		routerOpts = append(routerOpts, api.WithMetricsPath(""))
+	}
+	if !cfg.Features.Swagger {
+		routerOpts = append(routerOpts, api.WithoutSwagger())
+	}
+
+	addr := cfg.Server.Addr()
+	var current atomic.Pointer[http.Handler]
+	setHandler := func(h http.Handler) { current.Store(&h) }
+	setHandler(api.NewStartupRouter(probe, routerOpts...))
+	server := &http.Server{
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			(*current.Load()).ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+	scheme := "http"
+	if certs != nil {
+		server.TLSConfig = certs.Config()
+		scheme = "https"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal("Server failed to listen", err)
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		if certs != nil {
+			serveErr <- server.ServeTLS(listener, "", "")
+		} else {
+			serveErr <- server.Serve(listener)
+		}
+	}()
+	slog.Info("Starting password validation API", "addr", addr)
+
+	if certs != nil {
+		hangup := make(chan os.Signal, 1)
+		signal.Notify(hangup, syscall.SIGHUP)
+		go certs.Run(ctx, hangup, cfg.TLS.ReloadInterval)
+
+		opts := certs.Options()
+		slog.Info("TLS enabled",
+			"min_version", tls.VersionName(opts.MinVersion),
+			"client_certificates", opts.ClientAuth.String(),
+			"certificate", opts.CertFile,
+			"expires", certs.Leaf().NotAfter,
+			"reload_interval", cfg.TLS.ReloadInterval,
+		)
+	} else {
+		slog.Warn("TLS disabled; set TLS_CERT_FILE and TLS_KEY_FILE unless TLS is terminated in front of the server")
+	}
+
+	doc := passwordpolicy.Default()
+	if cfg.Policy.File != "" {
+		loaded, err := passwordpolicy.Load(cfg.Policy.File)
+		if err != nil {
+			fatal("Invalid password policy", err)
+		}
+		doc = loaded
+		slog.Info("Loaded password policy", "version", doc.Version(), "path", cfg.Policy.File)
+	}
+
+	var validatorOpts []passwordpolicy.Option
+	if passwordHistory, err := newPasswordHistory(cfg.History); err != nil {
+		fatal("Invalid password history configuration", err)
+	} else if passwordHistory != nil {
+		validatorOpts = append(validatorOpts, passwordpolicy.WithHistory(passwordHistory))
+		slog.Info("Password history enabled", "store", cfg.History.Store, "depth", passwordHistory.Depth())
+	}
+
+	validator, err := passwordpolicy.NewFromDocument(doc, validatorOpts...)
+	if err != nil {
+		fatal("Invalid password policy", err)
+	}
+	service := application.ServiceOf(validator)
+	slog.Info("Password policies", "policies", validator.Policies())
+	metrics.SetPolicyInfo(doc.Version(), doc.Hash())
+	probe.Ready("policies")
+
+	// The strength dictionaries are decoded on first use; do it now rather
+	// than in the first request.
+	go func() {
+		strength.Estimate("warm-up")
+		probe.Ready("dictionaries")
+	}()
+
+	if cfg.Policy.File != "" {
+		hangup := make(chan os.Signal, 1)
+		signal.Notify(hangup, syscall.SIGHUP)
+
+		reloader := passwordpolicy.NewReloader(cfg.Policy.File, validator, doc)
+		go reloader.Run(ctx, hangup, cfg.Policy.WatchInterval)
+		slog.Info("Password policy reloads on SIGHUP", "watch_interval", cfg.Policy.WatchInterval)
 	}
 
-	service := application.NewPasswordService(validators)
 	handler := handlers.NewPasswordHandler(service)
+	handler.SetBatchLimits(cfg.Limits.BatchMaxItems, cfg.Limits.BatchWorkers)
+	handler.SetStreamMaxLineBytes(cfg.Limits.StreamMaxLineBytes)
+	handler.SetStreamMaxLines(cfg.Limits.StreamMaxLines)
+	handler.SetStreamTimeouts(cfg.Limits.StreamLineTimeout, cfg.Limits.StreamMaxDuration)
+
+	limits, err := newRateLimits(cfg.RateLimit)
+	if err != nil {
+		fatal("Invalid rate limit configuration", err)
+	}
+	slog.Info("Rate limits", "per_ip", limits.PerIP.String(), "tiers", fmt.Sprint(limits.Tiers), "items", limits.Items.String())
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		slog.Info("CORS enabled", "origins", cfg.CORS.AllowedOrigins, "credentials", cfg.CORS.AllowCredentials)
+	}
+
+	routerOpts = append(routerOpts, api.WithRateLimits(limits))
+	interceptors := []grpc.UnaryServerInterceptor{grpcserver.IPRateLimitInterceptor(limits.Store, limits.PerIP)}
+	if keysPath := cfg.Auth.KeysFile; keysPath != "" {
+		keys, err := auth.Load(keysPath)
+		if err != nil {
+			fatal("Invalid API keys file", err)
+		}
+		for _, client := range keys.Clients() {
+			for _, name := range client.Policies {
+				if !slices.Contains(validator.Policies(), name) {
+					slog.Warn("API key allows an unknown policy", "client", client.Name, "policy", name)
+				}
+			}
+			if _, ok := limits.Tiers[client.Tier]; !ok {
+				slog.Warn("API key tier has no rate limits; the default tier applies", "client", client.Name, "tier", client.Tier, "default_tier", ratelimit.DefaultTier)
+			}
+		}
+		if keys.HasCertificates() && (certs == nil || certs.Options().ClientAuth == tlsconfig.NoClientCert) {
+			slog.Warn("The API keys file lists client certificates, but TLS client authentication is disabled; set TLS_CLIENT_CA_FILE", "path", keysPath)
+		}
+		routerOpts = append(routerOpts, api.WithAPIKeys(keys))
+		interceptors = append(interceptors, grpcserver.AuthInterceptor(keys), grpcserver.ClientRateLimitInterceptor(limits.Store, limits.Tiers))
+		slog.Info("API key authentication enabled", "keys", keys.Len(), "path", keysPath)
+	} else {
+		slog.Warn("API key authentication disabled; set API_KEYS_FILE to require API keys")
+	}
+
+	router := api.NewRouter(handler, routerOpts...)
+
+	var grpcServer *grpc.Server
+	if cfg.Features.GRPC {
+		grpcListener, err := net.Listen("tcp", cfg.Server.GRPCAddr())
+		if err != nil {
+			fatal("gRPC server failed to listen", err)
+		}
+		grpcOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+		if certs != nil {
+			grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(certs.Config())))
+		}
+		var grpcHealth *grpchealth.Server
+		grpcServer, grpcHealth = grpcserver.New(service, grpcOpts...)
+		probe.Watch(func(ready bool) { grpcserver.SetServing(grpcHealth, ready) })
+		go func() {
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				fatal("gRPC server failed", err)
+			}
+		}()
+	}
+
+	setHandler(router)
+	port := ":" + strconv.Itoa(cfg.Server.Port)
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		methods, _ := route.GetMethods()
+		if err == nil && len(methods) > 0 {
+			slog.Info("Endpoint", "method", methods[0], "url", scheme+"://localhost"+port+path)
+		}
+		return nil
+	})
+	if cfg.Features.Swagger {
+		slog.Info("Endpoint", "method", "GET", "url", scheme+"://localhost"+port+"/swagger/index.html")
+	}
+	if grpcServer != nil {
+		slog.Info("gRPC API (password.v1.PasswordService, grpc.health.v1.Health, reflection)", "addr", cfg.Server.GRPCAddr())
+	}
+
+	select {
+	case err := <-serveErr:
+		fatal("Server failed", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Fail readiness first and give load balancers SHUTDOWN_DELAY to stop
+	// sending new requests, then drain the requests in flight.
+	slog.Info("Shutting down: draining connections", "timeout", cfg.Server.ShutdownTimeout)
+	probe.NotReady("shutdown", "draining connections")
+	time.Sleep(cfg.Server.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
+		close(grpcStopped)
+	}()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("HTTP server did not drain in time, closing connections", "error", err)
+		server.Close()
+	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		if grpcServer != nil {
+			slog.Warn("gRPC server did not drain in time, closing connections")
+			grpcServer.Stop()
+		}
+	}
+	slog.Info("Server stopped")
+}
+
+// fatal logs err and exits, whatever the log level.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
 
-	router := mux.NewRouter()
+// newLogger returns the logger of the server. It becomes the default
+// logger, so it also receives the output of the log package, at the info
+// level.
+func newLogger(c config.Log, w io.Writer) *slog.Logger {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Level))
 
-	apiRouter := router.PathPrefix("/api/v1").Subrouter()
-	apiRouter.HandleFunc("/validate-password", handler.ValidatePassword).Methods("POST", "OPTIONS")
+	opts := &slog.HandlerOptions{Level: level}
+	if c.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
 
-	router.HandleFunc("/health", handler.Health).Methods("GET")
-	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
+// newTLS loads the server certificate. The client certificates are
+// required by default when a client CA file is set. It returns nil when TLS
+// is disabled.
+func newTLS(c config.TLS) (*tlsconfig.Certificates, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
 
-	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	minVersion, err := tlsconfig.ParseVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	suites, err := tlsconfig.ParseCipherSuites(strings.Join(c.CipherSuites, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth := tlsconfig.NoClientCert
+	if c.ClientAuth != "" {
+		if clientAuth, err = tlsconfig.ParseClientAuth(c.ClientAuth); err != nil {
+			return nil, err
+		}
+	} else if c.ClientCAFile != "" {
+		clientAuth = tlsconfig.RequireClientCert
+	}
 
-	router.Use(middleware.LoggingMiddleware)
-	router.Use(middleware.CORSMiddleware)
+	return tlsconfig.Load(tlsconfig.Options{
+		CertFile:     c.CertFile,
+		KeyFile:      c.KeyFile,
+		ClientCAFile: c.ClientCAFile,
+		ClientAuth:   clientAuth,
+		MinVersion:   minVersion,
+		CipherSuites: suites,
+	})
+}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = DefaultPort
+// newPasswordHistory builds the password history. It returns nil when no
+// store is configured.
+func newPasswordHistory(c config.History) (*history.History, error) {
+	var store history.PasswordHistoryStore
+	switch c.Store {
+	case "":
+		return nil, nil
+	case "memory":
+		store = history.NewMemoryStore()
+	case "file":
+		fileStore, err := history.NewFileStore(c.File)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	default:
+		return nil, fmt.Errorf("unknown history store %q", c.Store)
 	}
 
-	addr := ":" + port
-	log.Printf("Starting password validation API on %s", addr)
-	log.Printf("Endpoints:")
-	log.Printf("  POST   http://localhost%s/api/v1/validate-password", addr)
-	log.Printf("  GET    http://localhost%s/health", addr)
-	log.Printf("  GET    http://localhost%s/metrics", addr)
-	log.Printf("  GET    http://localhost%s/swagger/index.html", addr)
+	var hasher history.Hasher
+	switch c.Hash {
+	case "argon2id":
+		hasher = history.NewArgon2idHasher(history.DefaultArgon2Params)
+	case "bcrypt":
+		hasher = history.NewBcryptHasher(bcrypt.DefaultCost)
+	default:
+		return nil, fmt.Errorf("unknown history hash %q", c.Hash)
+	}
+
+	return history.NewHistory(store, hasher, c.Depth), nil
+}
+
+// newRateLimits builds the rate limits of the HTTP and gRPC APIs.
+func newRateLimits(c config.RateLimit) (api.RateLimits, error) {
+	var limits api.RateLimits
 
-	if err := http.ListenAndServe(addr, router); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	perIP, err := ratelimit.ParseLimit(c.IP)
+	if err != nil {
+		return limits, err
 	}
+	tiers, err := ratelimit.ParseTiers(c.Tiers)
+	if err != nil {
+		return limits, err
+	}
+	items, err := ratelimit.ParseLimit(c.Items)
+	if err != nil {
+		return limits, err
+	}
+	proxies, err := c.Proxies()
+	if err != nil {
+		return limits, err
+	}
+
+	return api.RateLimits{
+		Store:          ratelimit.NewMemoryStore(c.MaxKeys),
+		PerIP:          perIP,
+		Tiers:          tiers,
+		Items:          items,
+		TrustedProxies: proxies,
+	}, nil
 }
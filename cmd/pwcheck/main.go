@@ -0,0 +1,314 @@
+// Command pwcheck validates passwords locally with the same rules and
+// policies as the API, without starting a server. It is meant for audits of
+// credential dumps and configuration files in CI.
+//
+// Usage:
+//
+//	pwcheck [flags] [password ...]
+//
+// Passwords come from the arguments, from the file given with -file, or from
+// standard input, one per line. Passwords are never printed unless
+// -show-passwords is set; results are identified by their input line.
+//
+// The exit status is 0 when every password is valid, 1 when at least one is
+// invalid and 2 on usage or configuration errors.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/willherrera/itau-backend-challenge/pkg/passwordpolicy"
+)
+
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitError   = 2
+
+	// maxLineBytes is the longest password line accepted.
+	maxLineBytes = 1024 * 1024
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type options struct {
+	policyFile    string
+	policyName    string
+	inputFile     string
+	format        string
+	showPasswords bool
+	invalidOnly   bool
+	workers       int
+}
+
+// run executes the command and returns its exit status.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("pwcheck", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pwcheck [flags] [password ...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Validates passwords given as arguments, read from -file or from standard input (one per line).")
+		fmt.Fprintln(stderr, "Exit status: 0 if every password is valid, 1 if any is invalid, 2 on errors.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	var opts options
+	flags.StringVar(&opts.policyFile, "policy", "", "policy file (YAML or JSON); the built-in policy is used when empty")
+	flags.StringVar(&opts.policyName, "name", passwordpolicy.DefaultPolicy, "named policy of the policy file to apply")
+	flags.StringVar(&opts.inputFile, "file", "", "read passwords from this file, one per line (\"-\" for standard input)")
+	flags.StringVar(&opts.format, "format", "text", "output format: text or json (one JSON object per line)")
+	flags.BoolVar(&opts.showPasswords, "show-passwords", false, "include the passwords in the output")
+	flags.BoolVar(&opts.invalidOnly, "invalid-only", false, "report only invalid passwords")
+	flags.IntVar(&opts.workers, "workers", 0, "passwords validated in parallel (0 means GOMAXPROCS)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitValid
+		}
+		return exitError
+	}
+
+	if opts.format != "text" && opts.format != "json" {
+		fmt.Fprintf(stderr, "pwcheck: unknown format %q (expected text or json)\n", opts.format)
+		return exitError
+	}
+	if opts.inputFile != "" && flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "pwcheck: passwords cannot be given both as arguments and with -file")
+		return exitError
+	}
+
+	service, err := newService(opts.policyFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "pwcheck: invalid password policy: %v\n", err)
+		return exitError
+	}
+
+	var input io.Reader
+	switch {
+	case flags.NArg() > 0:
+		input = strings.NewReader(strings.Join(flags.Args(), "\n"))
+	case opts.inputFile != "" && opts.inputFile != "-":
+		file, err := os.Open(opts.inputFile)
+		if err != nil {
+			fmt.Fprintf(stderr, "pwcheck: %v\n", err)
+			return exitError
+		}
+		defer file.Close()
+		input = file
+	default:
+		input = stdin
+	}
+
+	sum, err := check(ctx, service, opts, input, newReporter(opts, stdout))
+	if errors.Is(err, passwordpolicy.ErrPolicyNotFound) {
+		fmt.Fprintf(stderr, "pwcheck: unknown policy %q (available: %s)\n", opts.policyName, strings.Join(service.Policies(), ", "))
+		return exitError
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "pwcheck: %v\n", err)
+		return exitError
+	}
+
+	// The summary goes to stderr in text mode so that stdout only lists
+	// results, and ends the stream in JSON mode, like the NDJSON endpoint.
+	if opts.format == "json" {
+		json.NewEncoder(stdout).Encode(struct {
+			Summary summary `json:"summary"`
+		}{sum})
+	} else {
+		fmt.Fprintf(stderr, "%d checked, %d valid, %d invalid\n", sum.Total, sum.Valid, sum.Invalid)
+	}
+	if sum.Invalid > 0 {
+		return exitInvalid
+	}
+	return exitValid
+}
+
+func newService(path string) (*passwordpolicy.Validator, error) {
+	doc := passwordpolicy.Default()
+	if path != "" {
+		loaded, err := passwordpolicy.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		doc = loaded
+	}
+	return passwordpolicy.NewFromDocument(doc)
+}
+
+// reportFunc writes the result of the password read from line. password is
+// empty unless passwords are shown.
+type reportFunc func(line int, password string, result *passwordpolicy.Result) error
+
+type summary struct {
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+}
+
+// check validates every non-empty line of input and reports the results in
+// input order, although they are computed in parallel.
+func check(ctx context.Context, service *passwordpolicy.Validator, opts options, input io.Reader, report reportFunc) (summary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	in := make(chan passwordpolicy.BatchItem)
+	results, err := service.ValidateStream(ctx, opts.policyName, in, opts.workers)
+	if err != nil {
+		return summary{}, err
+	}
+
+	// Passwords are kept only until their result is reported, and only
+	// when they are going to be shown.
+	var shown sync.Map
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(in)
+		readErr <- readPasswords(ctx, input, in, func(seq int, password string) {
+			if opts.showPasswords {
+				shown.Store(seq, password)
+			}
+		})
+	}()
+
+	var sum summary
+	pending := make(map[int]passwordpolicy.BatchResult)
+	next := 0
+	for result := range results {
+		pending[result.Seq] = result
+		for r, ok := pending[next]; ok; r, ok = pending[next] {
+			delete(pending, next)
+			next++
+
+			sum.Total++
+			if r.IsValid {
+				sum.Valid++
+			} else {
+				sum.Invalid++
+			}
+			password := ""
+			if v, ok := shown.LoadAndDelete(r.Seq); ok {
+				password = v.(string)
+			}
+			line, _ := strconv.Atoi(r.ID)
+			if err := report(line, password, r.Result); err != nil {
+				return sum, err
+			}
+		}
+	}
+
+	if err := <-readErr; err != nil {
+		return sum, err
+	}
+	if err := ctx.Err(); err != nil {
+		return sum, err
+	}
+	return sum, nil
+}
+
+// readPasswords sends every non-empty line of input to in. Items are
+// numbered from 0 in Seq and carry their line number, counted from 1, in ID.
+// keep is called with each password before it is sent.
+func readPasswords(ctx context.Context, input io.Reader, in chan<- passwordpolicy.BatchItem, keep func(seq int, password string)) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	seq := 0
+	for line := 1; scanner.Scan(); line++ {
+		password := strings.TrimSuffix(scanner.Text(), "\r")
+		if password == "" {
+			continue
+		}
+		keep(seq, password)
+		select {
+		case in <- passwordpolicy.BatchItem{ID: strconv.Itoa(line), Seq: seq, Password: password}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		seq++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading passwords: %w", err)
+	}
+	return nil
+}
+
+// newReporter returns the function that writes one result in the selected
+// format.
+func newReporter(opts options, w io.Writer) reportFunc {
+	enc := json.NewEncoder(w)
+	return func(line int, password string, result *passwordpolicy.Result) error {
+		if opts.invalidOnly && result.IsValid {
+			return nil
+		}
+		if opts.format == "json" {
+			return enc.Encode(jsonResult{
+				Line:       line,
+				Password:   password,
+				IsValid:    result.IsValid,
+				Policy:     result.Policy,
+				Violations: toJSONViolations(result.Violations),
+				Score:      result.Strength.Score,
+			})
+		}
+
+		label := fmt.Sprintf("line %d", line)
+		if password != "" {
+			label += fmt.Sprintf(" (%q)", password)
+		}
+		if result.IsValid {
+			_, err := fmt.Fprintf(w, "%s: valid\n", label)
+			return err
+		}
+		_, err := fmt.Fprintf(w, "%s: invalid: %s\n", label, strings.Join(result.Errors, "; "))
+		return err
+	}
+}
+
+type jsonResult struct {
+	Line       int             `json:"line"`
+	Password   string          `json:"password,omitempty"`
+	IsValid    bool            `json:"isValid"`
+	Policy     string          `json:"policy"`
+	Violations []jsonViolation `json:"violations,omitempty"`
+	Score      int             `json:"score"`
+}
+
+type jsonViolation struct {
+	Code     string         `json:"code"`
+	Rule     string         `json:"rule"`
+	Message  string         `json:"message"`
+	Params   map[string]any `json:"params,omitempty"`
+	Position *int           `json:"position,omitempty"`
+}
+
+// toJSONViolations drops the offending character, which would reveal part
+// of the password.
+func toJSONViolations(violations []passwordpolicy.Violation) []jsonViolation {
+	out := make([]jsonViolation, 0, len(violations))
+	for _, v := range violations {
+		jv := jsonViolation{Code: v.Code, Rule: v.Rule, Message: v.Message, Params: v.Params}
+		if v.Position != passwordpolicy.NoPosition {
+			position := v.Position
+			jv.Position = &position
+		}
+		out = append(out, jv)
+	}
+	return out
+}
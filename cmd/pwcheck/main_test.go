@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	passwords := filepath.Join(dir, "passwords.txt")
+	if err := os.WriteFile(passwords, []byte("AbTp9!fok\r\n\r\nAbTp9!foo\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policyFile := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(policyFile, []byte("rules:\n  - name: min_length\n    params:\n      length: 4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOutput []string
+	}{
+		{
+			name:       "valid arguments",
+			args:       []string{"AbTp9!fok", "Zx8#kLm2q"},
+			wantCode:   exitValid,
+			wantOutput: []string{"line 1: valid", "line 2: valid"},
+		},
+		{
+			name:       "invalid password from stdin",
+			stdin:      "AbTp9!fok\nabc\n",
+			wantCode:   exitInvalid,
+			wantOutput: []string{"line 1: valid", "line 2: invalid: password must have at least 9 characters"},
+		},
+		{
+			name:       "file keeps line numbers across blank lines",
+			args:       []string{"-file", passwords},
+			wantCode:   exitInvalid,
+			wantOutput: []string{"line 1: valid", "line 3: invalid: password must not contain repeated characters"},
+		},
+		{
+			name:       "invalid only",
+			args:       []string{"-file", passwords, "-invalid-only"},
+			wantCode:   exitInvalid,
+			wantOutput: []string{"line 3: invalid"},
+		},
+		{
+			name:       "policy file",
+			args:       []string{"-policy", policyFile, "abcd"},
+			wantCode:   exitValid,
+			wantOutput: []string{"line 1: valid"},
+		},
+		{
+			name:     "unknown policy name",
+			args:     []string{"-name", "admin", "abc"},
+			wantCode: exitError,
+		},
+		{
+			name:     "missing policy file",
+			args:     []string{"-policy", filepath.Join(dir, "missing.yaml"), "abc"},
+			wantCode: exitError,
+		},
+		{
+			name:     "unknown format",
+			args:     []string{"-format", "xml", "abc"},
+			wantCode: exitError,
+		},
+		{
+			name:     "arguments and file",
+			args:     []string{"-file", passwords, "abc"},
+			wantCode: exitError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			if len(tt.wantOutput) == 0 {
+				return
+			}
+			if len(lines) != len(tt.wantOutput) {
+				t.Fatalf("output = %q, want %d lines", stdout.String(), len(tt.wantOutput))
+			}
+			for i, want := range tt.wantOutput {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("line %d = %q, want prefix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_DoesNotEchoPasswords(t *testing.T) {
+	for _, format := range []string{"text", "json"} {
+		var stdout, stderr bytes.Buffer
+		run(context.Background(), []string{"-format", format, "Secr3t!xy", "hunter2"}, nil, &stdout, &stderr)
+
+		output := stdout.String() + stderr.String()
+		if strings.Contains(output, "Secr3t!xy") || strings.Contains(output, "hunter2") {
+			t.Errorf("%s output contains a password: %s", format, output)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	run(context.Background(), []string{"-show-passwords", "hunter2"}, nil, &stdout, &stderr)
+	if !strings.Contains(stdout.String(), `"hunter2"`) {
+		t.Errorf("output = %q, want the password with -show-passwords", stdout.String())
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	input := strings.Repeat("AbTp9!fok\nabc\n", 50)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"-format", "json", "-workers", "4"}, strings.NewReader(input), &stdout, &stderr)
+	if code != exitInvalid {
+		t.Fatalf("run() = %d, want %d", code, exitInvalid)
+	}
+
+	dec := json.NewDecoder(&stdout)
+	for want := 1; want <= 100; want++ {
+		var result jsonResult
+		if err := dec.Decode(&result); err != nil {
+			t.Fatalf("Decode() error: %v", err)
+		}
+		if result.Line != want || result.IsValid != (want%2 == 1) {
+			t.Fatalf("result = %+v, want line %d in input order", result, want)
+		}
+		if !result.IsValid && result.Violations[0].Code != "MIN_LENGTH" {
+			t.Errorf("line %d violations = %+v, want MIN_LENGTH first", want, result.Violations)
+		}
+	}
+
+	var last struct {
+		Summary summary `json:"summary"`
+	}
+	if err := dec.Decode(&last); err != nil {
+		t.Fatalf("Decode() summary error: %v", err)
+	}
+	if last.Summary != (summary{Total: 100, Valid: 50, Invalid: 50}) {
+		t.Errorf("summary = %+v, want 100 total, 50 valid, 50 invalid", last.Summary)
+	}
+}
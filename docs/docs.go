@@ -17,9 +17,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/validate-password": {
+        "/api/v1/generate-password": {
             "post": {
-                "description": "Valida se uma senha atende a todos os critérios de segurança definidos",
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Gera senhas aleatórias (crypto/rand) ou frases-senha a partir de uma lista de palavras embutida.\nCada senha gerada é validada com todas as regras da política antes de ser devolvida.\nO corpo é opcional; sem ele é gerada uma senha aleatória de 16 caracteres para a política \"default\".",
                 "consumes": [
                     "application/json"
                 ],
@@ -29,8 +34,101 @@ const docTemplate = `{
                 "tags": [
                     "Password"
                 ],
-                "summary": "Valida uma senha",
+                "summary": "Gera senhas que atendem à política",
+                "parameters": [
+                    {
+                        "description": "Opções de geração",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.GeneratePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Senhas geradas",
+                        "schema": {
+                            "$ref": "#/definitions/models.GeneratePasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Opções inválidas",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "A política não pode ser atendida com essas opções",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Geração cancelada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/policies/{name}/validate": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Valida a senha usando a política nomeada no caminho (ex.: default, admin, service-account)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Password"
+                ],
+                "summary": "Valida uma senha com uma política específica",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome da política",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma das mensagens (pt-BR, en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
                     {
                         "description": "Senha a ser validada",
                         "name": "request",
@@ -54,8 +152,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "405": {
-                        "description": "Método não permitido",
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -63,69 +179,881 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/api/v1/policy": {
             "get": {
-                "description": "Verifica se a API está funcionando corretamente",
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Lista as regras da política em uso, com códigos de violação, descrições e parâmetros,\ngeradas a partir dos validadores configurados. Use para montar checklists de senha no cliente.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Policy"
+                ],
+                "summary": "Regras da política ativa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome da política (padrão: default)",
+                        "name": "policy",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
-                        "description": "API está saudável",
+                        "description": "Regras da política",
                         "schema": {
-                            "$ref": "#/definitions/models.HealthResponse"
+                            "$ref": "#/definitions/models.PolicyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Bad Request"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "Invalid request body"
-                }
-            }
         },
-        "models.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "service": {
-                    "type": "string",
-                    "example": "password-validator"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "healthy"
+        "/api/v1/subjects/{id}/history": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Registra a senha atual do sujeito após uma troca bem-sucedida. Apenas um hash lento\n(argon2id ou bcrypt) é armazenado, e somente as últimas N senhas são mantidas.\nO histórico de cada sujeito pertence ao cliente (chave de API) que o gravou.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "History"
+                ],
+                "summary": "Registra uma senha no histórico",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identificador do sujeito (usuário ou conta)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Senha a registrar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecordPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Senha registrada"
+                    },
+                    "400": {
+                        "description": "Requisição inválida ou senha maior que 72 bytes com bcrypt",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Falha ao gravar o histórico",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Histórico de senhas desabilitado",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.ValidatePasswordRequest": {
-            "type": "object",
-            "required": [
-                "password"
-            ],
-            "properties": {
-                "password": {
-                    "type": "string",
-                    "example": "AbTp9!fok"
+        "/api/v1/validate-password": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Valida se uma senha atende a todos os critérios de segurança definidos.\nO campo opcional \"policy\" seleciona uma política nomeada; sem ele é usada a política \"default\".\nO campo opcional subjectId ativa a verificação de reuso quando o histórico de senhas está habilitado.\nOs campos opcionais username, email, firstName, lastName e companyName são usados pela regra personal_info.\nAs mensagens das violações seguem o campo \"locale\" ou o cabeçalho Accept-Language (pt-BR, en ou es; padrão en).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Password"
+                ],
+                "summary": "Valida uma senha",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma das mensagens (pt-BR, en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Senha a ser validada",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado da validação",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Requisição inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "405": {
+                        "description": "Método não permitido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Validação cancelada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.ValidatePasswordResponse": {
-            "type": "object",
-            "properties": {
+        "/api/v1/validate-password-change": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Valida a nova senha com as regras da política e a compara com a senha atual,\nrejeitando variações pequenas (ex.: Summer2024! → Summer2025!) com o código SIMILAR_TO_PREVIOUS.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Password"
+                ],
+                "summary": "Valida uma troca de senha",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma das mensagens (pt-BR, en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Senha atual e nova senha",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado da validação da nova senha",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Requisição inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Validação cancelada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/validate-passwords": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Valida um lote de senhas com a mesma política, em paralelo com um número limitado de workers.\nCada item pode ter um \"id\", devolvido no resultado correspondente; os resultados seguem a ordem dos itens.\nO tamanho máximo do lote é configurável (BATCH_MAX_ITEMS, padrão 1000).\nCada senha do lote consome uma unidade do limite de senhas (RATE_LIMIT_ITEMS).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Password"
+                ],
+                "summary": "Valida várias senhas em uma requisição",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma das mensagens (pt-BR, en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Senhas a serem validadas",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultados da validação",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Requisição inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Lote maior que o limite",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições ou de senhas excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Validação cancelada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/validate-passwords/stream": {
+            "post": {
+                "security": [
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "Recebe um corpo application/x-ndjson com um objeto {\"id\",\"password\"} por linha e devolve, também em NDJSON,\num resultado por linha assim que cada senha é validada (a ordem pode diferir da entrada; use \"line\" ou \"id\").\nO corpo nunca é carregado inteiro em memória. Linhas inválidas ou maiores que o limite (STREAM_MAX_LINE_BYTES)\ngeram uma linha com \"error\" e não interrompem o fluxo. A última linha traz o resumo com contagens por regra.\nO fluxo lê no máximo STREAM_MAX_LINES linhas (padrão 100000) e é desacelerado ao limite de senhas (RATE_LIMIT_ITEMS).\nA leitura é encerrada se nenhuma linha chegar em STREAM_LINE_TIMEOUT (padrão 30s) ou após STREAM_MAX_DURATION (padrão 10m).",
+                "consumes": [
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Password"
+                ],
+                "summary": "Valida senhas em fluxo (NDJSON)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome da política (padrão: default)",
+                        "name": "policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma das mensagens (pt-BR, en, es); tem precedência sobre Accept-Language",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma das mensagens (pt-BR, en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Uma senha por linha",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Um resultado por linha, seguido de models.ValidatePasswordStreamSummary",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidatePasswordStreamResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Chave de API ausente ou inválida",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Chave de API sem acesso à política",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Política não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type diferente de application/x-ndjson",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de requisições excedido",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Verifica se a API está pronta para receber tráfego (equivalente a /health/ready)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "API está saudável",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Carregando ou desligando",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Indica que o processo está de pé e respondendo. Não depende do carregamento das políticas\nnem do desligamento: uma falha aqui significa que o processo deve ser reiniciado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness",
+                "responses": {
+                    "200": {
+                        "description": "Processo respondendo",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Indica se a API pode receber tráfego. Responde 503 enquanto as políticas e dicionários\nsão carregados e durante o desligamento, com os motivos em \"checks\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness",
+                "responses": {
+                    "200": {
+                        "description": "Pronta para receber tráfego",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Carregando ou desligando",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.CrackTimesSeconds": {
+            "type": "object",
+            "properties": {
+                "offlineFastHashing1e10PerSecond": {
+                    "type": "number",
+                    "example": 0.1000000001
+                },
+                "offlineSlowHashing1e4PerSecond": {
+                    "type": "number",
+                    "example": 100000.0001
+                },
+                "onlineNoThrottling10PerSecond": {
+                    "type": "number",
+                    "example": 100000000.1
+                },
+                "onlineThrottling100PerHour": {
+                    "type": "number",
+                    "example": 36000000036
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Invalid request body"
+                }
+            }
+        },
+        "models.GeneratePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count of passwords to generate, from 1 (default) to 20.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "excludeAmbiguous": {
+                    "description": "ExcludeAmbiguous leaves out characters that are easily confused, such\nas 0/O and 1/l/I.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "length": {
+                    "description": "Length of random passwords; defaults to 16 or the policy minimum.",
+                    "type": "integer",
+                    "example": 16
+                },
+                "mode": {
+                    "description": "Mode is \"random\" (default) or \"passphrase\".",
+                    "type": "string",
+                    "enum": [
+                        "random",
+                        "passphrase"
+                    ],
+                    "example": "random"
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "words": {
+                    "description": "Words in a passphrase; defaults to 4. More are added if the policy\nminimum length requires it.",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.GeneratePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "passwords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k#T9vQ2m!xRb7LpZ"
+                    ]
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                }
+            }
+        },
+        "models.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks explains why the service is not ready.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "shutdown: draining connections"
+                    ]
+                },
+                "service": {
+                    "type": "string",
+                    "example": "password-validator"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                }
+            }
+        },
+        "models.PolicyResponse": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PolicyRule"
+                    }
+                }
+            }
+        },
+        "models.PolicyRule": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "MIN_LENGTH"
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Password must have at least 9 characters"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "min_length"
+                }
+            }
+        },
+        "models.RecordPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "AbTp9!fok"
+                }
+            }
+        },
+        "models.Strength": {
+            "type": "object",
+            "properties": {
+                "crackTimeDisplay": {
+                    "type": "string",
+                    "example": "1 day"
+                },
+                "crackTimesSeconds": {
+                    "$ref": "#/definitions/models.CrackTimesSeconds"
+                },
+                "guesses": {
+                    "type": "number",
+                    "example": 1000000001
+                },
+                "guessesLog10": {
+                    "type": "number",
+                    "example": 9
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "warning": {
+                    "type": "string",
+                    "example": "This is a top-10 common password"
+                }
+            }
+        },
+        "models.ValidatePasswordChangeRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "oldPassword"
+            ],
+            "properties": {
+                "companyName": {
+                    "type": "string",
+                    "example": "Example S.A."
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao.silva@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "João"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Silva"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "pt-BR"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "example": "AbTp9!fok"
+                },
+                "oldPassword": {
+                    "type": "string",
+                    "example": "Summer2024!"
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "subjectId": {
+                    "type": "string",
+                    "example": "user-42"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jsilva"
+                }
+            }
+        },
+        "models.ValidatePasswordItem": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "companyName": {
+                    "type": "string",
+                    "example": "Example S.A."
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao.silva@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "João"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "legacy-1042"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Silva"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "AbTp9!fok"
+                },
+                "subjectId": {
+                    "type": "string",
+                    "example": "user-42"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jsilva"
+                }
+            }
+        },
+        "models.ValidatePasswordRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "companyName": {
+                    "type": "string",
+                    "example": "Example S.A."
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao.silva@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "João"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Silva"
+                },
+                "locale": {
+                    "description": "Locale selects the language of the violation messages (pt-BR, en or\nes) and takes precedence over the Accept-Language header.",
+                    "type": "string",
+                    "example": "pt-BR"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "AbTp9!fok"
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "subjectId": {
+                    "description": "SubjectID identifies the user or account changing its password. When\npassword history is enabled, its recent passwords are rejected.",
+                    "type": "string",
+                    "example": "user-42"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jsilva"
+                }
+            }
+        },
+        "models.ValidatePasswordResponse": {
+            "type": "object",
+            "properties": {
                 "errors": {
                     "type": "array",
                     "items": {
@@ -138,9 +1066,178 @@ const docTemplate = `{
                 "isValid": {
                     "type": "boolean",
                     "example": true
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "strength": {
+                    "$ref": "#/definitions/models.Strength"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Violation"
+                    }
+                }
+            }
+        },
+        "models.ValidatePasswordResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        ""
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "legacy-1042"
+                },
+                "isValid": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "strength": {
+                    "$ref": "#/definitions/models.Strength"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Violation"
+                    }
+                }
+            }
+        },
+        "models.ValidatePasswordStreamResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "line is longer than 4096 bytes"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        ""
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "legacy-1042"
+                },
+                "isValid": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "strength": {
+                    "$ref": "#/definitions/models.Strength"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Violation"
+                    }
+                }
+            }
+        },
+        "models.ValidatePasswordsRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ValidatePasswordItem"
+                    }
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "pt-BR"
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                }
+            }
+        },
+        "models.ValidatePasswordsResponse": {
+            "type": "object",
+            "properties": {
+                "invalid": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "policy": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ValidatePasswordResult"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "valid": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.Violation": {
+            "type": "object",
+            "properties": {
+                "char": {
+                    "type": "string",
+                    "example": "o"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "MIN_LENGTH"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "password must have at least 9 characters"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "min_length"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "APIKey": {
+            "description": "Chave de API do cliente; exigida quando API_KEYS_FILE está configurado.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
     }
 }`
 
@@ -149,7 +1246,7 @@ var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:8080",
 	BasePath:         "",
-	Schemes:          []string{"http"},
+	Schemes:          []string{"http", "https"},
 	Title:            "Password Validator API",
 	Description:      "API para validação de senhas com regras específicas de segurança\n\nRegras de validação:\n- Mínimo de 9 caracteres\n- Pelo menos 1 dígito\n- Pelo menos 1 letra minúscula\n- Pelo menos 1 letra maiúscula\n- Pelo menos 1 caractere especial (!@#$%^&*()-+)\n- Não deve conter caracteres repetidos",
 	InfoInstanceName: "swagger",
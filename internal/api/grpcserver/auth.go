@@ -0,0 +1,77 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the metadata key that carries the API key, the gRPC
+// counterpart of the X-API-Key header.
+const APIKeyMetadata = "x-api-key"
+
+// AuthInterceptor requires one of keys on every call to the password
+// service and stores the client that owns it in the context. Like the HTTP
+// API, a verified TLS client certificate listed in keys replaces the API
+// key. Health checks and reflection stay open.
+func AuthInterceptor(keys *auth.Keys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isPasswordService(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		if cert := peerCertificate(ctx); cert != nil {
+			if client, ok := keys.AuthenticateCertificate(cert); ok {
+				setLogClient(ctx, client.Name)
+				return handler(auth.NewContext(ctx, client), req)
+			}
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(APIKeyMetadata)
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing API key: send it in the "+APIKeyMetadata+" metadata")
+		}
+		client, ok := keys.Authenticate(values[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+
+		setLogClient(ctx, client.Name)
+		return handler(auth.NewContext(ctx, client), req)
+	}
+}
+
+// peerCertificate returns the certificate the caller presented over TLS, if
+// any.
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+	return info.State.PeerCertificates[0]
+}
+
+// authorizePolicy returns a PermissionDenied error when the API key of the
+// call may not use policy.
+func authorizePolicy(ctx context.Context, policy string) error {
+	if policy == "" {
+		policy = application.DefaultPolicy
+	}
+	if client, ok := auth.FromContext(ctx); ok && !client.AllowsPolicy(policy) {
+		return status.Error(codes.PermissionDenied, "API key is not allowed to use policy: "+policy)
+	}
+	return nil
+}
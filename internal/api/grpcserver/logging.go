@@ -0,0 +1,49 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingInterceptor logs each unary call with the attributes of
+// middleware.LoggingMiddleware.
+func LoggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	entry := &logEntry{client: auth.Anonymous}
+	resp, err := handler(context.WithValue(ctx, logEntryKey{}, entry), req)
+
+	addr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	slog.Info("gRPC call",
+		"method", info.FullMethod,
+		"remote_addr", addr,
+		"client", entry.client,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	)
+	return resp, err
+}
+
+// logEntry holds what inner interceptors learn about a call and the
+// logging interceptor reports once the call ends.
+type logEntry struct {
+	client string
+}
+
+type logEntryKey struct{}
+
+// setLogClient records the client of a call for the log line.
+func setLogClient(ctx context.Context, client string) {
+	if entry, ok := ctx.Value(logEntryKey{}).(*logEntry); ok {
+		entry.client = client
+	}
+}
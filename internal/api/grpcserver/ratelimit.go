@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+	passwordv1 "github.com/willherrera/itau-backend-challenge/pkg/api/password/v1"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// IPRateLimitInterceptor limits the calls to the password service from each
+// peer address. Chain it before AuthInterceptor, like the HTTP middleware.
+func IPRateLimitInterceptor(store ratelimit.Store, limit ratelimit.Limit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isPasswordService(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ip := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			ip = p.Addr.String()
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+		}
+		if err := rateLimit(ctx, store, ratelimit.AddressKey(ip), limit, "ip"); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ClientRateLimitInterceptor limits the calls of each authenticated client
+// to the limit of its tier. Chain it after AuthInterceptor.
+func ClientRateLimitInterceptor(store ratelimit.Store, tiers ratelimit.Tiers) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if client, ok := auth.FromContext(ctx); ok {
+			if err := rateLimit(ctx, store, "client:"+client.Name, tiers.Limit(client.Tier), "client"); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// rateLimit takes a token for key and returns a ResourceExhausted error,
+// with a retry-after header in seconds, when the bucket is empty.
+func rateLimit(ctx context.Context, store ratelimit.Store, key string, limit ratelimit.Limit, scope string) error {
+	if limit.Unlimited() {
+		return nil
+	}
+
+	decision, err := store.Take(ctx, key, limit, 1)
+	if err != nil {
+		slog.Error("Rate limit store error, letting the call through", "error", err)
+		return nil
+	}
+	if decision.Allowed {
+		return nil
+	}
+
+	retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+	metrics.RateLimitRejectedTotal.WithLabelValues(scope, auth.ClientName(ctx)).Inc()
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	return status.Error(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfter))
+}
+
+func isPasswordService(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+passwordv1.PasswordService_ServiceDesc.ServiceName+"/")
+}
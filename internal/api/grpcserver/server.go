@@ -0,0 +1,254 @@
+// Package grpcserver exposes the password service over gRPC, next to the
+// REST API. The protobuf definitions live in api/proto and the generated
+// code in pkg/api/password/v1.
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/observe"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	"github.com/willherrera/itau-backend-challenge/internal/i18n"
+	passwordv1 "github.com/willherrera/itau-backend-challenge/pkg/api/password/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// New creates a gRPC server with the password service, the standard health
+// checking service and server reflection. The returned health server
+// reports SERVING for the whole server and for password.v1.PasswordService;
+// callers can change that, e.g. during shutdown.
+func New(service *application.PasswordService, opts ...grpc.ServerOption) (*grpc.Server, *health.Server) {
+	opts = append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(LoggingInterceptor)}, opts...)
+	server := grpc.NewServer(opts...)
+
+	passwordv1.RegisterPasswordServiceServer(server, NewPasswordServer(service))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(passwordv1.PasswordService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server, healthServer
+}
+
+// PasswordServer implements passwordv1.PasswordServiceServer on top of
+// application.PasswordService, with the same behaviour as the REST handlers.
+type PasswordServer struct {
+	passwordv1.UnimplementedPasswordServiceServer
+
+	service *application.PasswordService
+}
+
+func NewPasswordServer(service *application.PasswordService) *PasswordServer {
+	return &PasswordServer{service: service}
+}
+
+func (s *PasswordServer) ValidatePassword(ctx context.Context, req *passwordv1.ValidatePasswordRequest) (*passwordv1.ValidatePasswordResponse, error) {
+	defer observe.TrackValidation()()
+
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+	subject := auth.Subject(ctx, req.GetSubjectId())
+	if len(subject) > history.MaxSubjectLength {
+		return nil, status.Error(codes.InvalidArgument, "subject_id is too long")
+	}
+
+	if err := authorizePolicy(ctx, req.GetPolicy()); err != nil {
+		return nil, err
+	}
+
+	ctx = domain.WithUserInfo(ctx, toUserInfo(req.GetUser()))
+	ctx = domain.WithSubject(ctx, subject)
+	result, err := s.service.ValidatePolicyContext(ctx, req.GetPolicy(), req.GetPassword())
+	return toValidateResponse(requestLocale(ctx, req.GetLocale()), req.GetPolicy(), result, err)
+}
+
+func (s *PasswordServer) ValidatePasswordChange(ctx context.Context, req *passwordv1.ValidatePasswordChangeRequest) (*passwordv1.ValidatePasswordResponse, error) {
+	defer observe.TrackValidation()()
+
+	if req.GetOldPassword() == "" || req.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "old_password and new_password are required")
+	}
+	subject := auth.Subject(ctx, req.GetSubjectId())
+	if len(subject) > history.MaxSubjectLength {
+		return nil, status.Error(codes.InvalidArgument, "subject_id is too long")
+	}
+
+	if err := authorizePolicy(ctx, req.GetPolicy()); err != nil {
+		return nil, err
+	}
+
+	ctx = domain.WithUserInfo(ctx, toUserInfo(req.GetUser()))
+	ctx = domain.WithSubject(ctx, subject)
+	result, err := s.service.ValidatePasswordChange(ctx, req.GetPolicy(), req.GetOldPassword(), req.GetNewPassword())
+	return toValidateResponse(requestLocale(ctx, req.GetLocale()), req.GetPolicy(), result, err)
+}
+
+func (s *PasswordServer) GetPolicy(ctx context.Context, req *passwordv1.GetPolicyRequest) (*passwordv1.GetPolicyResponse, error) {
+	name := req.GetPolicy()
+	if name == "" {
+		name = application.DefaultPolicy
+	}
+	if err := authorizePolicy(ctx, name); err != nil {
+		return nil, err
+	}
+
+	descriptions, err := s.service.Describe(name)
+	if errors.Is(err, application.ErrPolicyNotFound) {
+		return nil, status.Error(codes.NotFound, "unknown policy: "+name)
+	}
+
+	response := &passwordv1.GetPolicyResponse{Policy: name}
+	for _, d := range descriptions {
+		params, err := toStruct(d.Params)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "rule %s: %v", d.Rule, err)
+		}
+		response.Rules = append(response.Rules, &passwordv1.PolicyRule{
+			Rule:        d.Rule,
+			Codes:       d.Codes,
+			Description: d.Description,
+			Params:      params,
+		})
+	}
+	return response, nil
+}
+
+func (s *PasswordServer) ListPolicies(ctx context.Context, req *passwordv1.ListPoliciesRequest) (*passwordv1.ListPoliciesResponse, error) {
+	// Clients only see the policies their API key may use.
+	var policies []string
+	for _, name := range s.service.Policies() {
+		if authorizePolicy(ctx, name) == nil {
+			policies = append(policies, name)
+		}
+	}
+	return &passwordv1.ListPoliciesResponse{Policies: policies}, nil
+}
+
+// requestLocale returns the language of the violation messages (see
+// observe.Locale), negotiated from the "accept-language" metadata.
+func requestLocale(ctx context.Context, field string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return observe.Locale(field, md.Get("accept-language")...)
+}
+
+func toValidateResponse(locale, policy string, result *application.ValidationResult, err error) (*passwordv1.ValidatePasswordResponse, error) {
+	if errors.Is(err, application.ErrPolicyNotFound) {
+		return nil, status.Error(codes.NotFound, "unknown policy: "+policy)
+	}
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	observe.RecordValidation(result.IsValid, result.Violations)
+
+	localized := i18n.Localize(locale, result.Violations)
+	violations, err := toViolations(localized)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	messages := make([]string, len(localized))
+	for i, v := range localized {
+		messages[i] = v.Message
+	}
+	return &passwordv1.ValidatePasswordResponse{
+		IsValid:    result.IsValid,
+		Policy:     result.Policy,
+		Errors:     messages,
+		Violations: violations,
+		Strength:   toStrength(result.Strength),
+	}, nil
+}
+
+func toUserInfo(user *passwordv1.UserInfo) domain.UserInfo {
+	return domain.UserInfo{
+		Username:    user.GetUsername(),
+		Email:       user.GetEmail(),
+		FirstName:   user.GetFirstName(),
+		LastName:    user.GetLastName(),
+		CompanyName: user.GetCompanyName(),
+	}
+}
+
+func toViolations(violations []domain.Violation) ([]*passwordv1.Violation, error) {
+	out := make([]*passwordv1.Violation, 0, len(violations))
+	for _, v := range violations {
+		params, err := toStruct(v.Params)
+		if err != nil {
+			return nil, err
+		}
+		m := &passwordv1.Violation{
+			Code:    v.Code,
+			Rule:    v.Rule,
+			Message: v.Message,
+			Params:  params,
+			Char:    v.Char,
+		}
+		if v.Position != domain.NoPosition {
+			position := int32(v.Position)
+			m.Position = &position
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func toStrength(s strength.Result) *passwordv1.Strength {
+	return &passwordv1.Strength{
+		Score:        int32(s.Score),
+		Guesses:      s.Guesses,
+		GuessesLog10: s.GuessesLog10,
+		CrackTimesSeconds: &passwordv1.CrackTimesSeconds{
+			OnlineThrottling_100PerHour:      s.CrackTimes.OnlineThrottling,
+			OnlineNoThrottling_10PerSecond:   s.CrackTimes.OnlineNoThrottling,
+			OfflineSlowHashing_1E4PerSecond:  s.CrackTimes.OfflineSlowHashing,
+			OfflineFastHashing_1E10PerSecond: s.CrackTimes.OfflineFastHashing,
+		},
+		CrackTimeDisplay: s.CrackTimeDisplay,
+		Warning:          s.Warning,
+	}
+}
+
+// toStruct converts rule params to a protobuf Struct. Params are going to
+// be JSON anyway on the REST side, so a JSON round trip normalises the
+// types structpb does not accept directly, such as []string.
+func toStruct(params map[string]any) (*structpb.Struct, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(normalized)
+}
+
+// SetServing reports the whole server and the password service as SERVING
+// or NOT_SERVING on the health server returned by New.
+func SetServing(healthServer *health.Server, serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	healthServer.SetServingStatus("", status)
+	healthServer.SetServingStatus(passwordv1.PasswordService_ServiceDesc.ServiceName, status)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+)
+
+// authorizePolicy answers 403 Forbidden and returns false when the API key
+// of the request may not use policy. Requests without a key are served when
+// authentication is disabled.
+func (h *PasswordHandler) authorizePolicy(w http.ResponseWriter, r *http.Request, policy string) bool {
+	if policy == "" {
+		policy = application.DefaultPolicy
+	}
+	client, ok := auth.FromContext(r.Context())
+	if !ok || client.AllowsPolicy(policy) {
+		return true
+	}
+	h.sendError(w, http.StatusForbidden, "API key is not allowed to use policy: "+policy)
+	return false
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/api/observe"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+const (
+	// DefaultBatchMaxItems is the default limit of passwords per batch request.
+	DefaultBatchMaxItems = 1000
+
+	// maxBatchItemBytes bounds the request body to this many bytes per
+	// allowed item, so oversized bodies are rejected before being decoded.
+	maxBatchItemBytes = 4096
+)
+
+// SetBatchLimits sets the maximum number of items accepted by
+// ValidatePasswords and the number of workers validating each batch. A
+// workers value of zero uses GOMAXPROCS.
+func (h *PasswordHandler) SetBatchLimits(maxItems, workers int) {
+	h.batchMaxItems = maxItems
+	h.batchWorkers = workers
+}
+
+// ValidatePasswords handles POST /api/v1/validate-passwords requests.
+// @Summary Valida várias senhas em uma requisição
+// @Description Valida um lote de senhas com a mesma política, em paralelo com um número limitado de workers.
+// @Description Cada item pode ter um "id", devolvido no resultado correspondente; os resultados seguem a ordem dos itens.
+// @Description O tamanho máximo do lote é configurável (BATCH_MAX_ITEMS, padrão 1000).
+// @Description Cada senha do lote consome uma unidade do limite de senhas (RATE_LIMIT_ITEMS).
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "Idioma das mensagens (pt-BR, en, es)"
+// @Param request body models.ValidatePasswordsRequest true "Senhas a serem validadas"
+// @Success 200 {object} models.ValidatePasswordsResponse "Resultados da validação"
+// @Failure 400 {object} models.ErrorResponse "Requisição inválida"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
+// @Failure 413 {object} models.ErrorResponse "Lote maior que o limite"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições ou de senhas excedido"
+// @Failure 503 {object} models.ErrorResponse "Validação cancelada"
+// @Security APIKey
+// @Router /api/v1/validate-passwords [post]
+func (h *PasswordHandler) ValidatePasswords(w http.ResponseWriter, r *http.Request) {
+	defer observe.TrackValidation()()
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.batchMaxItems)*maxBatchItemBytes)
+
+	var req models.ValidatePasswordsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.sendError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Items) == 0 {
+		h.sendError(w, http.StatusBadRequest, "Items field is required")
+		return
+	}
+	if len(req.Items) > h.batchMaxItems {
+		h.sendError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("At most %d items are allowed per request", h.batchMaxItems))
+		return
+	}
+
+	items := make([]application.BatchItem, len(req.Items))
+	for i, item := range req.Items {
+		if item.Password == "" {
+			h.sendError(w, http.StatusBadRequest, fmt.Sprintf("items[%d]: Password field is required", i))
+			return
+		}
+		subject := auth.Subject(r.Context(), item.SubjectID)
+		if len(subject) > history.MaxSubjectLength {
+			h.sendError(w, http.StatusBadRequest, fmt.Sprintf("items[%d]: Subject ID is too long", i))
+			return
+		}
+		items[i] = application.BatchItem{
+			Password: item.Password,
+			Subject:  subject,
+			User:     item.UserInfo(),
+		}
+	}
+	if !h.authorizePolicy(w, r, req.Policy) {
+		return
+	}
+	// Unknown policies are answered before the item budget is charged.
+	if !h.service.HasPolicy(req.Policy) {
+		h.sendError(w, http.StatusNotFound, "Unknown policy: "+req.Policy)
+		return
+	}
+	if !h.takeItems(w, r, len(items)) {
+		return
+	}
+	metrics.BatchSize.Observe(float64(len(items)))
+
+	results, err := h.service.ValidateBatch(r.Context(), req.Policy, items, h.batchWorkers)
+	if errors.Is(err, application.ErrPolicyNotFound) {
+		h.sendError(w, http.StatusNotFound, "Unknown policy: "+req.Policy)
+		return
+	}
+	if err != nil {
+		h.sendError(w, http.StatusServiceUnavailable, "Validation was cancelled")
+		return
+	}
+
+	locale := requestLocale(r, req.Locale)
+	response := models.ValidatePasswordsResponse{
+		Policy:  results[0].Policy,
+		Total:   len(results),
+		Results: make([]models.ValidatePasswordResult, len(results)),
+	}
+	for i, result := range results {
+		observe.RecordValidation(result.IsValid, result.Violations)
+		if result.IsValid {
+			response.Valid++
+		} else {
+			response.Invalid++
+		}
+		messages, violations := localize(locale, result.Violations)
+		response.Results[i] = models.ValidatePasswordResult{
+			ID:         req.Items[i].ID,
+			IsValid:    result.IsValid,
+			Errors:     messages,
+			Violations: violations,
+			Strength:   toStrengthModel(result.Strength),
+		}
+	}
+
+	setContentLanguage(w, locale)
+	h.sendJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/generator"
+)
+
+// GeneratePassword handles POST /api/v1/generate-password requests.
+// @Summary Gera senhas que atendem à política
+// @Description Gera senhas aleatórias (crypto/rand) ou frases-senha a partir de uma lista de palavras embutida.
+// @Description Cada senha gerada é validada com todas as regras da política antes de ser devolvida.
+// @Description O corpo é opcional; sem ele é gerada uma senha aleatória de 16 caracteres para a política "default".
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param request body models.GeneratePasswordRequest false "Opções de geração"
+// @Success 200 {object} models.GeneratePasswordResponse "Senhas geradas"
+// @Failure 400 {object} models.ErrorResponse "Opções inválidas"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
+// @Failure 422 {object} models.ErrorResponse "A política não pode ser atendida com essas opções"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Failure 503 {object} models.ErrorResponse "Geração cancelada"
+// @Security APIKey
+// @Router /api/v1/generate-password [post]
+func (h *PasswordHandler) GeneratePassword(w http.ResponseWriter, r *http.Request) {
+	var req models.GeneratePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	if req.Count < 1 || req.Count > generator.MaxCount {
+		h.sendError(w, http.StatusBadRequest, "Count must be between 1 and 20")
+		return
+	}
+
+	opts := generator.Options{
+		Mode:             strings.ToLower(req.Mode),
+		Length:           req.Length,
+		Words:            req.Words,
+		ExcludeAmbiguous: req.ExcludeAmbiguous,
+	}
+	policy := req.Policy
+	if policy == "" {
+		policy = application.DefaultPolicy
+	}
+	if !h.authorizePolicy(w, r, policy) {
+		return
+	}
+
+	passwords, err := h.service.GeneratePasswords(r.Context(), policy, opts, req.Count)
+	switch {
+	case err == nil:
+	case errors.Is(err, application.ErrPolicyNotFound):
+		h.sendError(w, http.StatusNotFound, "Unknown policy: "+policy)
+		return
+	case errors.Is(err, generator.ErrInvalidOptions):
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, generator.ErrUnsatisfiable):
+		h.sendError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	default:
+		h.sendError(w, http.StatusServiceUnavailable, "Generation was cancelled")
+		return
+	}
+
+	// Generated passwords are secrets; keep them out of shared caches.
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSON(w, http.StatusOK, models.GeneratePasswordResponse{
+		Policy:    policy,
+		Passwords: passwords,
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/health"
+)
+
+const serviceName = "password-validator"
+
+// HealthHandler serves the health endpoints from a probe. They do not
+// depend on the password service, so they answer while it is still loading.
+type HealthHandler struct {
+	probe *health.Probe
+}
+
+// NewHealthHandler reports the state of probe; with a nil probe the service
+// is always ready.
+func NewHealthHandler(probe *health.Probe) *HealthHandler {
+	return &HealthHandler{probe: probe}
+}
+
+// Live handles GET /health/live requests.
+// @Summary Liveness
+// @Description Indica que o processo está de pé e respondendo. Não depende do carregamento das políticas
+// @Description nem do desligamento: uma falha aqui significa que o processo deve ser reiniciado.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "Processo respondendo"
+// @Router /health/live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.HealthResponse{Status: "alive", Service: serviceName})
+}
+
+// Ready handles GET /health/ready requests.
+// @Summary Readiness
+// @Description Indica se a API pode receber tráfego. Responde 503 enquanto as políticas e dicionários
+// @Description são carregados e durante o desligamento, com os motivos em "checks".
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "Pronta para receber tráfego"
+// @Failure 503 {object} models.HealthResponse "Carregando ou desligando"
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ready, reasons := h.probe.Status()
+	if !ready {
+		writeJSON(w, http.StatusServiceUnavailable, models.HealthResponse{Status: "unhealthy", Service: serviceName, Checks: reasons})
+		return
+	}
+	writeJSON(w, http.StatusOK, models.HealthResponse{Status: "healthy", Service: serviceName})
+}
+
+// Health handles GET /health requests, kept for existing clients; it
+// reports readiness.
+// @Summary Health check
+// @Description Verifica se a API está pronta para receber tráfego (equivalente a /health/ready)
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "API está saudável"
+// @Failure 503 {object} models.HealthResponse "Carregando ou desligando"
+// @Router /health [get]
+func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	h.Ready(w, r)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+)
+
+// maxHistoryBodyBytes bounds the body of history requests, which hold a
+// single password.
+const maxHistoryBodyBytes = 4096
+
+// RecordPasswordHistory handles POST /api/v1/subjects/{id}/history requests.
+// @Summary Registra uma senha no histórico
+// @Description Registra a senha atual do sujeito após uma troca bem-sucedida. Apenas um hash lento
+// @Description (argon2id ou bcrypt) é armazenado, e somente as últimas N senhas são mantidas.
+// @Description O histórico de cada sujeito pertence ao cliente (chave de API) que o gravou.
+// @Tags History
+// @Accept json
+// @Param id path string true "Identificador do sujeito (usuário ou conta)"
+// @Param request body models.RecordPasswordRequest true "Senha a registrar"
+// @Success 204 "Senha registrada"
+// @Failure 400 {object} models.ErrorResponse "Requisição inválida ou senha maior que 72 bytes com bcrypt"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Failure 500 {object} models.ErrorResponse "Falha ao gravar o histórico"
+// @Failure 501 {object} models.ErrorResponse "Histórico de senhas desabilitado"
+// @Security APIKey
+// @Router /api/v1/subjects/{id}/history [post]
+func (h *PasswordHandler) RecordPasswordHistory(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxHistoryBodyBytes)
+
+	var req models.RecordPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Password == "" {
+		h.sendError(w, http.StatusBadRequest, "Password field is required")
+		return
+	}
+
+	subject := auth.Subject(r.Context(), mux.Vars(r)["id"])
+	err := h.service.RecordPassword(r.Context(), subject, req.Password)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, application.ErrHistoryDisabled):
+		h.sendError(w, http.StatusNotImplemented, "Password history is not enabled")
+	case errors.Is(err, history.ErrInvalidSubject):
+		h.sendError(w, http.StatusBadRequest, "Subject ID is too long")
+	case errors.Is(err, history.ErrPasswordTooLong):
+		h.sendError(w, http.StatusBadRequest, "Password is too long to be hashed with bcrypt (72 bytes)")
+	default:
+		slog.Error("Failed to record password history", "error", err)
+		h.sendError(w, http.StatusInternalServerError, "Password history could not be recorded")
+	}
+}
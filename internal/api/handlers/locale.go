@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/api/observe"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/i18n"
+)
+
+// requestLocale returns the language of the violation messages sent back
+// (see observe.Locale).
+func requestLocale(r *http.Request, field string) string {
+	return observe.Locale(field, r.Header.Values("Accept-Language")...)
+}
+
+// setContentLanguage announces the language of the messages in a response.
+func setContentLanguage(w http.ResponseWriter, locale string) {
+	w.Header().Set("Content-Language", locale)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// localize returns the violations of a result in locale, together with the
+// legacy errors field, which repeats their messages.
+func localize(locale string, violations []domain.Violation) ([]string, []models.Violation) {
+	localized := i18n.Localize(locale, violations)
+	messages := make([]string, len(localized))
+	for i, v := range localized {
+		messages[i] = v.Message
+	}
+	return messages, toViolationModels(localized)
+}
@@ -2,43 +2,92 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/api/observe"
 	"github.com/willherrera/itau-backend-challenge/internal/application"
-	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
 )
 
 type PasswordHandler struct {
 	service *application.PasswordService
+
+	batchMaxItems      int
+	batchWorkers       int
+	streamMaxLineBytes int
+	streamMaxLines     int
+	streamLineTimeout  time.Duration
+	streamMaxDuration  time.Duration
 }
 
 func NewPasswordHandler(service *application.PasswordService) *PasswordHandler {
 	return &PasswordHandler{
-		service: service,
+		service:            service,
+		batchMaxItems:      DefaultBatchMaxItems,
+		streamMaxLineBytes: DefaultStreamMaxLineBytes,
+		streamMaxLines:     DefaultStreamMaxLines,
+		streamLineTimeout:  DefaultStreamLineTimeout,
+		streamMaxDuration:  DefaultStreamMaxDuration,
 	}
 }
 
 // ValidatePassword handles POST /api/v1/validate-password requests.
 // @Summary Valida uma senha
-// @Description Valida se uma senha atende a todos os critérios de segurança definidos
+// @Description Valida se uma senha atende a todos os critérios de segurança definidos.
+// @Description O campo opcional "policy" seleciona uma política nomeada; sem ele é usada a política "default".
+// @Description O campo opcional subjectId ativa a verificação de reuso quando o histórico de senhas está habilitado.
+// @Description Os campos opcionais username, email, firstName, lastName e companyName são usados pela regra personal_info.
+// @Description As mensagens das violações seguem o campo "locale" ou o cabeçalho Accept-Language (pt-BR, en ou es; padrão en).
 // @Tags Password
 // @Accept json
 // @Produce json
+// @Param Accept-Language header string false "Idioma das mensagens (pt-BR, en, es)"
 // @Param request body models.ValidatePasswordRequest true "Senha a ser validada"
 // @Success 200 {object} models.ValidatePasswordResponse "Resultado da validação"
 // @Failure 400 {object} models.ErrorResponse "Requisição inválida"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
 // @Failure 405 {object} models.ErrorResponse "Método não permitido"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Failure 503 {object} models.ErrorResponse "Validação cancelada"
+// @Security APIKey
 // @Router /api/v1/validate-password [post]
 func (h *PasswordHandler) ValidatePassword(w http.ResponseWriter, r *http.Request) {
-	start := time.Now()
-	metrics.InProgress.Inc()
-	defer func() {
-		metrics.InProgress.Dec()
-		metrics.RequestDuration.Observe(time.Since(start).Seconds())
-	}()
+	h.validate(w, r, "")
+}
+
+// ValidatePasswordWithPolicy handles POST /api/v1/policies/{name}/validate requests.
+// @Summary Valida uma senha com uma política específica
+// @Description Valida a senha usando a política nomeada no caminho (ex.: default, admin, service-account)
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param name path string true "Nome da política"
+// @Param Accept-Language header string false "Idioma das mensagens (pt-BR, en, es)"
+// @Param request body models.ValidatePasswordRequest true "Senha a ser validada"
+// @Success 200 {object} models.ValidatePasswordResponse "Resultado da validação"
+// @Failure 400 {object} models.ErrorResponse "Requisição inválida"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Security APIKey
+// @Router /api/v1/policies/{name}/validate [post]
+func (h *PasswordHandler) ValidatePasswordWithPolicy(w http.ResponseWriter, r *http.Request) {
+	h.validate(w, r, mux.Vars(r)["name"])
+}
+
+func (h *PasswordHandler) validate(w http.ResponseWriter, r *http.Request, policy string) {
+	defer observe.TrackValidation()()
 
 	var req models.ValidatePasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -51,34 +100,187 @@ func (h *PasswordHandler) ValidatePassword(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	result := h.service.Validate(req.Password)
-	metrics.RecordValidation(result.IsValid, result.Errors)
+	if policy != "" && req.Policy != "" && req.Policy != policy {
+		h.sendError(w, http.StatusBadRequest, "Policy field does not match the policy in the URL")
+		return
+	}
+	if policy == "" {
+		policy = req.Policy
+	}
+	if !h.authorizePolicy(w, r, policy) {
+		return
+	}
+
+	subject := auth.Subject(r.Context(), req.SubjectID)
+	if len(subject) > history.MaxSubjectLength {
+		h.sendError(w, http.StatusBadRequest, "Subject ID is too long")
+		return
+	}
+
+	ctx := domain.WithUserInfo(r.Context(), req.UserInfo())
+	ctx = domain.WithSubject(ctx, subject)
+	result, err := h.service.ValidatePolicyContext(ctx, policy, req.Password)
+	h.sendResult(w, requestLocale(r, req.Locale), policy, result, err)
+}
+
+// ValidatePasswordChange handles POST /api/v1/validate-password-change requests.
+// @Summary Valida uma troca de senha
+// @Description Valida a nova senha com as regras da política e a compara com a senha atual,
+// @Description rejeitando variações pequenas (ex.: Summer2024! → Summer2025!) com o código SIMILAR_TO_PREVIOUS.
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "Idioma das mensagens (pt-BR, en, es)"
+// @Param request body models.ValidatePasswordChangeRequest true "Senha atual e nova senha"
+// @Success 200 {object} models.ValidatePasswordResponse "Resultado da validação da nova senha"
+// @Failure 400 {object} models.ErrorResponse "Requisição inválida"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Failure 503 {object} models.ErrorResponse "Validação cancelada"
+// @Security APIKey
+// @Router /api/v1/validate-password-change [post]
+func (h *PasswordHandler) ValidatePasswordChange(w http.ResponseWriter, r *http.Request) {
+	defer observe.TrackValidation()()
+
+	var req models.ValidatePasswordChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
 
+	if req.OldPassword == "" || req.NewPassword == "" {
+		h.sendError(w, http.StatusBadRequest, "oldPassword and newPassword fields are required")
+		return
+	}
+	subject := auth.Subject(r.Context(), req.SubjectID)
+	if len(subject) > history.MaxSubjectLength {
+		h.sendError(w, http.StatusBadRequest, "Subject ID is too long")
+		return
+	}
+	if !h.authorizePolicy(w, r, req.Policy) {
+		return
+	}
+
+	ctx := domain.WithUserInfo(r.Context(), req.UserInfo())
+	ctx = domain.WithSubject(ctx, subject)
+	result, err := h.service.ValidatePasswordChange(ctx, req.Policy, req.OldPassword, req.NewPassword)
+	h.sendResult(w, requestLocale(r, req.Locale), req.Policy, result, err)
+}
+
+func (h *PasswordHandler) sendResult(w http.ResponseWriter, locale, policy string, result *application.ValidationResult, err error) {
+	if errors.Is(err, application.ErrPolicyNotFound) {
+		h.sendError(w, http.StatusNotFound, "Unknown policy: "+policy)
+		return
+	}
+	if err != nil {
+		h.sendError(w, http.StatusServiceUnavailable, "Validation was cancelled")
+		return
+	}
+
+	observe.RecordValidation(result.IsValid, result.Violations)
+
+	messages, violations := localize(locale, result.Violations)
+	setContentLanguage(w, locale)
 	h.sendJSON(w, http.StatusOK, models.ValidatePasswordResponse{
-		IsValid: result.IsValid,
-		Errors:  result.Errors,
+		IsValid:    result.IsValid,
+		Policy:     result.Policy,
+		Errors:     messages,
+		Violations: violations,
+		Strength:   toStrengthModel(result.Strength),
 	})
 }
 
-// Health handles GET /health requests.
-// @Summary Health check
-// @Description Verifica se a API está funcionando corretamente
-// @Tags Health
+// GetPolicy handles GET /api/v1/policy requests.
+// @Summary Regras da política ativa
+// @Description Lista as regras da política em uso, com códigos de violação, descrições e parâmetros,
+// @Description geradas a partir dos validadores configurados. Use para montar checklists de senha no cliente.
+// @Tags Policy
 // @Produce json
-// @Success 200 {object} models.HealthResponse "API está saudável"
-// @Router /health [get]
-func (h *PasswordHandler) Health(w http.ResponseWriter, r *http.Request) {
-	h.sendJSON(w, http.StatusOK, models.HealthResponse{
-		Status:  "healthy",
-		Service: "password-validator",
+// @Param policy query string false "Nome da política (padrão: default)"
+// @Success 200 {object} models.PolicyResponse "Regras da política"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Security APIKey
+// @Router /api/v1/policy [get]
+func (h *PasswordHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("policy")
+	if name == "" {
+		name = application.DefaultPolicy
+	}
+	if !h.authorizePolicy(w, r, name) {
+		return
+	}
+
+	descriptions, err := h.service.Describe(name)
+	if errors.Is(err, application.ErrPolicyNotFound) {
+		h.sendError(w, http.StatusNotFound, "Unknown policy: "+name)
+		return
+	}
+
+	rules := make([]models.PolicyRule, 0, len(descriptions))
+	for _, d := range descriptions {
+		rules = append(rules, models.PolicyRule{
+			Rule:        d.Rule,
+			Codes:       d.Codes,
+			Description: d.Description,
+			Params:      d.Params,
+		})
+	}
+
+	h.sendJSON(w, http.StatusOK, models.PolicyResponse{
+		Policy: name,
+		Rules:  rules,
 	})
 }
 
+func toViolationModels(violations []domain.Violation) []models.Violation {
+	out := make([]models.Violation, 0, len(violations))
+	for _, v := range violations {
+		m := models.Violation{
+			Code:    v.Code,
+			Rule:    v.Rule,
+			Message: v.Message,
+			Params:  v.Params,
+			Char:    v.Char,
+		}
+		if v.Position != domain.NoPosition {
+			position := v.Position
+			m.Position = &position
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func toStrengthModel(s strength.Result) *models.Strength {
+	return &models.Strength{
+		Score:        s.Score,
+		Guesses:      s.Guesses,
+		GuessesLog10: s.GuessesLog10,
+		CrackTimesSeconds: models.CrackTimesSeconds{
+			OnlineThrottling:   s.CrackTimes.OnlineThrottling,
+			OnlineNoThrottling: s.CrackTimes.OnlineNoThrottling,
+			OfflineSlowHashing: s.CrackTimes.OfflineSlowHashing,
+			OfflineFastHashing: s.CrackTimes.OfflineFastHashing,
+		},
+		CrackTimeDisplay: s.CrackTimeDisplay,
+		Warning:          s.Warning,
+	}
+}
+
 func (h *PasswordHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Failed to encode JSON response: %v", err)
+		slog.Error("Failed to encode JSON response", "error", err)
 	}
 }
 
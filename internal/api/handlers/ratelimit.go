@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// takeItems takes n tokens from the item budget of the request. When there
+// are not enough, it answers 429 Too Many Requests and returns false.
+// Requests without a budget are not limited.
+func (h *PasswordHandler) takeItems(w http.ResponseWriter, r *http.Request, n int) bool {
+	budget, ok := ratelimit.FromContext(r.Context())
+	if !ok {
+		return true
+	}
+	decision, err := budget.Take(r.Context(), n)
+	if err != nil {
+		slog.Error("Rate limit store error, letting the request through", "error", err)
+		return true
+	}
+	if decision.Allowed {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+	metrics.RateLimitRejectedTotal.WithLabelValues("items", auth.ClientName(r.Context())).Inc()
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	h.sendError(w, http.StatusTooManyRequests, fmt.Sprintf("Password rate limit exceeded, %d passwords can be validated in %d seconds", n, retryAfter))
+	return false
+}
+
+// waitItem takes a token from the item budget of ctx, waiting for one when
+// the bucket is empty, so that a stream slows down to the rate limit
+// instead of failing half-way.
+func waitItem(ctx context.Context) error {
+	budget, ok := ratelimit.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for {
+		decision, err := budget.Take(ctx, 1)
+		if err != nil {
+			slog.Error("Rate limit store error, letting the item through", "error", err)
+			return nil
+		}
+		if decision.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(decision.RetryAfter)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
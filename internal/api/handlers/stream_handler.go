@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/api/observe"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+
+	// DefaultStreamMaxLineBytes is the default limit of one NDJSON line.
+	DefaultStreamMaxLineBytes = 4096
+
+	// DefaultStreamMaxLines is the default limit of lines per stream.
+	DefaultStreamMaxLines = 100000
+
+	// DefaultStreamLineTimeout is the default time allowed to receive each
+	// line of a stream and to write each result.
+	DefaultStreamLineTimeout = 30 * time.Second
+
+	// DefaultStreamMaxDuration is the default time a stream is read for.
+	DefaultStreamMaxDuration = 10 * time.Minute
+)
+
+var (
+	errLineTooLong  = errors.New("line too long")
+	errTooManyLines = errors.New("too many lines")
+)
+
+// SetStreamMaxLineBytes sets the longest line ValidatePasswordStream accepts.
+// Longer lines are skipped and reported as errors.
+func (h *PasswordHandler) SetStreamMaxLineBytes(n int) {
+	h.streamMaxLineBytes = n
+}
+
+// SetStreamMaxLines sets the number of lines ValidatePasswordStream reads
+// from one request. The rest of the body is ignored and the summary reports
+// the stream as cut short.
+func (h *PasswordHandler) SetStreamMaxLines(n int) {
+	h.streamMaxLines = n
+}
+
+// SetStreamTimeouts sets the time ValidatePasswordStream waits for each line
+// of the body, and for each result to be written, and the time after which
+// it stops reading the body altogether. Either way the summary reports the
+// stream as cut short.
+func (h *PasswordHandler) SetStreamTimeouts(lineTimeout, maxDuration time.Duration) {
+	h.streamLineTimeout = lineTimeout
+	h.streamMaxDuration = maxDuration
+}
+
+// ValidatePasswordStream handles POST /api/v1/validate-passwords/stream requests.
+// @Summary Valida senhas em fluxo (NDJSON)
+// @Description Recebe um corpo application/x-ndjson com um objeto {"id","password"} por linha e devolve, também em NDJSON,
+// @Description um resultado por linha assim que cada senha é validada (a ordem pode diferir da entrada; use "line" ou "id").
+// @Description O corpo nunca é carregado inteiro em memória. Linhas inválidas ou maiores que o limite (STREAM_MAX_LINE_BYTES)
+// @Description geram uma linha com "error" e não interrompem o fluxo. A última linha traz o resumo com contagens por regra.
+// @Description O fluxo lê no máximo STREAM_MAX_LINES linhas (padrão 100000) e é desacelerado ao limite de senhas (RATE_LIMIT_ITEMS).
+// @Description A leitura é encerrada se nenhuma linha chegar em STREAM_LINE_TIMEOUT (padrão 30s) ou após STREAM_MAX_DURATION (padrão 10m).
+// @Tags Password
+// @Accept application/x-ndjson
+// @Produce application/x-ndjson
+// @Param policy query string false "Nome da política (padrão: default)"
+// @Param locale query string false "Idioma das mensagens (pt-BR, en, es); tem precedência sobre Accept-Language"
+// @Param Accept-Language header string false "Idioma das mensagens (pt-BR, en, es)"
+// @Param request body models.ValidatePasswordItem true "Uma senha por linha"
+// @Success 200 {object} models.ValidatePasswordStreamResult "Um resultado por linha, seguido de models.ValidatePasswordStreamSummary"
+// @Failure 401 {object} models.ErrorResponse "Chave de API ausente ou inválida"
+// @Failure 403 {object} models.ErrorResponse "Chave de API sem acesso à política"
+// @Failure 404 {object} models.ErrorResponse "Política não encontrada"
+// @Failure 415 {object} models.ErrorResponse "Content-Type diferente de application/x-ndjson"
+// @Failure 429 {object} models.ErrorResponse "Limite de requisições excedido"
+// @Security APIKey
+// @Router /api/v1/validate-passwords/stream [post]
+func (h *PasswordHandler) ValidatePasswordStream(w http.ResponseWriter, r *http.Request) {
+	defer observe.TrackValidation()()
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != ndjsonContentType {
+		h.sendError(w, http.StatusUnsupportedMediaType, "Content-Type must be "+ndjsonContentType)
+		return
+	}
+
+	// net/http cancels the request context when reading the body fails,
+	// which includes the read deadlines set below; the summary must still
+	// be written then. A client that goes away is noticed by the failed
+	// reads and writes instead.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+
+	policy := r.URL.Query().Get("policy")
+	if policy == "" {
+		policy = application.DefaultPolicy
+	}
+	if !h.authorizePolicy(w, r, policy) {
+		return
+	}
+
+	in := make(chan application.BatchItem)
+	results, err := h.service.ValidateStream(ctx, policy, in, h.batchWorkers)
+	if errors.Is(err, application.ErrPolicyNotFound) {
+		h.sendError(w, http.StatusNotFound, "Unknown policy: "+policy)
+		return
+	}
+
+	// Results are written while the body is still being uploaded. Instead
+	// of the server timeouts, which would cut long streams, each line must
+	// arrive and each result be written within streamLineTimeout, and the
+	// body is read for at most streamMaxDuration.
+	rc := http.NewResponseController(w)
+	_ = rc.EnableFullDuplex()
+	readUntil := time.Now().Add(h.streamMaxDuration)
+	nextLine := func() {
+		deadline := time.Now().Add(h.streamLineTimeout)
+		if deadline.After(readUntil) {
+			deadline = readUntil
+		}
+		_ = rc.SetReadDeadline(deadline)
+	}
+	nextLine()
+
+	locale := requestLocale(r, r.URL.Query().Get("locale"))
+	setContentLanguage(w, locale)
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	rejected := make(chan models.ValidatePasswordStreamResult)
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- h.readStream(ctx, r.Body, in, rejected, nextLine)
+	}()
+
+	summary := models.StreamSummary{Policy: policy, Violations: map[string]int{}}
+	enc := json.NewEncoder(w)
+	write := func(v any) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(h.streamLineTimeout))
+		if err := enc.Encode(v); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	for results != nil || rejected != nil {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			observe.RecordValidation(result.IsValid, result.Violations)
+			summary.Total++
+			if result.IsValid {
+				summary.Valid++
+			} else {
+				summary.Invalid++
+				failed := make(map[string]bool)
+				for _, v := range result.Violations {
+					if !failed[v.Rule] {
+						failed[v.Rule] = true
+						summary.Violations[v.Rule]++
+					}
+				}
+			}
+
+			messages, violations := localize(locale, result.Violations)
+			if !write(models.ValidatePasswordStreamResult{
+				Line:       result.Seq,
+				ID:         result.ID,
+				IsValid:    result.IsValid,
+				Errors:     messages,
+				Violations: violations,
+				Strength:   toStrengthModel(result.Strength),
+			}) {
+				cancel()
+			}
+		case line, ok := <-rejected:
+			if !ok {
+				rejected = nil
+				continue
+			}
+			summary.Total++
+			summary.Rejected++
+			if !write(line) {
+				cancel()
+			}
+		case <-ctx.Done():
+			// A write failed: the client went away or stopped reading, so
+			// nobody is left to receive the rest. The reader stops once
+			// the body is closed.
+			return
+		}
+	}
+
+	err = <-readErr
+	switch {
+	case errors.Is(err, errTooManyLines):
+		summary.Error = fmt.Sprintf("Only the first %d lines were read", h.streamMaxLines)
+	case errors.Is(err, os.ErrDeadlineExceeded) && !time.Now().Before(readUntil):
+		summary.Error = fmt.Sprintf("Only the lines received in the first %s were read", h.streamMaxDuration)
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		summary.Error = fmt.Sprintf("No line was received for %s", h.streamLineTimeout)
+	case err != nil:
+		slog.Warn("Failed to read password stream", "error", err)
+		summary.Error = "Request body could not be read to the end"
+	}
+	metrics.BatchSize.Observe(float64(summary.Total))
+	write(models.ValidatePasswordStreamSummary{Summary: summary})
+}
+
+// readStream decodes one item per line of body and sends it to in, taking a
+// token from the item budget for each, or sends the reason the line was
+// skipped to rejected. nextLine is called before each line is read. It
+// closes both channels when the body ends, after streamMaxLines lines or
+// when ctx is done.
+func (h *PasswordHandler) readStream(ctx context.Context, body io.Reader, in chan<- application.BatchItem, rejected chan<- models.ValidatePasswordStreamResult, nextLine func()) error {
+	defer close(in)
+	defer close(rejected)
+
+	reader := bufio.NewReader(body)
+	for lineNo := 1; ; lineNo++ {
+		if lineNo > h.streamMaxLines {
+			return errTooManyLines
+		}
+		nextLine()
+		line, err := readLine(reader, h.streamMaxLineBytes)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var item models.ValidatePasswordItem
+		var reason string
+		switch {
+		case errors.Is(err, errLineTooLong):
+			reason = fmt.Sprintf("line is longer than %d bytes", h.streamMaxLineBytes)
+		case err != nil:
+			return err
+		case len(bytes.TrimSpace(line)) == 0:
+			continue
+		case json.Unmarshal(line, &item) != nil:
+			reason = "Invalid JSON"
+		case item.Password == "":
+			reason = "Password field is required"
+		case len(auth.Subject(ctx, item.SubjectID)) > history.MaxSubjectLength:
+			reason = "Subject ID is too long"
+		}
+
+		if reason != "" {
+			select {
+			case rejected <- models.ValidatePasswordStreamResult{Line: lineNo, ID: item.ID, Error: reason}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		if err := waitItem(ctx); err != nil {
+			return err
+		}
+		select {
+		case in <- application.BatchItem{
+			ID:       item.ID,
+			Seq:      lineNo,
+			Password: item.Password,
+			Subject:  auth.Subject(ctx, item.SubjectID),
+			User:     item.UserInfo(),
+		}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// readLine returns the next line of r without its line terminator. A line
+// longer than limit bytes is consumed without being buffered and reported
+// with errLineTooLong. It returns io.EOF only when no data is left.
+func readLine(r *bufio.Reader, limit int) ([]byte, error) {
+	var line []byte
+	read, tooLong := false, false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		read = read || len(chunk) > 0
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(bytes.TrimRight(line, "\r\n")) > limit {
+				tooLong, line = true, nil
+			}
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) && read {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tooLong {
+			return nil, errLineTooLong
+		}
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+)
+
+// APIKeyHeader is the request header that carries the API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth rejects requests without a known API key with 401 Unauthorized
+// and stores the client that owns the key in the request context, where
+// handlers check which policies it may use. A verified TLS client
+// certificate whose identity is listed in keys authenticates the request
+// without an API key.
+func APIKeyAuth(keys *auth.Keys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+				if client, ok := keys.AuthenticateCertificate(r.TLS.PeerCertificates[0]); ok {
+					setLogClient(r, client.Name)
+					next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), client)))
+					return
+				}
+			}
+
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				unauthorized(w, "Missing API key: send it in the "+APIKeyHeader+" header")
+				return
+			}
+			client, ok := keys.Authenticate(key)
+			if !ok {
+				unauthorized(w, "Invalid API key")
+				return
+			}
+
+			setLogClient(r, client.Name)
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), client)))
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `APIKey header="`+APIKeyHeader+`"`)
+	sendError(w, http.StatusUnauthorized, message)
+}
+
+// sendError answers with an ErrorResponse, like the handlers do.
+func sendError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
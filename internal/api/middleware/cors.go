@@ -1,20 +1,249 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+// corsMethods are the methods of the API; browsers may preflight any of
+// them.
+var corsMethods = []string{http.MethodGet, http.MethodPost}
 
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return
+// CORSPolicy says which cross-origin requests browsers may make to the API.
+// The zero value allows none.
+type CORSPolicy struct {
+	// AllowedOrigins lists exact origins, such as https://app.example.com,
+	// and wildcard subdomain patterns, such as https://*.example.com, which
+	// match any subdomain but not example.com itself. Schemes and ports
+	// must match exactly. "*" allows any origin.
+	AllowedOrigins []string
+
+	// AllowCredentials lets browsers send cookies and client certificates
+	// and read the response. It cannot be combined with "*".
+	AllowCredentials bool
+
+	// AllowedHeaders are the request headers browsers may send, besides
+	// the CORS-safelisted ones.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers browser scripts may read,
+	// besides the CORS-safelisted ones.
+	ExposedHeaders []string
+
+	// MaxAge is how long browsers may cache a preflight response; zero or
+	// less leaves it to the browser.
+	MaxAge time.Duration
+}
+
+// Validate reports origins that are neither "*", an exact origin nor a
+// wildcard subdomain pattern, and "*" with credentials.
+func (p CORSPolicy) Validate() error {
+	var errs []error
+	for _, origin := range p.AllowedOrigins {
+		if origin == "*" {
+			if p.AllowCredentials {
+				errs = append(errs, errors.New(`origin "*" cannot be combined with credentials; list the origins`))
+			}
+			continue
+		}
+		if err := validateOrigin(origin); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateOrigin accepts scheme://host[:port], where host may start with
+// "*." to match its subdomains.
+func validateOrigin(origin string) error {
+	invalid := fmt.Errorf("invalid origin %q: want scheme://host[:port], such as https://app.example.com or https://*.example.com", origin)
+
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return invalid
+	}
+	if rest, wildcard := strings.CutPrefix(host, "*."); wildcard {
+		host = rest
+	}
+	u, err := url.Parse(scheme + "://" + host)
+	if err != nil || u.Host != host || u.Hostname() == "" || strings.Contains(host, "*") || strings.ContainsAny(host, "@/?#") {
+		return invalid
+	}
+	return nil
+}
+
+// corsMatcher matches request origins against the allowed ones.
+type corsMatcher struct {
+	any       bool
+	exact     []string
+	wildcards []wildcardOrigin
+}
+
+// wildcardOrigin is https://*.example.com, split into "https://" and
+// ".example.com".
+type wildcardOrigin struct {
+	prefix, suffix string
+}
+
+func newCORSMatcher(origins []string) corsMatcher {
+	var m corsMatcher
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			m.any = true
+		} else if scheme, host, ok := strings.Cut(origin, "://*."); ok {
+			m.wildcards = append(m.wildcards, wildcardOrigin{prefix: scheme + "://", suffix: "." + host})
+		} else {
+			m.exact = append(m.exact, origin)
 		}
+	}
+	return m
+}
 
-		next.ServeHTTP(w, r)
-	})
+// allows reports whether a request from origin may read responses.
+func (m corsMatcher) allows(origin string) bool {
+	if m.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if slices.Contains(m.exact, origin) {
+		return true
+	}
+	for _, w := range m.wildcards {
+		rest, ok := strings.CutPrefix(origin, w.prefix)
+		if !ok {
+			continue
+		}
+		if sub, ok := strings.CutSuffix(rest, w.suffix); ok && isSubdomain(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubdomain reports whether s is one or more DNS labels, such as "app" or
+// "eu.app".
+func isSubdomain(s string) bool {
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, c := range label {
+			if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CORS applies policy: responses to allowed origins carry the CORS headers,
+// preflight requests from allowed origins for allowed methods and headers
+// are answered with 204 No Content, and every other preflight is rejected
+// with 403 Forbidden. Requests from other origins are still served, without
+// CORS headers, so that browsers keep their responses from the calling
+// page. The policy must be valid; see CORSPolicy.Validate.
+func CORS(policy CORSPolicy) func(http.Handler) http.Handler {
+	matcher := newCORSMatcher(policy.AllowedOrigins)
+	// A literal "*" only works without credentials; otherwise the origin
+	// is echoed and the response varies with it.
+	wildcardResponse := matcher.any && !policy.AllowCredentials
+	allowedHeaders := make(map[string]bool, len(policy.AllowedHeaders))
+	for _, header := range policy.AllowedHeaders {
+		allowedHeaders[http.CanonicalHeaderKey(header)] = true
+	}
+	methods := strings.Join(corsMethods, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if !wildcardResponse {
+				h.Add("Vary", "Origin")
+			}
+
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" {
+				if r.Method == http.MethodOptions {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed := matcher.allows(origin)
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				if !allowed {
+					sendError(w, http.StatusForbidden, "Origin not allowed: "+origin)
+					return
+				}
+				if method := r.Header.Get("Access-Control-Request-Method"); !slices.Contains(corsMethods, method) {
+					sendError(w, http.StatusForbidden, "Method not allowed by CORS policy: "+method)
+					return
+				}
+				for _, header := range requestedHeaders(r) {
+					if !allowedHeaders[http.CanonicalHeaderKey(header)] {
+						sendError(w, http.StatusForbidden, "Header not allowed by CORS policy: "+header)
+						return
+					}
+				}
+			}
+
+			if allowed {
+				if wildcardResponse {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Set("Access-Control-Allow-Origin", origin)
+				}
+				if policy.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+				if len(policy.ExposedHeaders) > 0 && !preflight {
+					h.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+				}
+			}
+
+			if preflight {
+				h.Set("Access-Control-Allow-Methods", methods)
+				if len(policy.AllowedHeaders) > 0 {
+					h.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+				}
+				if policy.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestedHeaders lists the headers of an Access-Control-Request-Headers
+// preflight header.
+func requestedHeaders(r *http.Request) []string {
+	var headers []string
+	for _, value := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+	}
+	return headers
 }
@@ -1,11 +1,19 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
 )
 
+// LoggingMiddleware logs each request with the client that made it and
+// counts it in the API request metrics.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -14,21 +22,44 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 			ResponseWriter: w,
 			statusCode:     http.StatusOK,
 		}
+		entry := &logEntry{client: auth.Anonymous}
 
-		next.ServeHTTP(lrw, r)
+		next.ServeHTTP(lrw, r.WithContext(context.WithValue(r.Context(), logEntryKey{}, entry)))
 
-		duration := time.Since(start)
-		log.Printf(
-			"[%s] %s %s - Status: %d - Duration: %v",
-			r.Method,
-			r.RequestURI,
-			r.RemoteAddr,
-			lrw.statusCode,
-			duration,
+		slog.Info("HTTP request",
+			"method", r.Method,
+			"uri", r.RequestURI,
+			"remote_addr", r.RemoteAddr,
+			"client", entry.client,
+			"status", lrw.statusCode,
+			"duration", time.Since(start),
 		)
+
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		metrics.APIRequestsTotal.WithLabelValues(entry.client, route, strconv.Itoa(lrw.statusCode)).Inc()
 	})
 }
 
+// logEntry holds what inner middleware learns about a request and the
+// logging middleware reports once the request is served.
+type logEntry struct {
+	client string
+}
+
+type logEntryKey struct{}
+
+// setLogClient records the client of r for the log line and metrics.
+func setLogClient(r *http.Request, client string) {
+	if entry, ok := r.Context().Value(logEntryKey{}).(*logEntry); ok {
+		entry.client = client
+	}
+}
+
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -38,3 +69,9 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming handlers can flush through the middleware.
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
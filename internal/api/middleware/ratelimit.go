@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// IPRateLimit limits the requests of each client IP address. It runs before
+// authentication, so it also slows down attempts to guess API keys.
+// Requests relayed by one of trustedProxies are attributed to the address
+// the proxy reports in X-Forwarded-For.
+func IPRateLimit(store ratelimit.Store, limit ratelimit.Limit, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r, trustedProxies)
+			if rateLimit(w, r, store, ratelimit.AddressKey(ip), limit, "ip") {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// ClientRateLimit limits the requests of each authenticated client to the
+// limit of its tier. It must run after APIKeyAuth; requests without a
+// client pass through.
+func ClientRateLimit(store ratelimit.Store, tiers ratelimit.Tiers) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, ok := auth.FromContext(r.Context())
+			if !ok || rateLimit(w, r, store, "client:"+client.Name, tiers.Limit(client.Tier), "client") {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// ItemRateLimit gives each request a ratelimit.Budget of items, drawn from
+// the bucket of its client or, for requests without one, of its address.
+// The batch and stream handlers take a token per password from it, so that
+// one request cannot validate more passwords than limit allows. It must run
+// after APIKeyAuth.
+func ItemRateLimit(store ratelimit.Store, limit ratelimit.Limit, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := ratelimit.AddressKey(ClientIP(r, trustedProxies))
+			if client, ok := auth.FromContext(r.Context()); ok {
+				key = "client:" + client.Name
+			}
+			budget := ratelimit.Budget{Store: store, Key: "items:" + key, Limit: limit}
+			next.ServeHTTP(w, r.WithContext(ratelimit.NewContext(r.Context(), budget)))
+		})
+	}
+}
+
+// rateLimit takes a token for key and sets the RateLimit-* headers. When
+// the bucket is empty it answers 429 Too Many Requests and returns false.
+func rateLimit(w http.ResponseWriter, r *http.Request, store ratelimit.Store, key string, limit ratelimit.Limit, scope string) bool {
+	if limit.Unlimited() {
+		return true
+	}
+
+	decision, err := store.Take(r.Context(), key, limit, 1)
+	if err != nil {
+		// Losing the limits is better than losing the API.
+		slog.Error("Rate limit store error, letting the request through", "error", err)
+		return true
+	}
+	setRateLimitHeaders(w, decision)
+	if decision.Allowed {
+		return true
+	}
+
+	retryAfter := ceilSeconds(decision.RetryAfter)
+	metrics.RateLimitRejectedTotal.WithLabelValues(scope, auth.ClientName(r.Context())).Inc()
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	sendError(w, http.StatusTooManyRequests, fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retryAfter))
+	return false
+}
+
+// setRateLimitHeaders describes the bucket in the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers. When several limits
+// apply, the headers describe the one with the fewest requests left.
+func setRateLimitHeaders(w http.ResponseWriter, d ratelimit.Decision) {
+	h := w.Header()
+	if current, err := strconv.Atoi(h.Get("RateLimit-Remaining")); err == nil && current < d.Remaining {
+		return
+	}
+	h.Set("RateLimit-Limit", strconv.Itoa(d.Limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+}
+
+// ceilSeconds rounds d up to whole seconds, as the headers expect.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ClientIP returns the address of the client that sent r. When the
+// connection comes from a trusted proxy, it is the rightmost address of
+// X-Forwarded-For that is not itself a trusted proxy, since the leftmost
+// entries can be forged by the client.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !trusted(addr, trustedProxies) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		if addr = hop.Unmap(); !trusted(addr, trustedProxies) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+func trusted(addr netip.Addr, prefixes []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,206 @@
 package models
 
+import "github.com/willherrera/itau-backend-challenge/internal/domain"
+
+// UserDetails are the optional details of the account a password is for,
+// used by the personal_info rule and by the strength estimate. Requests embed
+// them, so they are sent as top-level JSON fields.
+type UserDetails struct {
+	Username    string `json:"username,omitempty" example:"jsilva"`
+	Email       string `json:"email,omitempty" example:"joao.silva@example.com"`
+	FirstName   string `json:"firstName,omitempty" example:"João"`
+	LastName    string `json:"lastName,omitempty" example:"Silva"`
+	CompanyName string `json:"companyName,omitempty" example:"Example S.A."`
+}
+
+// UserInfo returns the account details sent with the request.
+func (u UserDetails) UserInfo() domain.UserInfo {
+	return domain.UserInfo{
+		Username:    u.Username,
+		Email:       u.Email,
+		FirstName:   u.FirstName,
+		LastName:    u.LastName,
+		CompanyName: u.CompanyName,
+	}
+}
+
 type ValidatePasswordRequest struct {
 	Password string `json:"password" example:"AbTp9!fok" binding:"required"`
+	Policy   string `json:"policy,omitempty" example:"default"`
+
+	// Locale selects the language of the violation messages (pt-BR, en or
+	// es) and takes precedence over the Accept-Language header.
+	Locale string `json:"locale,omitempty" example:"pt-BR"`
+
+	// SubjectID identifies the user or account changing its password. When
+	// password history is enabled, its recent passwords are rejected.
+	SubjectID string `json:"subjectId,omitempty" example:"user-42"`
+
+	UserDetails
+}
+
+// ValidatePasswordChangeRequest validates a new password and compares it with
+// the password it replaces. The optional fields mean the same as in
+// ValidatePasswordRequest.
+type ValidatePasswordChangeRequest struct {
+	OldPassword string `json:"oldPassword" example:"Summer2024!" binding:"required"`
+	NewPassword string `json:"newPassword" example:"AbTp9!fok" binding:"required"`
+	Policy      string `json:"policy,omitempty" example:"default"`
+	Locale      string `json:"locale,omitempty" example:"pt-BR"`
+	SubjectID   string `json:"subjectId,omitempty" example:"user-42"`
+
+	UserDetails
+}
+
+// RecordPasswordRequest adds a password to a subject's history.
+type RecordPasswordRequest struct {
+	Password string `json:"password" example:"AbTp9!fok" binding:"required"`
+}
+
+// ValidatePasswordsRequest validates several passwords against one policy.
+type ValidatePasswordsRequest struct {
+	Policy string                 `json:"policy,omitempty" example:"default"`
+	Locale string                 `json:"locale,omitempty" example:"pt-BR"`
+	Items  []ValidatePasswordItem `json:"items" binding:"required"`
+}
+
+// ValidatePasswordItem is one password of a batch. ID is echoed back in the
+// result; the other optional fields mean the same as in
+// ValidatePasswordRequest.
+type ValidatePasswordItem struct {
+	ID        string `json:"id,omitempty" example:"legacy-1042"`
+	Password  string `json:"password" example:"AbTp9!fok" binding:"required"`
+	SubjectID string `json:"subjectId,omitempty" example:"user-42"`
+
+	UserDetails
+}
+
+// GeneratePasswordRequest asks for passwords that satisfy a policy. All
+// fields are optional.
+type GeneratePasswordRequest struct {
+	Policy string `json:"policy,omitempty" example:"default"`
+
+	// Mode is "random" (default) or "passphrase".
+	Mode string `json:"mode,omitempty" example:"random" enums:"random,passphrase"`
+
+	// Length of random passwords; defaults to 16 or the policy minimum.
+	Length int `json:"length,omitempty" example:"16"`
+
+	// Words in a passphrase; defaults to 4. More are added if the policy
+	// minimum length requires it.
+	Words int `json:"words,omitempty" example:"4"`
+
+	// Count of passwords to generate, from 1 (default) to 20.
+	Count int `json:"count,omitempty" example:"1"`
+
+	// ExcludeAmbiguous leaves out characters that are easily confused, such
+	// as 0/O and 1/l/I.
+	ExcludeAmbiguous bool `json:"excludeAmbiguous,omitempty" example:"false"`
+}
+
+type GeneratePasswordResponse struct {
+	Policy    string   `json:"policy" example:"default"`
+	Passwords []string `json:"passwords" example:"k#T9vQ2m!xRb7LpZ"`
 }
 
 type ValidatePasswordResponse struct {
-	IsValid bool     `json:"isValid" example:"true"`
-	Errors  []string `json:"errors,omitempty" example:""`
+	IsValid    bool        `json:"isValid" example:"true"`
+	Policy     string      `json:"policy" example:"default"`
+	Errors     []string    `json:"errors,omitempty" example:""`
+	Violations []Violation `json:"violations,omitempty"`
+	Strength   *Strength   `json:"strength,omitempty"`
+}
+
+// ValidatePasswordsResponse holds one result per item, in request order.
+type ValidatePasswordsResponse struct {
+	Policy  string                   `json:"policy" example:"default"`
+	Total   int                      `json:"total" example:"2"`
+	Valid   int                      `json:"valid" example:"1"`
+	Invalid int                      `json:"invalid" example:"1"`
+	Results []ValidatePasswordResult `json:"results"`
+}
+
+type ValidatePasswordResult struct {
+	ID         string      `json:"id,omitempty" example:"legacy-1042"`
+	IsValid    bool        `json:"isValid" example:"false"`
+	Errors     []string    `json:"errors,omitempty" example:""`
+	Violations []Violation `json:"violations,omitempty"`
+	Strength   *Strength   `json:"strength,omitempty"`
+}
+
+// ValidatePasswordStreamResult is one line of the NDJSON response of
+// POST /api/v1/validate-passwords/stream. Line is the number of the input
+// line it answers; Error is set, and the other fields empty, when that line
+// could not be validated.
+type ValidatePasswordStreamResult struct {
+	Line       int         `json:"line" example:"1"`
+	ID         string      `json:"id,omitempty" example:"legacy-1042"`
+	IsValid    bool        `json:"isValid" example:"false"`
+	Errors     []string    `json:"errors,omitempty" example:""`
+	Violations []Violation `json:"violations,omitempty"`
+	Strength   *Strength   `json:"strength,omitempty"`
+	Error      string      `json:"error,omitempty" example:"line is longer than 4096 bytes"`
+}
+
+// ValidatePasswordStreamSummary is the last line of the NDJSON response.
+type ValidatePasswordStreamSummary struct {
+	Summary StreamSummary `json:"summary"`
+}
+
+// StreamSummary counts the results of a stream. Violations maps each rule to
+// the number of passwords that failed it. Error is set when the request
+// body could not be read to the end.
+type StreamSummary struct {
+	Policy     string         `json:"policy" example:"default"`
+	Total      int            `json:"total" example:"3"`
+	Valid      int            `json:"valid" example:"1"`
+	Invalid    int            `json:"invalid" example:"1"`
+	Rejected   int            `json:"rejected" example:"1"`
+	Violations map[string]int `json:"violations"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Strength is the estimated resistance of the password to guessing. Score
+// goes from 0 (too guessable) to 4 (very unguessable).
+type Strength struct {
+	Score             int               `json:"score" example:"3"`
+	Guesses           float64           `json:"guesses" example:"1000000001"`
+	GuessesLog10      float64           `json:"guessesLog10" example:"9"`
+	CrackTimesSeconds CrackTimesSeconds `json:"crackTimesSeconds"`
+	CrackTimeDisplay  string            `json:"crackTimeDisplay" example:"1 day"`
+	Warning           string            `json:"warning,omitempty" example:"This is a top-10 common password"`
+}
+
+type CrackTimesSeconds struct {
+	OnlineThrottling   float64 `json:"onlineThrottling100PerHour" example:"36000000036"`
+	OnlineNoThrottling float64 `json:"onlineNoThrottling10PerSecond" example:"100000000.1"`
+	OfflineSlowHashing float64 `json:"offlineSlowHashing1e4PerSecond" example:"100000.0001"`
+	OfflineFastHashing float64 `json:"offlineFastHashing1e10PerSecond" example:"0.1000000001"`
+}
+
+// Violation is the machine-readable form of a validation error. Clients
+// should match on Code, which is stable across releases, rather than on Message.
+type Violation struct {
+	Code     string         `json:"code" example:"MIN_LENGTH"`
+	Rule     string         `json:"rule" example:"min_length"`
+	Message  string         `json:"message" example:"password must have at least 9 characters"`
+	Params   map[string]any `json:"params,omitempty"`
+	Char     string         `json:"char,omitempty" example:"o"`
+	Position *int           `json:"position,omitempty" example:"8"`
+}
+
+type PolicyResponse struct {
+	Policy string       `json:"policy" example:"default"`
+	Rules  []PolicyRule `json:"rules"`
+}
+
+// PolicyRule describes one active rule. Codes lists the violation codes the
+// rule can produce and Params uses the same keys as Violation.Params.
+type PolicyRule struct {
+	Rule        string         `json:"rule" example:"min_length"`
+	Codes       []string       `json:"codes" example:"MIN_LENGTH"`
+	Description string         `json:"description" example:"Password must have at least 9 characters"`
+	Params      map[string]any `json:"params,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -17,4 +211,7 @@ type ErrorResponse struct {
 type HealthResponse struct {
 	Status  string `json:"status" example:"healthy"`
 	Service string `json:"service" example:"password-validator"`
+
+	// Checks explains why the service is not ready.
+	Checks []string `json:"checks,omitempty" example:"shutdown: draining connections"`
 }
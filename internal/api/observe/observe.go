@@ -0,0 +1,40 @@
+// Package observe holds the request handling shared by the HTTP and gRPC
+// APIs, so both transports report validations and pick the language of
+// their messages the same way.
+package observe
+
+import (
+	"strings"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/i18n"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// TrackValidation records an in-flight validation; call the returned
+// function when it ends.
+func TrackValidation() func() {
+	start := time.Now()
+	metrics.InProgress.Inc()
+	return func() {
+		metrics.InProgress.Dec()
+		metrics.RequestDuration.Observe(time.Since(start).Seconds())
+	}
+}
+
+// RecordValidation counts a validation result and the rules it broke.
+func RecordValidation(isValid bool, violations []domain.Violation) {
+	metrics.RecordValidation(isValid)
+	for _, v := range violations {
+		metrics.RecordViolation(v.Rule, v.Code)
+	}
+}
+
+// Locale returns the language of the violation messages sent back: the
+// locale field of the request when it names a supported language, otherwise
+// the one negotiated from the Accept-Language values, falling back to
+// English.
+func Locale(field string, acceptLanguage ...string) string {
+	return i18n.Select(field, strings.Join(acceptLanguage, ","))
+}
@@ -0,0 +1,26 @@
+package observe
+
+import (
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/i18n"
+)
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		field  string
+		values []string
+		want   string
+	}{
+		{"", nil, i18n.English},
+		{"", []string{"es"}, i18n.Spanish},
+		{"", []string{"fr", "pt-BR;q=0.8"}, i18n.BrazilianPortuguese},
+		{"en", []string{"es"}, i18n.English},
+	}
+
+	for _, tt := range tests {
+		if got := Locale(tt.field, tt.values...); got != tt.want {
+			t.Errorf("Locale(%q, %q) = %q, want %q", tt.field, tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package api assembles the HTTP API: the routes served by the handlers, the
+// operational endpoints and the middleware around them.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
+	"github.com/willherrera/itau-backend-challenge/internal/api/middleware"
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/health"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+)
+
+type routerConfig struct {
+	keys        *auth.Keys
+	limits      *RateLimits
+	probe       *health.Probe
+	cors        middleware.CORSPolicy
+	metricsPath string
+	noSwagger   bool
+}
+
+func newRouterConfig(opts []Option) routerConfig {
+	config := routerConfig{
+		metricsPath: DefaultMetricsPath,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// DefaultMetricsPath is where the Prometheus metrics are served unless
+// WithMetricsPath says otherwise.
+const DefaultMetricsPath = "/metrics"
+
+// Option configures the router.
+type Option func(*routerConfig)
+
+// WithAPIKeys requires one of keys on every /api/v1 request. The health,
+// metrics and swagger endpoints stay open.
+func WithAPIKeys(keys *auth.Keys) Option {
+	return func(c *routerConfig) { c.keys = keys }
+}
+
+// WithProbe makes the readiness endpoints report the state of probe.
+// Without it the API is always ready.
+func WithProbe(probe *health.Probe) Option {
+	return func(c *routerConfig) { c.probe = probe }
+}
+
+// WithCORS sets the cross-origin requests browsers may make to the API.
+// Without it no other origin may read the responses. The policy must be
+// valid; see middleware.CORSPolicy.Validate.
+func WithCORS(policy middleware.CORSPolicy) Option {
+	return func(c *routerConfig) { c.cors = policy }
+}
+
+// WithMetricsPath serves the Prometheus metrics at path instead of
+// DefaultMetricsPath; an empty path does not serve them.
+func WithMetricsPath(path string) Option {
+	return func(c *routerConfig) { c.metricsPath = path }
+}
+
+// WithoutSwagger does not serve the Swagger UI.
+func WithoutSwagger() Option {
+	return func(c *routerConfig) { c.noSwagger = true }
+}
+
+// RateLimits are the token bucket limits applied to /api/v1.
+type RateLimits struct {
+	Store ratelimit.Store
+
+	// PerIP limits each client address, before authentication.
+	PerIP ratelimit.Limit
+
+	// Tiers limit each authenticated client, by the tier of its API key.
+	Tiers ratelimit.Tiers
+
+	// Items limits the passwords of batch and stream requests of each
+	// client, or of each address when the request has no client.
+	Items ratelimit.Limit
+
+	// TrustedProxies are the proxies whose X-Forwarded-For header gives
+	// the client address.
+	TrustedProxies []netip.Prefix
+}
+
+// WithRateLimits applies limits to every /api/v1 request.
+func WithRateLimits(limits RateLimits) Option {
+	return func(c *routerConfig) { c.limits = &limits }
+}
+
+// NewRouter returns the HTTP API served by cmd/api. The swagger UI needs the
+// generated docs package to be imported by the binary.
+func NewRouter(handler *handlers.PasswordHandler, opts ...Option) *mux.Router {
+	config := newRouterConfig(opts)
+
+	router := mux.NewRouter()
+
+	var chain []mux.MiddlewareFunc
+	if config.limits != nil {
+		chain = append(chain, middleware.IPRateLimit(config.limits.Store, config.limits.PerIP, config.limits.TrustedProxies))
+	}
+	if config.keys != nil {
+		chain = append(chain, middleware.APIKeyAuth(config.keys))
+	}
+	if config.limits != nil {
+		chain = append(chain,
+			middleware.ClientRateLimit(config.limits.Store, config.limits.Tiers),
+			middleware.ItemRateLimit(config.limits.Store, config.limits.Items, config.limits.TrustedProxies),
+		)
+	}
+
+	// The API routes are registered on the main router, each wrapped in
+	// chain, rather than on a /api/v1 subrouter: gorilla/mux subrouters
+	// answer 404 instead of 405 to a known path with the wrong method.
+	handleAPI := func(path string, h http.HandlerFunc, method string) {
+		var wrapped http.Handler = h
+		for i := len(chain) - 1; i >= 0; i-- {
+			wrapped = chain[i](wrapped)
+		}
+		router.Handle("/api/v1"+path, wrapped).Methods(method, "OPTIONS")
+	}
+	handleAPI("/validate-password", handler.ValidatePassword, "POST")
+	handleAPI("/validate-passwords", handler.ValidatePasswords, "POST")
+	handleAPI("/validate-passwords/stream", handler.ValidatePasswordStream, "POST")
+	handleAPI("/policies/{name}/validate", handler.ValidatePasswordWithPolicy, "POST")
+	handleAPI("/validate-password-change", handler.ValidatePasswordChange, "POST")
+	handleAPI("/generate-password", handler.GeneratePassword, "POST")
+	handleAPI("/policy", handler.GetPolicy, "GET")
+	handleAPI("/subjects/{id}/history", handler.RecordPasswordHistory, "POST")
+
+	handleOperational(router, config)
+	if !config.noSwagger {
+		router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	}
+
+	router.Use(middleware.LoggingMiddleware)
+	router.Use(middleware.CORS(config.cors))
+	return router
+}
+
+// NewStartupRouter serves the health and metrics endpoints while the
+// password service is being built, and answers every other request with
+// 503 Service Unavailable. The server switches to NewRouter once it is
+// ready. Only the options of the operational endpoints apply.
+func NewStartupRouter(probe *health.Probe, opts ...Option) *mux.Router {
+	config := newRouterConfig(opts)
+	config.probe = probe
+
+	router := mux.NewRouter()
+	handleOperational(router, config)
+	router.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error:   http.StatusText(http.StatusServiceUnavailable),
+			Message: "The service is starting",
+		})
+	})
+
+	router.Use(middleware.LoggingMiddleware)
+	return router
+}
+
+// handleOperational registers the health and metrics endpoints, which are
+// neither authenticated nor rate limited.
+func handleOperational(router *mux.Router, config routerConfig) {
+	healthHandler := handlers.NewHealthHandler(config.probe)
+	router.HandleFunc("/health", healthHandler.Health).Methods("GET")
+	router.HandleFunc("/health/live", healthHandler.Live).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.Ready).Methods("GET")
+	if config.metricsPath != "" {
+		router.Handle(config.metricsPath, promhttp.Handler()).Methods("GET")
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+// BatchItem is one password of a batch, with the optional request data that
+// single validations carry in their context.
+type BatchItem struct {
+	ID  string // caller's identifier, returned with the result
+	Seq int    // position in the input, returned with the result
+
+	Password string
+	Subject  string
+	User     domain.UserInfo
+}
+
+// BatchResult is the result of validating one BatchItem.
+type BatchResult struct {
+	ID  string
+	Seq int
+	*ValidationResult
+}
+
+// ValidateBatch validates every item against the named policy using at most
+// workers goroutines (GOMAXPROCS when workers is not positive). Results are
+// in the order of items. It returns ctx.Err() if the context ends before all
+// items are validated.
+func (s *PasswordService) ValidateBatch(ctx context.Context, name string, items []BatchItem, workers int) ([]*ValidationResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	in := make(chan BatchItem)
+	out, err := s.ValidateStream(ctx, name, in, min(workers, len(items)))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(in)
+		for i, item := range items {
+			item.Seq = i
+			select {
+			case in <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make([]*ValidationResult, len(items))
+	for result := range out {
+		results[result.Seq] = result.ValidationResult
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ValidateStream validates the items received from in against the named
+// policy using at most workers goroutines (GOMAXPROCS when workers is not
+// positive), and sends each result on the returned channel as soon as it is
+// ready, so results may arrive out of order. The channel is closed after in
+// is closed and drained, or when ctx ends; in the latter case some items are
+// left without a result and the caller should check ctx.Err().
+//
+// The policy is read once, so a reload during the stream does not mix rule
+// sets.
+func (s *PasswordService) ValidateStream(ctx context.Context, name string, in <-chan BatchItem, workers int) (<-chan BatchResult, error) {
+	name, validators, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	out := make(chan BatchResult, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var item BatchItem
+				select {
+				case next, ok := <-in:
+					if !ok {
+						return
+					}
+					item = next
+				case <-ctx.Done():
+					return
+				}
+
+				result, err := s.run(itemContext(ctx, item), name, validators, item.Password)
+				if err != nil {
+					return
+				}
+
+				select {
+				case out <- BatchResult{ID: item.ID, Seq: item.Seq, ValidationResult: result}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+func itemContext(ctx context.Context, item BatchItem) context.Context {
+	return domain.WithSubject(domain.WithUserInfo(ctx, item.User), item.Subject)
+}
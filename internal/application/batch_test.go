@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+// concurrencyValidator tracks how many validations run at the same time.
+type concurrencyValidator struct {
+	running, peak atomic.Int32
+}
+
+func (v *concurrencyValidator) Validate(password string) error {
+	n := v.running.Add(1)
+	defer v.running.Add(-1)
+	for {
+		peak := v.peak.Load()
+		if n <= peak || v.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func TestPasswordService_ValidateBatch(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{
+		rules.NewMinLengthValidator(9),
+		rules.NewDigitValidator(),
+		rules.NewPersonalInfoValidator(rules.DefaultMinPersonalTokenLength),
+	})
+
+	items := []BatchItem{
+		{Password: "AbTp9!fok"},
+		{Password: "short"},
+		{Password: "jsilva#2024", User: domain.UserInfo{Username: "jsilva"}},
+		{Password: "jsilva#2024"},
+	}
+	wantValid := []bool{true, false, false, true}
+
+	results, err := service.ValidateBatch(context.Background(), "", items, 2)
+	if err != nil {
+		t.Fatalf("ValidateBatch() unexpected error: %v", err)
+	}
+	if len(results) != len(items) {
+		t.Fatalf("ValidateBatch() returned %d results, want %d", len(results), len(items))
+	}
+	for i, result := range results {
+		if result.IsValid != wantValid[i] {
+			t.Errorf("results[%d].IsValid = %v, want %v (%v)", i, result.IsValid, wantValid[i], result.Errors)
+		}
+		if result.Policy != DefaultPolicy {
+			t.Errorf("results[%d].Policy = %q, want %q", i, result.Policy, DefaultPolicy)
+		}
+	}
+}
+
+func TestPasswordService_ValidateBatch_BoundedWorkers(t *testing.T) {
+	validator := &concurrencyValidator{}
+	service := NewPasswordService([]domain.PasswordValidator{validator})
+
+	items := make([]BatchItem, 50)
+	for i := range items {
+		items[i] = BatchItem{Password: fmt.Sprintf("password-%d", i)}
+	}
+
+	if _, err := service.ValidateBatch(context.Background(), "", items, 4); err != nil {
+		t.Fatalf("ValidateBatch() unexpected error: %v", err)
+	}
+	if peak := validator.peak.Load(); peak > 4 {
+		t.Errorf("ValidateBatch() ran %d validations at once, want at most 4", peak)
+	}
+}
+
+func TestPasswordService_ValidateBatch_Errors(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+
+	if _, err := service.ValidateBatch(context.Background(), "missing", []BatchItem{{Password: "x"}}, 1); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("ValidateBatch() error = %v, want %v", err, ErrPolicyNotFound)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := service.ValidateBatch(ctx, "", make([]BatchItem, 10), 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("ValidateBatch() error = %v, want %v", err, context.Canceled)
+	}
+
+	results, err := service.ValidateBatch(context.Background(), "", nil, 2)
+	if err != nil || len(results) != 0 {
+		t.Errorf("ValidateBatch(nil) = %v, %v; want no results", results, err)
+	}
+}
+
+func TestPasswordService_ValidateStream(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+
+	in := make(chan BatchItem)
+	out, err := service.ValidateStream(context.Background(), "", in, 3)
+	if err != nil {
+		t.Fatalf("ValidateStream() unexpected error: %v", err)
+	}
+
+	go func() {
+		defer close(in)
+		for i := range 20 {
+			password := "short"
+			if i%2 == 0 {
+				password = "long enough"
+			}
+			in <- BatchItem{ID: fmt.Sprintf("item-%d", i), Seq: i, Password: password}
+		}
+	}()
+
+	seen := make(map[int]bool)
+	for result := range out {
+		if result.ID != fmt.Sprintf("item-%d", result.Seq) {
+			t.Errorf("result ID = %q for Seq %d", result.ID, result.Seq)
+		}
+		if result.IsValid != (result.Seq%2 == 0) {
+			t.Errorf("result %d IsValid = %v", result.Seq, result.IsValid)
+		}
+		seen[result.Seq] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("ValidateStream() produced %d results, want 20", len(seen))
+	}
+}
+
+func TestPasswordService_ValidateStream_Cancel(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan BatchItem) // never closed: only cancellation ends the stream
+	out, err := service.ValidateStream(ctx, "", in, 2)
+	if err != nil {
+		t.Fatalf("ValidateStream() unexpected error: %v", err)
+	}
+
+	in <- BatchItem{Password: "AbTp9!fok"}
+	<-out
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("ValidateStream() produced a result after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ValidateStream() did not close its channel after cancellation")
+	}
+
+	if _, err := service.ValidateStream(context.Background(), "missing", in, 1); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("ValidateStream() error = %v, want %v", err, ErrPolicyNotFound)
+	}
+}
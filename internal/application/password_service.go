@@ -1,36 +1,323 @@
 package application
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
 	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+	"github.com/willherrera/itau-backend-challenge/internal/generator"
+)
+
+// DefaultPolicy is the policy applied when the caller does not ask for one.
+const DefaultPolicy = "default"
+
+var (
+	ErrPolicyNotFound  = errors.New("policy not found")
+	ErrNoDefaultPolicy = errors.New("a policy named \"" + DefaultPolicy + "\" is required")
+	ErrHistoryDisabled = errors.New("password history is not enabled")
 )
 
+// PasswordHistory remembers the passwords used by each subject. See
+// internal/history for the implementation.
+type PasswordHistory interface {
+	rules.PasswordHistory
+	Record(ctx context.Context, subject, password string) error
+}
+
+// PasswordService validates passwords against a set of named policies. The
+// set can be replaced at runtime with SetPolicies; each validation reads the
+// set once, so in-flight requests finish with the policies they started with.
 type PasswordService struct {
-	validators []domain.PasswordValidator
+	policies atomic.Pointer[map[string][]domain.PasswordValidator]
+
+	history     PasswordHistory
+	historyRule *rules.PasswordHistoryValidator
 }
 
+// NewPasswordService creates a service with a single, default policy.
 func NewPasswordService(validators []domain.PasswordValidator) *PasswordService {
-	return &PasswordService{
-		validators: validators,
+	s := &PasswordService{}
+	s.policies.Store(&map[string][]domain.PasswordValidator{
+		DefaultPolicy: validators,
+	})
+	return s
+}
+
+// NewPasswordServiceWithPolicies creates a service holding several named
+// policies. One of them must be named DefaultPolicy.
+func NewPasswordServiceWithPolicies(policies map[string][]domain.PasswordValidator) (*PasswordService, error) {
+	s := &PasswordService{}
+	if err := s.SetPolicies(policies); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetPolicies atomically replaces every policy served by the service.
+func (s *PasswordService) SetPolicies(policies map[string][]domain.PasswordValidator) error {
+	if _, ok := policies[DefaultPolicy]; !ok {
+		return ErrNoDefaultPolicy
+	}
+	s.policies.Store(&policies)
+	return nil
+}
+
+// SetHistory enables password history: every policy then also rejects a
+// password the subject in the validation context used recently, and
+// RecordPassword becomes available. It must be called before the service
+// handles requests.
+func (s *PasswordService) SetHistory(history PasswordHistory) {
+	s.history = history
+	s.historyRule = rules.NewPasswordHistoryValidator(history)
+}
+
+// serviceOf is registered by pkg/passwordpolicy, which cannot export the
+// service behind its Validator without exposing this package.
+var serviceOf func(validator any) *PasswordService
+
+// RegisterServiceOf is called by pkg/passwordpolicy to make ServiceOf work.
+func RegisterServiceOf(f func(validator any) *PasswordService) {
+	serviceOf = f
+}
+
+// ServiceOf returns the service behind a *passwordpolicy.Validator, so the
+// servers in cmd/api are built on the public package. It returns nil for any
+// other value.
+func ServiceOf(validator any) *PasswordService {
+	if serviceOf == nil {
+		return nil
+	}
+	return serviceOf(validator)
+}
+
+// RecordPassword adds password to the subject's history after a successful
+// password change.
+func (s *PasswordService) RecordPassword(ctx context.Context, subject, password string) error {
+	if s.history == nil {
+		return ErrHistoryDisabled
 	}
+	return s.history.Record(ctx, subject, password)
 }
 
 type ValidationResult struct {
-	IsValid bool     `json:"isValid"`
-	Errors  []string `json:"errors,omitempty"`
+	IsValid    bool               `json:"isValid"`
+	Policy     string             `json:"policy"`
+	Errors     []string           `json:"errors,omitempty"`
+	Violations []domain.Violation `json:"violations,omitempty"`
+	Strength   strength.Result    `json:"strength"`
 }
 
+// Validate checks the password against the default policy.
 func (s *PasswordService) Validate(password string) *ValidationResult {
+	result, _ := s.ValidatePolicy(DefaultPolicy, password)
+	return result
+}
+
+// ValidatePolicy checks the password against the named policy. An empty name
+// selects the default policy.
+func (s *PasswordService) ValidatePolicy(name, password string) (*ValidationResult, error) {
+	return s.ValidatePolicyContext(context.Background(), name, password)
+}
+
+// ValidatePolicyContext is like ValidatePolicy but passes ctx to validators
+// that perform I/O or read request data such as domain.UserInfo. It returns
+// ctx.Err() if the context ends during validation.
+func (s *PasswordService) ValidatePolicyContext(ctx context.Context, name, password string) (*ValidationResult, error) {
+	return s.validate(ctx, name, password, false)
+}
+
+// ValidatePasswordChange validates newPassword against the named policy and
+// also rejects it when it is a small variation of oldPassword. Policies
+// without a similarity rule of their own get one with default settings.
+func (s *PasswordService) ValidatePasswordChange(ctx context.Context, name, oldPassword, newPassword string) (*ValidationResult, error) {
+	return s.validate(domain.WithPreviousPassword(ctx, oldPassword), name, newPassword, true)
+}
+
+func (s *PasswordService) validate(ctx context.Context, name, password string, change bool) (*ValidationResult, error) {
+	name, validators, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if change && !hasSimilarityRule(validators) {
+		validators = append(validators[:len(validators):len(validators)], rules.NewSimilarityValidator(rules.DefaultMinEditDistance))
+	}
+	return s.run(ctx, name, validators, password)
+}
+
+// lookup returns the validators of the named policy, defaulting the name.
+// HasPolicy reports whether the service holds the named policy; an empty
+// name is the default policy.
+func (s *PasswordService) HasPolicy(name string) bool {
+	_, _, err := s.lookup(name)
+	return err == nil
+}
+
+func (s *PasswordService) lookup(name string) (string, []domain.PasswordValidator, error) {
+	if name == "" {
+		name = DefaultPolicy
+	}
+
+	validators, ok := (*s.policies.Load())[name]
+	if !ok {
+		return name, nil, ErrPolicyNotFound
+	}
+	return name, validators, nil
+}
+
+// run applies validators, followed by the history rule when enabled.
+func (s *PasswordService) run(ctx context.Context, name string, validators []domain.PasswordValidator, password string) (*ValidationResult, error) {
+	user, _ := domain.UserInfoFromContext(ctx)
 	result := &ValidationResult{
-		IsValid: true,
-		Errors:  []string{},
+		IsValid:    true,
+		Policy:     name,
+		Errors:     []string{},
+		Violations: []domain.Violation{},
+		Strength:   strength.Estimate(password, user.Inputs()...),
+	}
+
+	if s.historyRule != nil {
+		validators = append(validators[:len(validators):len(validators)], s.historyRule)
 	}
 
-	for _, validator := range s.validators {
-		if err := validator.Validate(password); err != nil {
+	for _, validator := range validators {
+		if err := domain.ValidateContext(ctx, validator, password); err != nil {
 			result.IsValid = false
 			result.Errors = append(result.Errors, err.Error())
+			result.Violations = append(result.Violations, domain.AsViolation(err))
 		}
 	}
 
-	return result
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const (
+	// attemptsPerPassword bounds the candidates checked against the rules
+	// that need no I/O, per password asked for.
+	attemptsPerPassword = 50
+
+	// maxGeneratedRejections bounds the candidates that pass those rules
+	// but fail the rest of the policy, which may do network lookups.
+	maxGeneratedRejections = 3
+
+	// generateTimeout bounds a whole GeneratePasswords call.
+	generateTimeout = 5 * time.Second
+)
+
+// GeneratePasswords creates count passwords that pass every rule of the
+// named policy. Candidates come from the generator and are first checked
+// with the rules that need no I/O; only those that pass are checked with
+// the full policy, so rules the generator does not model (blocklists,
+// breached passwords) are honoured without a lookup per discarded
+// candidate. It returns an error wrapping generator.ErrUnsatisfiable when
+// too many candidates are rejected, and the context error when the call
+// takes longer than generateTimeout.
+func (s *PasswordService) GeneratePasswords(ctx context.Context, name string, opts generator.Options, count int) ([]string, error) {
+	name, validators, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	descriptions, err := s.Describe(name)
+	if err != nil {
+		return nil, err
+	}
+	req := generator.RequirementsFromRules(descriptions)
+
+	var local []domain.PasswordValidator
+	for _, validator := range validators {
+		if _, ok := validator.(domain.ContextValidator); !ok {
+			local = append(local, validator)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout)
+	defer cancel()
+
+	passwords := make([]string, 0, count)
+	for attempts, rejected := 0, 0; len(passwords) < count; attempts++ {
+		if attempts == count*attemptsPerPassword || rejected == maxGeneratedRejections {
+			return nil, fmt.Errorf("%w: generated passwords keep failing the policy", generator.ErrUnsatisfiable)
+		}
+
+		candidate, err := generator.Generate(req, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !passesAll(local, candidate) {
+			continue
+		}
+
+		result, err := s.run(ctx, name, validators, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if result.IsValid {
+			passwords = append(passwords, candidate)
+		} else {
+			rejected++
+		}
+	}
+	return passwords, nil
+}
+
+// passesAll reports whether password passes every validator.
+func passesAll(validators []domain.PasswordValidator, password string) bool {
+	for _, validator := range validators {
+		if validator.Validate(password) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func hasSimilarityRule(validators []domain.PasswordValidator) bool {
+	for _, validator := range validators {
+		if _, ok := validator.(*rules.SimilarityValidator); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Policies returns the names of the configured policies in sorted order.
+func (s *PasswordService) Policies() []string {
+	policies := *s.policies.Load()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe lists the rules of the named policy, in the order they are
+// applied. Validators that do not implement domain.Describer are reported
+// as unknown rules.
+func (s *PasswordService) Describe(name string) ([]domain.RuleDescription, error) {
+	if name == "" {
+		name = DefaultPolicy
+	}
+
+	validators, ok := (*s.policies.Load())[name]
+	if !ok {
+		return nil, ErrPolicyNotFound
+	}
+
+	if s.historyRule != nil {
+		validators = append(validators[:len(validators):len(validators)], s.historyRule)
+	}
+
+	descriptions := make([]domain.RuleDescription, 0, len(validators))
+	for _, validator := range validators {
+		descriptions = append(descriptions, domain.Describe(validator))
+	}
+	return descriptions, nil
 }
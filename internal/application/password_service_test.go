@@ -1,10 +1,15 @@
 package application
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/willherrera/itau-backend-challenge/internal/domain"
 	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/generator"
 )
 
 func TestPasswordService_Validate(t *testing.T) {
@@ -141,3 +146,358 @@ func TestPasswordService_ValidateAllExamples(t *testing.T) {
 		})
 	}
 }
+
+func TestPasswordService_Violations(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{
+		rules.NewMinLengthValidator(9),
+		rules.NewDigitValidator(),
+		rules.NewLowercaseValidator(),
+		rules.NewUppercaseValidator(),
+		rules.NewSpecialCharValidator("!@#$%^&*()-+"),
+		rules.NewNoDuplicatesValidator(),
+		plainErrorValidator{},
+	})
+
+	result := service.Validate("aa")
+
+	wantCodes := []string{
+		rules.CodeMinLength,
+		rules.CodeNoDigit,
+		rules.CodeNoUppercase,
+		rules.CodeNoSpecialChar,
+		rules.CodeDuplicateChar,
+		domain.CodeUnknown,
+	}
+	if len(result.Violations) != len(wantCodes) {
+		t.Fatalf("got %d violations, want %d: %+v", len(result.Violations), len(wantCodes), result.Violations)
+	}
+	if len(result.Errors) != len(result.Violations) {
+		t.Errorf("got %d errors and %d violations, want the same count", len(result.Errors), len(result.Violations))
+	}
+
+	for i, code := range wantCodes {
+		if result.Violations[i].Code != code {
+			t.Errorf("violation[%d].Code = %s, want %s", i, result.Violations[i].Code, code)
+		}
+		if result.Violations[i].Message != result.Errors[i] {
+			t.Errorf("violation[%d].Message = %q, want legacy error %q", i, result.Violations[i].Message, result.Errors[i])
+		}
+	}
+}
+
+type plainErrorValidator struct{}
+
+func (plainErrorValidator) Validate(password string) error {
+	return errors.New("custom rule failed")
+}
+
+func TestPasswordService_ValidatePolicy(t *testing.T) {
+	service, err := NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		DefaultPolicy: {rules.NewMinLengthValidator(4)},
+		"admin":       {rules.NewMinLengthValidator(12), rules.NewDigitValidator()},
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordServiceWithPolicies() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		policy    string
+		password  string
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "empty name uses default", policy: "", password: "abcd", wantValid: true},
+		{name: "default policy", policy: DefaultPolicy, password: "abc", wantValid: false},
+		{name: "admin policy rejects short password", policy: "admin", password: "abcd1", wantValid: false},
+		{name: "admin policy accepts long password", policy: "admin", password: "abcdefghijk1", wantValid: true},
+		{name: "unknown policy", policy: "pin", password: "1234", wantErr: ErrPolicyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ValidatePolicy(tt.policy, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidatePolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if result.IsValid != tt.wantValid {
+				t.Errorf("ValidatePolicy() IsValid = %v, want %v. Errors: %v", result.IsValid, tt.wantValid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestNewPasswordServiceWithPolicies_RequiresDefault(t *testing.T) {
+	_, err := NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		"admin": {rules.NewDigitValidator()},
+	})
+	if !errors.Is(err, ErrNoDefaultPolicy) {
+		t.Errorf("NewPasswordServiceWithPolicies() error = %v, want %v", err, ErrNoDefaultPolicy)
+	}
+}
+
+func TestPasswordService_SetPolicies(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{rules.NewDigitValidator()})
+
+	if err := service.SetPolicies(map[string][]domain.PasswordValidator{
+		"admin": {rules.NewDigitValidator()},
+	}); !errors.Is(err, ErrNoDefaultPolicy) {
+		t.Fatalf("SetPolicies() error = %v, want %v", err, ErrNoDefaultPolicy)
+	}
+	if !service.Validate("abc1").IsValid {
+		t.Error("rejected SetPolicies() call replaced the policies")
+	}
+
+	if err := service.SetPolicies(map[string][]domain.PasswordValidator{
+		DefaultPolicy: {rules.NewUppercaseValidator()},
+	}); err != nil {
+		t.Fatalf("SetPolicies() unexpected error: %v", err)
+	}
+	if service.Validate("abc1").IsValid {
+		t.Error("SetPolicies() did not replace the default policy")
+	}
+}
+
+func TestPasswordService_Describe(t *testing.T) {
+	service := NewPasswordService([]domain.PasswordValidator{
+		rules.NewMinLengthValidator(12),
+		rules.NewSpecialCharValidator("_"),
+		plainErrorValidator{},
+	})
+
+	descriptions, err := service.Describe("")
+	if err != nil {
+		t.Fatalf("Describe() unexpected error: %v", err)
+	}
+
+	wantRules := []string{rules.RuleMinLength, rules.RuleSpecialChar, domain.RuleUnknown}
+	if len(descriptions) != len(wantRules) {
+		t.Fatalf("Describe() got %d rules, want %d", len(descriptions), len(wantRules))
+	}
+	for i, rule := range wantRules {
+		if descriptions[i].Rule != rule {
+			t.Errorf("descriptions[%d].Rule = %s, want %s", i, descriptions[i].Rule, rule)
+		}
+	}
+
+	if descriptions[0].Params["minLength"] != 12 {
+		t.Errorf("min_length params = %v, want minLength=12", descriptions[0].Params)
+	}
+	if descriptions[1].Description != "Password must contain at least one special character (_)" {
+		t.Errorf("special_char description = %q", descriptions[1].Description)
+	}
+
+	if _, err := service.Describe("admin"); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("Describe(admin) error = %v, want %v", err, ErrPolicyNotFound)
+	}
+}
+
+type contextValidator struct {
+	gotCtx context.Context
+}
+
+func (v *contextValidator) Validate(password string) error {
+	return errors.New("Validate called instead of ValidateContext")
+}
+
+func (v *contextValidator) ValidateContext(ctx context.Context, password string) error {
+	v.gotCtx = ctx
+	return nil
+}
+
+func TestPasswordService_ValidatePolicyContext(t *testing.T) {
+	validator := &contextValidator{}
+	service := NewPasswordService([]domain.PasswordValidator{validator})
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+
+	result, err := service.ValidatePolicyContext(ctx, "", "AbTp9!fok")
+	if err != nil || !result.IsValid {
+		t.Fatalf("ValidatePolicyContext() = %+v, %v", result, err)
+	}
+	if validator.gotCtx == nil || validator.gotCtx.Value(key{}) != "request" {
+		t.Error("ValidatePolicyContext() did not pass the context to the validator")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := service.ValidatePolicyContext(cancelled, "", "AbTp9!fok"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ValidatePolicyContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestPasswordService_StrengthUsesUserInfo(t *testing.T) {
+	service := NewPasswordService(nil)
+
+	anonymous, _ := service.ValidatePolicyContext(context.Background(), "", "ricardomendes")
+	ctx := domain.WithUserInfo(context.Background(), domain.UserInfo{Username: "ricardomendes"})
+	known, _ := service.ValidatePolicyContext(ctx, "", "ricardomendes")
+
+	if known.Strength.Guesses >= anonymous.Strength.Guesses {
+		t.Errorf("guesses with user info = %v, want fewer than %v", known.Strength.Guesses, anonymous.Strength.Guesses)
+	}
+}
+
+type memoryHistory struct {
+	passwords map[string][]string
+}
+
+func (h *memoryHistory) Contains(ctx context.Context, subject, password string) (bool, error) {
+	for _, used := range h.passwords[subject] {
+		if used == password {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (h *memoryHistory) Depth() int {
+	return 5
+}
+
+func (h *memoryHistory) Record(ctx context.Context, subject, password string) error {
+	h.passwords[subject] = append(h.passwords[subject], password)
+	return nil
+}
+
+func TestPasswordService_History(t *testing.T) {
+	service := NewPasswordService(nil)
+
+	if err := service.RecordPassword(context.Background(), "user-42", "AbTp9!fok"); !errors.Is(err, ErrHistoryDisabled) {
+		t.Fatalf("RecordPassword() error = %v, want ErrHistoryDisabled", err)
+	}
+
+	service.SetHistory(&memoryHistory{passwords: map[string][]string{}})
+	if err := service.RecordPassword(context.Background(), "user-42", "AbTp9!fok"); err != nil {
+		t.Fatalf("RecordPassword() unexpected error: %v", err)
+	}
+
+	ctx := domain.WithSubject(context.Background(), "user-42")
+	result, _ := service.ValidatePolicyContext(ctx, "", "AbTp9!fok")
+	if result.IsValid || result.Violations[0].Code != rules.CodePasswordReused {
+		t.Errorf("reused password result = %+v", result)
+	}
+
+	if result := service.Validate("AbTp9!fok"); !result.IsValid {
+		t.Errorf("password without subject rejected: %+v", result.Violations)
+	}
+
+	descriptions, _ := service.Describe("")
+	if len(descriptions) != 1 || descriptions[0].Rule != rules.RulePasswordHistory {
+		t.Errorf("Describe() = %+v, want the password_history rule", descriptions)
+	}
+}
+
+func TestPasswordService_ValidatePasswordChange(t *testing.T) {
+	service, _ := NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		DefaultPolicy: {rules.NewMinLengthValidator(9)},
+		"lenient":     {rules.NewSimilarityValidator(1)},
+	})
+
+	result, err := service.ValidatePasswordChange(context.Background(), "", "Winter#1986", "Winter#1987")
+	if err != nil || result.IsValid || result.Violations[0].Code != rules.CodeSimilarToPrevious {
+		t.Errorf("default policy change = %+v, %v; want SIMILAR_TO_PREVIOUS", result, err)
+	}
+
+	// The policy's own similarity rule replaces the default one.
+	result, _ = service.ValidatePasswordChange(context.Background(), "lenient", "Kp9#vXw2q", "Kp9#vXw3r")
+	if !result.IsValid {
+		t.Errorf("lenient policy change rejected: %+v", result.Violations)
+	}
+
+	if result := service.Validate("Winter#1987"); !result.IsValid {
+		t.Errorf("plain validation applied the similarity rule: %+v", result.Violations)
+	}
+}
+
+// lookupValidator stands for a rule that does I/O, such as the breached
+// password lookup, and counts its calls.
+type lookupValidator struct {
+	calls  atomic.Int32
+	reject bool
+}
+
+func (v *lookupValidator) Validate(password string) error {
+	return v.ValidateContext(context.Background(), password)
+}
+
+func (v *lookupValidator) ValidateContext(ctx context.Context, password string) error {
+	v.calls.Add(1)
+	if v.reject {
+		return errors.New("password could not be checked")
+	}
+	return nil
+}
+
+func TestPasswordService_GeneratePasswordsLooksUpOnlyFinalCandidates(t *testing.T) {
+	lookup := &lookupValidator{}
+	failing := &lookupValidator{reject: true}
+	service, _ := NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		DefaultPolicy: {rules.NewMinLengthValidator(9), noLetterAValidator{}, lookup},
+		"unavailable": {rules.NewMinLengthValidator(9), failing},
+	})
+
+	if _, err := service.GeneratePasswords(context.Background(), "", generator.Options{Length: 12}, 5); err != nil {
+		t.Fatalf("GeneratePasswords() unexpected error: %v", err)
+	}
+	if calls := lookup.calls.Load(); calls != 5 {
+		t.Errorf("lookup rule ran %d times for 5 passwords, want 5", calls)
+	}
+
+	_, err := service.GeneratePasswords(context.Background(), "unavailable", generator.Options{}, 20)
+	if !errors.Is(err, generator.ErrUnsatisfiable) {
+		t.Errorf("GeneratePasswords() error = %v, want %v", err, generator.ErrUnsatisfiable)
+	}
+	if calls := failing.calls.Load(); calls != maxGeneratedRejections {
+		t.Errorf("failing lookup rule ran %d times, want %d", calls, maxGeneratedRejections)
+	}
+}
+
+// noLetterAValidator rejects passwords the generator cannot know about.
+type noLetterAValidator struct{}
+
+func (noLetterAValidator) Validate(password string) error {
+	if strings.ContainsAny(password, "aA") {
+		return errors.New("password must not contain the letter a")
+	}
+	return nil
+}
+
+func TestPasswordService_GeneratePasswords(t *testing.T) {
+	service, _ := NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		DefaultPolicy: {
+			rules.NewMinLengthValidator(9),
+			rules.NewDigitValidator(),
+			rules.NewLowercaseValidator(),
+			rules.NewUppercaseValidator(),
+			rules.NewSpecialCharValidator("!@#$%^&*()-+"),
+			rules.NewNoDuplicatesValidator(),
+			noLetterAValidator{},
+		},
+		"impossible": {plainErrorValidator{}},
+	})
+
+	passwords, err := service.GeneratePasswords(context.Background(), "", generator.Options{Length: 12}, 5)
+	if err != nil {
+		t.Fatalf("GeneratePasswords() unexpected error: %v", err)
+	}
+	if len(passwords) != 5 {
+		t.Fatalf("GeneratePasswords() returned %d passwords, want 5", len(passwords))
+	}
+	for _, password := range passwords {
+		if result := service.Validate(password); !result.IsValid {
+			t.Errorf("GeneratePasswords() = %q, rejected: %v", password, result.Errors)
+		}
+	}
+
+	if _, err := service.GeneratePasswords(context.Background(), "impossible", generator.Options{}, 1); !errors.Is(err, generator.ErrUnsatisfiable) {
+		t.Errorf("GeneratePasswords() error = %v, want %v", err, generator.ErrUnsatisfiable)
+	}
+	if _, err := service.GeneratePasswords(context.Background(), "missing", generator.Options{}, 1); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("GeneratePasswords() error = %v, want %v", err, ErrPolicyNotFound)
+	}
+}
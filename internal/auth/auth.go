@@ -0,0 +1,276 @@
+// Package auth identifies API clients by key or by TLS client certificate.
+// Keys are never stored: the keys file lists their SHA-256 digests together
+// with the client each one belongs to. API keys are long random tokens, so a
+// fast digest is enough to keep a leaked file from revealing them, and it
+// lets a key be looked up by its digest instead of being compared with every
+// entry.
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// Anonymous names the client of requests made without authentication.
+	Anonymous = "anonymous"
+
+	// DefaultTier is the rate limit tier of keys that do not name one.
+	DefaultTier = "default"
+
+	hashPrefix = "sha256:"
+)
+
+// Client is the identity and permissions attached to an API key.
+type Client struct {
+	// Name identifies the client in logs and metric labels. Several keys
+	// may share it, e.g. while a key is being rotated.
+	Name string `yaml:"client" json:"client"`
+
+	// Policies lists the password policies the client may use; empty
+	// allows all of them.
+	Policies []string `yaml:"policies,omitempty" json:"policies,omitempty"`
+
+	// Tier selects the rate limits applied to the client.
+	Tier string `yaml:"tier,omitempty" json:"tier,omitempty"`
+}
+
+// AllowsPolicy reports whether the client may use the named policy.
+func (c Client) AllowsPolicy(name string) bool {
+	return len(c.Policies) == 0 || slices.Contains(c.Policies, name)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated client.
+func NewContext(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, client)
+}
+
+// FromContext returns the client authenticated for a request, if any.
+func FromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(contextKey{}).(Client)
+	return client, ok
+}
+
+// ClientName returns the name of the client authenticated for a request,
+// or Anonymous.
+func ClientName(ctx context.Context) string {
+	if client, ok := FromContext(ctx); ok {
+		return client.Name
+	}
+	return Anonymous
+}
+
+// Subject scopes a subject id sent by the client authenticated for ctx to
+// that client, so that a client can only read and write the password
+// history of its own subjects. An empty id stays empty.
+func Subject(ctx context.Context, id string) string {
+	if id == "" {
+		return ""
+	}
+	return ClientName(ctx) + "/" + id
+}
+
+// Keys is a set of API keys and client certificate identities. It is safe
+// for concurrent use.
+type Keys struct {
+	clients      map[[sha256.Size]byte]Client
+	certificates map[string]Client
+}
+
+// keysFile is the layout of the keys file:
+//
+//	keys:
+//	  - client: checkout-web
+//	    hash: sha256:<64 hexadecimal digits>
+//	    policies: [default]
+//	    tier: standard
+//	  - client: billing-service
+//	    certificate: uri:spiffe://payments/billing
+//
+// An entry has either the hash of an API key or the identity of a client
+// certificate, written as cn:, dns:, uri: or email: followed by the subject
+// common name or subject alternative name to match.
+type keysFile struct {
+	Keys []keyEntry `yaml:"keys"`
+}
+
+type keyEntry struct {
+	Client      `yaml:",inline"`
+	Hash        string `yaml:"hash,omitempty"`
+	Certificate string `yaml:"certificate,omitempty"`
+}
+
+var clientNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Load reads a keys file, in YAML or JSON.
+func Load(path string) (*Keys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading keys file: %w", err)
+	}
+
+	keys, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// Parse decodes a keys file, rejecting unknown fields, malformed digests or
+// identities and keys or identities listed twice.
+func Parse(data []byte) (*Keys, error) {
+	var file keysFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decoding keys: %w", err)
+	}
+
+	keys := &Keys{
+		clients:      make(map[[sha256.Size]byte]Client, len(file.Keys)),
+		certificates: make(map[string]Client),
+	}
+	for i, entry := range file.Keys {
+		if !clientNamePattern.MatchString(entry.Name) {
+			return nil, fmt.Errorf("keys[%d]: invalid client name %q", i, entry.Name)
+		}
+		if entry.Tier == "" {
+			entry.Tier = DefaultTier
+		}
+
+		switch {
+		case entry.Hash != "" && entry.Certificate != "":
+			return nil, fmt.Errorf("keys[%d] (%s): set either hash or certificate, not both", i, entry.Name)
+		case entry.Certificate != "":
+			identity, err := parseIdentity(entry.Certificate)
+			if err != nil {
+				return nil, fmt.Errorf("keys[%d] (%s): %w", i, entry.Name, err)
+			}
+			if other, ok := keys.certificates[identity]; ok {
+				return nil, fmt.Errorf("keys[%d] (%s): same certificate as client %s", i, entry.Name, other.Name)
+			}
+			keys.certificates[identity] = entry.Client
+		default:
+			digest, err := parseHash(entry.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("keys[%d] (%s): %w", i, entry.Name, err)
+			}
+			if other, ok := keys.clients[digest]; ok {
+				return nil, fmt.Errorf("keys[%d] (%s): same key as client %s", i, entry.Name, other.Name)
+			}
+			keys.clients[digest] = entry.Client
+		}
+	}
+	return keys, nil
+}
+
+func parseHash(hash string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	encoded, ok := strings.CutPrefix(hash, hashPrefix)
+	if !ok {
+		return digest, fmt.Errorf("hash must start with %q", hashPrefix)
+	}
+	if len(encoded) != hex.EncodedLen(sha256.Size) {
+		return digest, errors.New("hash must be 64 hexadecimal digits")
+	}
+	if _, err := hex.Decode(digest[:], []byte(encoded)); err != nil {
+		return digest, errors.New("hash must be 64 hexadecimal digits")
+	}
+	return digest, nil
+}
+
+// identityKinds are the prefixes of certificate identities, in the order
+// AuthenticateCertificate tries them.
+var identityKinds = []string{"uri", "dns", "email", "cn"}
+
+func parseIdentity(identity string) (string, error) {
+	kind, value, ok := strings.Cut(identity, ":")
+	if !ok || value == "" || !slices.Contains(identityKinds, strings.ToLower(kind)) {
+		return "", fmt.Errorf("certificate must be cn:, dns:, uri: or email: followed by a name, got %q", identity)
+	}
+	return strings.ToLower(kind) + ":" + value, nil
+}
+
+// CertificateIdentities returns the identities of a client certificate, in
+// the form used by the keys file: its URI, DNS and email subject alternative
+// names, then its subject common name.
+func CertificateIdentities(cert *x509.Certificate) []string {
+	var identities []string
+	for _, uri := range cert.URIs {
+		identities = append(identities, "uri:"+uri.String())
+	}
+	for _, name := range cert.DNSNames {
+		identities = append(identities, "dns:"+name)
+	}
+	for _, email := range cert.EmailAddresses {
+		identities = append(identities, "email:"+email)
+	}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, "cn:"+cert.Subject.CommonName)
+	}
+	return identities
+}
+
+// AuthenticateCertificate returns the client mapped to the first identity
+// of cert listed in the keys file. The certificate must already have been
+// verified against the trusted client CAs.
+func (k *Keys) AuthenticateCertificate(cert *x509.Certificate) (Client, bool) {
+	for _, identity := range CertificateIdentities(cert) {
+		if client, ok := k.certificates[identity]; ok {
+			return client, true
+		}
+	}
+	return Client{}, false
+}
+
+// Authenticate returns the client that owns key.
+func (k *Keys) Authenticate(key string) (Client, bool) {
+	if key == "" {
+		return Client{}, false
+	}
+	client, ok := k.clients[sha256.Sum256([]byte(key))]
+	return client, ok
+}
+
+// Clients returns the client of every key and certificate identity, in no
+// particular order.
+func (k *Keys) Clients() []Client {
+	clients := make([]Client, 0, k.Len())
+	for _, client := range k.clients {
+		clients = append(clients, client)
+	}
+	for _, client := range k.certificates {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// Len returns the number of keys and certificate identities.
+func (k *Keys) Len() int {
+	return len(k.clients) + len(k.certificates)
+}
+
+// HasCertificates reports whether any client is identified by certificate.
+func (k *Keys) HasCertificates() bool {
+	return len(k.certificates) > 0
+}
+
+// HashKey returns the digest of key as written in the keys file.
+func HashKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hashPrefix + hex.EncodeToString(digest[:])
+}
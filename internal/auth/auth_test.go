@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`
+keys:
+  - client: checkout-web
+    hash: ` + HashKey("key-1") + `
+    policies: [default]
+    tier: standard
+  - client: checkout-web
+    hash: ` + HashKey("key-2") + `
+  - client: admin-portal
+    hash: sha256:` + strings.ToUpper(strings.TrimPrefix(HashKey("key-3"), "sha256:")) + `
+`)
+	keys, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if keys.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", keys.Len())
+	}
+
+	tests := []struct {
+		key    string
+		want   Client
+		wantOK bool
+	}{
+		{"key-1", Client{Name: "checkout-web", Policies: []string{"default"}, Tier: "standard"}, true},
+		{"key-2", Client{Name: "checkout-web", Tier: DefaultTier}, true},
+		{"key-3", Client{Name: "admin-portal", Tier: DefaultTier}, true},
+		{"key-4", Client{}, false},
+		{"", Client{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := keys.Authenticate(tt.key)
+		if ok != tt.wantOK || got.Name != tt.want.Name || got.Tier != tt.want.Tier || len(got.Policies) != len(tt.want.Policies) {
+			t.Errorf("Authenticate(%q) = %+v, %v, want %+v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown field", "keys:\n  - client: a\n    hash: " + HashKey("k") + "\n    role: admin\n", "field role not found"},
+		{"missing client", "keys:\n  - hash: " + HashKey("k") + "\n", "invalid client name"},
+		{"client name with spaces", "keys:\n  - client: my app\n    hash: " + HashKey("k") + "\n", "invalid client name"},
+		{"short hash", "keys:\n  - client: a\n    hash: sha256:abcd\n", "64 hexadecimal digits"},
+		{"long hash", "keys:\n  - client: a\n    hash: " + HashKey("k") + "00\n", "64 hexadecimal digits"},
+		{"not hexadecimal", "keys:\n  - client: a\n    hash: sha256:" + strings.Repeat("z", 64) + "\n", "64 hexadecimal digits"},
+		{"plain key", "keys:\n  - client: a\n    hash: my-secret-key\n", "must start with"},
+		{"duplicate key", "keys:\n  - client: a\n    hash: " + HashKey("k") + "\n  - client: b\n    hash: " + HashKey("k") + "\n", "same key as client a"},
+		{"hash and certificate", "keys:\n  - client: a\n    hash: " + HashKey("k") + "\n    certificate: cn:a\n", "not both"},
+		{"unknown identity kind", "keys:\n  - client: a\n    certificate: ip:10.0.0.1\n", "cn:, dns:, uri: or email:"},
+		{"empty identity", "keys:\n  - client: a\n    certificate: 'cn:'\n", "cn:, dns:, uri: or email:"},
+		{"duplicate certificate", "keys:\n  - client: a\n    certificate: dns:a.internal\n  - client: b\n    certificate: DNS:a.internal\n", "same certificate as client a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateCertificate(t *testing.T) {
+	keys, err := Parse([]byte(`
+keys:
+  - client: billing-service
+    certificate: uri:spiffe://payments/billing
+    tier: internal
+  - client: reports
+    certificate: dns:reports.internal
+  - client: legacy-batch
+    certificate: cn:legacy-batch
+`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if keys.Len() != 3 || !keys.HasCertificates() {
+		t.Errorf("Len() = %d, HasCertificates() = %v, want 3, true", keys.Len(), keys.HasCertificates())
+	}
+
+	spiffe, _ := url.Parse("spiffe://payments/billing")
+	tests := []struct {
+		name   string
+		cert   *x509.Certificate
+		want   string
+		wantOK bool
+	}{
+		{"URI SAN", &x509.Certificate{URIs: []*url.URL{spiffe}, Subject: pkix.Name{CommonName: "reports"}}, "billing-service", true},
+		{"DNS SAN before the common name", &x509.Certificate{DNSNames: []string{"reports.internal"}, Subject: pkix.Name{CommonName: "legacy-batch"}}, "reports", true},
+		{"common name", &x509.Certificate{Subject: pkix.Name{CommonName: "legacy-batch"}}, "legacy-batch", true},
+		{"unknown certificate", &x509.Certificate{DNSNames: []string{"other.internal"}, Subject: pkix.Name{CommonName: "other"}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, ok := keys.AuthenticateCertificate(tt.cert)
+			if ok != tt.wantOK || client.Name != tt.want {
+				t.Errorf("AuthenticateCertificate() = %+v, %v, want %s, %v", client, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := keys.Authenticate("cn:legacy-batch"); ok {
+		t.Error("a certificate identity authenticated as an API key")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(path, []byte(`{"keys": [{"client": "batch-job", "hash": "`+HashKey("key-1")+`", "tier": "bulk"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if client, ok := keys.Authenticate("key-1"); !ok || client.Tier != "bulk" {
+		t.Errorf("Authenticate() = %+v, %v, want the bulk tier", client, ok)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load() of a missing file succeeded, want an error")
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := FromContext(ctx); ok || ClientName(ctx) != Anonymous {
+		t.Errorf("empty context has a client")
+	}
+
+	ctx = NewContext(ctx, Client{Name: "checkout-web", Policies: []string{"default"}})
+	client, ok := FromContext(ctx)
+	if !ok || ClientName(ctx) != "checkout-web" {
+		t.Errorf("FromContext() = %+v, %v, want checkout-web", client, ok)
+	}
+	if !client.AllowsPolicy("default") || client.AllowsPolicy("admin") {
+		t.Error("AllowsPolicy() does not follow the policies of the key")
+	}
+	if !(Client{Name: "any"}).AllowsPolicy("admin") {
+		t.Error("a key without policies must allow all of them")
+	}
+}
@@ -0,0 +1,81 @@
+// Package bloom implements a fixed-size Bloom filter sized for a target
+// false-positive rate, used to hold large password lists in a few bits per
+// entry.
+package bloom
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// Filter is a Bloom filter. It never reports a false negative; a positive
+// answer is wrong with roughly the probability it was sized for. Filter is
+// safe for concurrent Test calls once all Add calls have returned.
+type Filter struct {
+	bits   []uint64
+	m      uint64
+	k      uint64
+	fpRate float64
+	seed1  maphash.Seed
+	seed2  maphash.Seed
+}
+
+// New sizes a filter for n entries at the given false-positive rate, which
+// must be in (0, 1).
+func New(n uint64, fpRate float64) *Filter {
+	if n == 0 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &Filter{
+		bits:   make([]uint64, m/64),
+		m:      m,
+		k:      k,
+		fpRate: fpRate,
+		seed1:  maphash.MakeSeed(),
+		seed2:  maphash.MakeSeed(),
+	}
+}
+
+// Add inserts key into the filter.
+func (f *Filter) Add(key []byte) {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Test reports whether key may have been added.
+func (f *Filter) Test(key []byte) bool {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SizeBytes is the memory used by the bit array.
+func (f *Filter) SizeBytes() int {
+	return len(f.bits) * 8
+}
+
+// FalsePositiveRate is the rate the filter was sized for.
+func (f *Filter) FalsePositiveRate() float64 {
+	return f.fpRate
+}
+
+// hashes returns the two base hashes for Kirsch-Mitzenmacher double hashing.
+// The seeds are random per filter, so a filter cannot be persisted and
+// reloaded in another process.
+func (f *Filter) hashes(key []byte) (uint64, uint64) {
+	return maphash.Bytes(f.seed1, key), maphash.Bytes(f.seed2, key) | 1
+}
@@ -0,0 +1,59 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFilter_NoFalseNegatives(t *testing.T) {
+	f := New(10000, 0.01)
+	for i := 0; i < 10000; i++ {
+		f.Add([]byte(fmt.Sprintf("password%d", i)))
+	}
+
+	for i := 0; i < 10000; i++ {
+		if !f.Test([]byte(fmt.Sprintf("password%d", i))) {
+			t.Fatalf("Test(password%d) = false, want true", i)
+		}
+	}
+}
+
+func TestFilter_FalsePositiveRate(t *testing.T) {
+	const n = 20000
+	f := New(n, 0.01)
+	for i := 0; i < n; i++ {
+		f.Add([]byte(fmt.Sprintf("in-%d", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if f.Test([]byte(fmt.Sprintf("out-%d", i))) {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / n; rate > 0.02 {
+		t.Errorf("false positive rate = %.4f, want about 0.01", rate)
+	}
+}
+
+func TestNew_Sizing(t *testing.T) {
+	// About 9.6 bits per entry for 1%, 14.4 bits for 0.1%.
+	tests := []struct {
+		fpRate  float64
+		minBits int
+		maxBits int
+	}{
+		{0.01, 9, 10},
+		{0.001, 14, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.fpRate), func(t *testing.T) {
+			bits := New(1_000_000, tt.fpRate).SizeBytes() * 8 / 1_000_000
+			if bits < tt.minBits || bits > tt.maxBits {
+				t.Errorf("bits per entry = %d, want between %d and %d", bits, tt.minBits, tt.maxBits)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package breach provides rules.BreachRangeSource implementations for the
+// breached password check: the Have I Been Pwned range API over HTTP, a
+// local directory of range files, an in-memory set for tests, and a cache
+// that can wrap any of them.
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+// parseRange reads the range format shared by the API and the downloadable
+// corpus: one "SUFFIX:COUNT" entry per line. Padding entries (count 0) are
+// dropped.
+func parseRange(r io.Reader) (map[string]int, error) {
+	suffixes := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		suffix, countText, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed range entry %q", line)
+		}
+		count, err := strconv.Atoi(countText)
+		if err != nil {
+			return nil, fmt.Errorf("malformed count in range entry %q", line)
+		}
+		if count > 0 {
+			suffixes[strings.ToUpper(suffix)] = count
+		}
+	}
+	return suffixes, scanner.Err()
+}
+
+// validPrefix guards against building URLs or file paths from anything but
+// a hash prefix.
+func validPrefix(prefix string) error {
+	if len(prefix) != rules.BreachPrefixLength {
+		return fmt.Errorf("invalid hash prefix %q", prefix)
+	}
+	if _, err := hex.DecodeString(prefix + "0"); err != nil {
+		return fmt.Errorf("invalid hash prefix %q", prefix)
+	}
+	return nil
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,159 @@
+package breach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const (
+	passwordPrefix = "5BAA6"
+	passwordSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+)
+
+func TestHTTPSource(t *testing.T) {
+	var gotPath, gotPadding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotPadding = r.Header.Get("Add-Padding")
+		fmt.Fprintf(w, "%s:3730471\r\n0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n", passwordSuffix)
+	}))
+	defer server.Close()
+
+	suffixes, err := NewHTTPSource(server.URL, time.Second).Range(context.Background(), passwordPrefix)
+	if err != nil {
+		t.Fatalf("Range() unexpected error: %v", err)
+	}
+
+	if gotPath != "/range/"+passwordPrefix || gotPadding != "true" {
+		t.Errorf("request path/padding = %s/%s", gotPath, gotPadding)
+	}
+	if len(suffixes) != 1 || suffixes[passwordSuffix] != 3730471 {
+		t.Errorf("Range() = %v, want only the non-padding suffix", suffixes)
+	}
+}
+
+func TestHTTPSource_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/range/FFFFF" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	source := NewHTTPSource(server.URL, time.Second)
+
+	if _, err := source.Range(context.Background(), passwordPrefix); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("Range() error = %v, want unexpected status 429", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := source.Range(ctx, "FFFFF"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Range() error = %v, want context deadline exceeded", err)
+	}
+
+	if _, err := source.Range(context.Background(), "../etc"); err == nil {
+		t.Error("Range() expected error for invalid prefix")
+	}
+}
+
+func TestDirectorySource(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, passwordPrefix+".txt"), []byte(passwordSuffix+":10\n"), 0o600)
+
+	source, err := NewDirectorySource(dir)
+	if err != nil {
+		t.Fatalf("NewDirectorySource() unexpected error: %v", err)
+	}
+
+	suffixes, err := source.Range(context.Background(), passwordPrefix)
+	if err != nil || suffixes[passwordSuffix] != 10 {
+		t.Errorf("Range() = %v, %v; want %s:10", suffixes, err, passwordSuffix)
+	}
+
+	suffixes, err = source.Range(context.Background(), "00000")
+	if err != nil || len(suffixes) != 0 {
+		t.Errorf("Range() of missing prefix = %v, %v; want empty", suffixes, err)
+	}
+
+	if _, err := NewDirectorySource(filepath.Join(dir, "missing")); err == nil {
+		t.Error("NewDirectorySource() expected error for missing directory")
+	}
+}
+
+func TestMemorySource(t *testing.T) {
+	source := NewMemorySource("password")
+	source.Add("password", 4)
+
+	suffixes, _ := source.Range(context.Background(), passwordPrefix)
+	if suffixes[passwordSuffix] != 5 {
+		t.Errorf("Range() = %v, want %s:5", suffixes, passwordSuffix)
+	}
+}
+
+type countingSource struct {
+	calls int
+	err   error
+}
+
+func (s *countingSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return map[string]int{prefix + "SUFFIX": 1}, nil
+}
+
+func TestCachedSource(t *testing.T) {
+	inner := &countingSource{}
+	cache := NewCachedSource(inner, time.Minute, 2)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	cache.Range(ctx, "AAAAA")
+	cache.Range(ctx, "AAAAA")
+	if inner.calls != 1 {
+		t.Fatalf("inner calls = %d, want 1 (second lookup cached)", inner.calls)
+	}
+
+	// Filling the cache evicts the least recently used prefix.
+	cache.Range(ctx, "BBBBB")
+	cache.Range(ctx, "AAAAA")
+	cache.Range(ctx, "CCCCC")
+	cache.Range(ctx, "AAAAA")
+	if inner.calls != 3 {
+		t.Fatalf("inner calls = %d, want 3", inner.calls)
+	}
+	cache.Range(ctx, "BBBBB")
+	if inner.calls != 4 {
+		t.Fatalf("inner calls = %d, want 4 (BBBBB evicted)", inner.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	cache.Range(ctx, "BBBBB")
+	if inner.calls != 5 {
+		t.Fatalf("inner calls = %d, want 5 (entry expired)", inner.calls)
+	}
+}
+
+func TestCachedSource_DoesNotCacheErrors(t *testing.T) {
+	inner := &countingSource{err: errors.New("unavailable")}
+	cache := NewCachedSource(inner, time.Minute, 10)
+
+	cache.Range(context.Background(), "AAAAA")
+	cache.Range(context.Background(), "AAAAA")
+	if inner.calls != 2 {
+		t.Errorf("inner calls = %d, want 2", inner.calls)
+	}
+}
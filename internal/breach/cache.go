@@ -0,0 +1,99 @@
+package breach
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// CachedSource keeps recent range responses in memory, evicting the least
+// recently used prefix once maxEntries is reached. Errors are never cached.
+type CachedSource struct {
+	source     rules.BreachRangeSource
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	prefix   string
+	suffixes map[string]int
+	expires  time.Time
+}
+
+func NewCachedSource(source rules.BreachRangeSource, ttl time.Duration, maxEntries int) *CachedSource {
+	return &CachedSource{
+		source:     source,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func (c *CachedSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	if suffixes, ok := c.get(prefix); ok {
+		metrics.BreachRangeLookupsTotal.WithLabelValues("cache_hit").Inc()
+		return suffixes, nil
+	}
+
+	suffixes, err := c.source.Range(ctx, prefix)
+	if err != nil {
+		metrics.BreachRangeLookupsTotal.WithLabelValues("error").Inc()
+		return nil, err
+	}
+	metrics.BreachRangeLookupsTotal.WithLabelValues("cache_miss").Inc()
+
+	c.put(prefix, suffixes)
+	return suffixes, nil
+}
+
+func (c *CachedSource) get(prefix string) (map[string]int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[prefix]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.now().After(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, prefix)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	return entry.suffixes, true
+}
+
+func (c *CachedSource) put(prefix string, suffixes map[string]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[prefix]; ok {
+		c.lru.Remove(elem)
+	}
+
+	c.entries[prefix] = c.lru.PushFront(&cacheEntry{
+		prefix:   prefix,
+		suffixes: suffixes,
+		expires:  c.now().Add(c.ttl),
+	})
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).prefix)
+	}
+}
@@ -0,0 +1,48 @@
+package breach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DirectorySource serves ranges from a local copy of the corpus laid out as
+// one "{PREFIX}.txt" file per hash prefix, as produced by the official
+// Pwned Passwords downloader. Nothing is kept in memory.
+type DirectorySource struct {
+	dir string
+}
+
+func NewDirectorySource(dir string) (*DirectorySource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("breach corpus directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breach corpus directory: %s is not a directory", dir)
+	}
+	return &DirectorySource{dir: dir}, nil
+}
+
+func (s *DirectorySource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	if err := validPrefix(prefix); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(s.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseRange(f)
+}
@@ -0,0 +1,56 @@
+package breach
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the public Have I Been Pwned Pwned Passwords API.
+const DefaultAPIURL = "https://api.pwnedpasswords.com"
+
+// HTTPSource queries a Pwned Passwords compatible range API
+// (GET {baseURL}/range/{prefix}).
+type HTTPSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSource creates a source for the API at baseURL. The client timeout
+// is a safety net; per-request deadlines come from the context.
+func NewHTTPSource(baseURL string, timeout time.Duration) *HTTPSource {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &HTTPSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	if err := validPrefix(prefix); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Padding hides the real number of suffixes from network observers.
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "password-validator")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("breach range request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("breach range request failed: unexpected status %d", resp.StatusCode)
+	}
+	return parseRange(resp.Body)
+}
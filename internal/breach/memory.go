@@ -0,0 +1,54 @@
+package breach
+
+import (
+	"context"
+	"sync"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+// MemorySource is an in-memory breach corpus, intended for tests and small
+// deployments.
+type MemorySource struct {
+	mu     sync.RWMutex
+	ranges map[string]map[string]int
+}
+
+// NewMemorySource creates a source containing the given plain-text passwords,
+// each counted once.
+func NewMemorySource(passwords ...string) *MemorySource {
+	s := &MemorySource{ranges: make(map[string]map[string]int)}
+	for _, password := range passwords {
+		s.Add(password, 1)
+	}
+	return s
+}
+
+// Add records a breached password seen count times.
+func (s *MemorySource) Add(password string, count int) {
+	hash := sha1Hex(password)
+	prefix, suffix := hash[:rules.BreachPrefixLength], hash[rules.BreachPrefixLength:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ranges[prefix] == nil {
+		s.ranges[prefix] = make(map[string]int)
+	}
+	s.ranges[prefix][suffix] += count
+}
+
+func (s *MemorySource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	suffixes := make(map[string]int, len(s.ranges[prefix]))
+	for suffix, count := range s.ranges[prefix] {
+		suffixes[suffix] = count
+	}
+	return suffixes, nil
+}
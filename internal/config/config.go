@@ -0,0 +1,338 @@
+// Package config holds the settings of the API server.
+//
+// Settings come from, in increasing order of precedence: the defaults, a
+// YAML config file (-config or CONFIG_FILE), environment variables and
+// command-line flags. Every setting has a key in the file, an environment
+// variable and a flag, given by the tags of its field:
+//
+//	Port int `yaml:"port" env:"PORT" help:"..."`
+//
+// is server.port in the file, PORT in the environment and -server-port on
+// the command line. Fields tagged secret:"true" are redacted when the
+// configuration is printed.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
+	"github.com/willherrera/itau-backend-challenge/internal/api/middleware"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+	"github.com/willherrera/itau-backend-challenge/internal/tlsconfig"
+)
+
+// Config is the configuration of the API server.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	TLS       TLS       `yaml:"tls"`
+	CORS      CORS      `yaml:"cors"`
+	Log       Log       `yaml:"log"`
+	Metrics   Metrics   `yaml:"metrics"`
+	Policy    Policy    `yaml:"policy"`
+	History   History   `yaml:"history"`
+	Auth      Auth      `yaml:"auth"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Limits    Limits    `yaml:"limits"`
+	Features  Features  `yaml:"features"`
+}
+
+// Server holds the listen addresses and the connection lifecycle.
+type Server struct {
+	Host              string        `yaml:"host" env:"HOST" help:"interface to listen on; empty listens on all of them"`
+	Port              int           `yaml:"port" env:"PORT" help:"HTTP API port"`
+	GRPCPort          int           `yaml:"grpc_port" env:"GRPC_PORT" help:"gRPC API port"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" help:"time allowed to read request headers"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" help:"time allowed to read a whole request"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" help:"time allowed to write a response"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" help:"time an idle keep-alive connection stays open"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" help:"time allowed to drain requests on shutdown"`
+	ShutdownDelay     time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" help:"time between failing readiness and closing the listeners"`
+}
+
+// Addr returns the HTTP listen address.
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// GRPCAddr returns the gRPC listen address.
+func (s Server) GRPCAddr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.GRPCPort))
+}
+
+// TLS configures TLS and mutual TLS; see package tlsconfig.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE" help:"server certificate (PEM); enables TLS"`
+	KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE" help:"server private key (PEM)" secret:"true"`
+	ClientCAFile   string        `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE" help:"CAs of client certificates (PEM); enables mutual TLS"`
+	ClientAuth     string        `yaml:"client_auth" env:"TLS_CLIENT_AUTH" help:"client certificates: none, optional or require (default require with a client CA file)"`
+	MinVersion     string        `yaml:"min_version" env:"TLS_MIN_VERSION" help:"minimum TLS version: 1.2 or 1.3"`
+	CipherSuites   []string      `yaml:"cipher_suites" env:"TLS_CIPHER_SUITES" help:"comma-separated TLS 1.2 cipher suites; empty keeps Go's defaults"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL" help:"how often the certificate files are checked for changes; 0 reloads on SIGHUP only"`
+}
+
+// Enabled reports whether the servers use TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// CORS configures the cross-origin requests the browser allows.
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" help:"comma-separated origins allowed to call the API, such as https://app.example.com or https://*.example.com; * allows any; empty allows none"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" help:"let browsers send credentials on cross-origin requests"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" help:"comma-separated request headers browsers may send"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" help:"comma-separated response headers browser scripts may read"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE" help:"how long browsers may cache preflight responses"`
+}
+
+// Policy returns the CORS policy of the HTTP API.
+func (c CORS) Policy() middleware.CORSPolicy {
+	return middleware.CORSPolicy{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowCredentials: c.AllowCredentials,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		MaxAge:           c.MaxAge,
+	}
+}
+
+// Log configures the server logs.
+type Log struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" help:"minimum log level: debug, info, warn or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" help:"log format: text or json"`
+}
+
+// Metrics configures the Prometheus endpoint.
+type Metrics struct {
+	Path string `yaml:"path" env:"METRICS_PATH" help:"path of the Prometheus metrics endpoint"`
+}
+
+// Policy locates the password policy file.
+type Policy struct {
+	File          string        `yaml:"file" env:"POLICY_FILE" help:"password policy file (YAML or JSON); the built-in policy is used when empty"`
+	WatchInterval time.Duration `yaml:"watch_interval" env:"POLICY_WATCH_INTERVAL" help:"how often the policy file is checked for changes; 0 reloads on SIGHUP only"`
+}
+
+// History configures the password history; it is disabled when Store is
+// empty.
+type History struct {
+	Store string `yaml:"store" env:"HISTORY_STORE" help:"password history store: memory or file; empty disables the history"`
+	File  string `yaml:"file" env:"HISTORY_FILE" help:"file of the file history store"`
+	Depth int    `yaml:"depth" env:"HISTORY_DEPTH" help:"passwords remembered per subject"`
+	Hash  string `yaml:"hash" env:"HISTORY_HASH" help:"password history hash: argon2id or bcrypt"`
+}
+
+// Auth configures the authentication of API clients.
+type Auth struct {
+	KeysFile string `yaml:"keys_file" env:"API_KEYS_FILE" help:"API keys file; empty disables authentication" secret:"true"`
+}
+
+// RateLimit configures the token bucket limits of the API.
+type RateLimit struct {
+	IP             string   `yaml:"ip" env:"RATE_LIMIT_IP" help:"limit per client address, such as 20/s:40, or unlimited"`
+	Tiers          string   `yaml:"tiers" env:"RATE_LIMIT_TIERS" help:"limits per API key tier, such as default=50/s:100,internal=unlimited"`
+	Items          string   `yaml:"items" env:"RATE_LIMIT_ITEMS" help:"passwords of batch and stream requests validated per client, or per address without authentication; the burst must fit limits.batch_max_items"`
+	MaxKeys        int      `yaml:"max_keys" env:"RATE_LIMIT_MAX_KEYS" help:"rate limit buckets kept in memory"`
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES" help:"comma-separated CIDRs of proxies whose X-Forwarded-For is trusted"`
+}
+
+// Limits bounds the batch and stream endpoints.
+type Limits struct {
+	BatchMaxItems      int `yaml:"batch_max_items" env:"BATCH_MAX_ITEMS" help:"passwords per batch request"`
+	BatchWorkers       int `yaml:"batch_workers" env:"BATCH_WORKERS" help:"passwords of a batch validated in parallel; 0 means GOMAXPROCS"`
+	StreamMaxLineBytes int `yaml:"stream_max_line_bytes" env:"STREAM_MAX_LINE_BYTES" help:"longest line of a stream request, in bytes"`
+	StreamMaxLines     int `yaml:"stream_max_lines" env:"STREAM_MAX_LINES" help:"lines read from a stream request"`
+
+	StreamLineTimeout time.Duration `yaml:"stream_line_timeout" env:"STREAM_LINE_TIMEOUT" help:"time allowed to receive each line of a stream request and to write each result"`
+	StreamMaxDuration time.Duration `yaml:"stream_max_duration" env:"STREAM_MAX_DURATION" help:"time a stream request body is read for"`
+}
+
+// Features turns optional parts of the server on or off.
+type Features struct {
+	GRPC    bool `yaml:"grpc" env:"GRPC_ENABLED" help:"serve the gRPC API"`
+	Swagger bool `yaml:"swagger" env:"SWAGGER_ENABLED" help:"serve the Swagger UI"`
+	Metrics bool `yaml:"metrics" env:"METRICS_ENABLED" help:"serve the Prometheus metrics"`
+}
+
+// Defaults of the settings that are not plain zero values.
+const (
+	DefaultPort           = 8080
+	DefaultGRPCPort       = 9090
+	DefaultIPRateLimit    = "20/s:40"
+	DefaultItemRateLimit  = "100/s:1000"
+	DefaultRateLimitTiers = "default=50/s:100"
+)
+
+// Default returns the configuration used when nothing is set.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Port:              DefaultPort,
+			GRPCPort:          DefaultGRPCPort,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		TLS: TLS{
+			MinVersion:     "1.2",
+			ReloadInterval: time.Minute,
+		},
+		CORS: CORS{
+			AllowedHeaders: []string{"Content-Type", "X-API-Key", "Accept-Language"},
+			ExposedHeaders: []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+			MaxAge:         10 * time.Minute,
+		},
+		Log:     Log{Level: "info", Format: "text"},
+		Metrics: Metrics{Path: "/metrics"},
+		History: History{Depth: history.DefaultDepth, Hash: "argon2id"},
+		RateLimit: RateLimit{
+			IP:      DefaultIPRateLimit,
+			Items:   DefaultItemRateLimit,
+			Tiers:   DefaultRateLimitTiers,
+			MaxKeys: ratelimit.DefaultMaxKeys,
+		},
+		Limits: Limits{
+			BatchMaxItems:      handlers.DefaultBatchMaxItems,
+			StreamMaxLineBytes: handlers.DefaultStreamMaxLineBytes,
+			StreamMaxLines:     handlers.DefaultStreamMaxLines,
+			StreamLineTimeout:  handlers.DefaultStreamLineTimeout,
+			StreamMaxDuration:  handlers.DefaultStreamMaxDuration,
+		},
+		Features: Features{GRPC: true, Swagger: true, Metrics: true},
+	}
+}
+
+// Validate checks every setting and reports all the invalid ones, each
+// named by its file key, environment variable and flag.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(path string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", describe(path), err))
+		}
+	}
+	positive := func(path string, n int) {
+		if n < 1 {
+			check(path, fmt.Errorf("must be a positive integer, got %d", n))
+		}
+	}
+	port := func(path string, n int) {
+		if n < 1 || n > 65535 {
+			check(path, fmt.Errorf("must be between 1 and 65535, got %d", n))
+		}
+	}
+	oneOf := func(path, value string, allowed ...string) {
+		if !slices.Contains(allowed, value) {
+			check(path, fmt.Errorf("must be %s, got %q", strings.Join(allowed, ", "), value))
+		}
+	}
+
+	port("server.port", c.Server.Port)
+	if c.Features.GRPC {
+		port("server.grpc_port", c.Server.GRPCPort)
+		if c.Server.GRPCPort == c.Server.Port {
+			check("server.grpc_port", errors.New("must differ from server.port"))
+		}
+	}
+	for path, d := range map[string]time.Duration{
+		"server.read_header_timeout": c.Server.ReadHeaderTimeout,
+		"server.read_timeout":        c.Server.ReadTimeout,
+		"server.write_timeout":       c.Server.WriteTimeout,
+		"server.idle_timeout":        c.Server.IdleTimeout,
+		"server.shutdown_timeout":    c.Server.ShutdownTimeout,
+		"server.shutdown_delay":      c.Server.ShutdownDelay,
+		"tls.reload_interval":        c.TLS.ReloadInterval,
+		"cors.max_age":               c.CORS.MaxAge,
+		"policy.watch_interval":      c.Policy.WatchInterval,
+	} {
+		if d < 0 {
+			check(path, fmt.Errorf("must not be negative, got %v", d))
+		}
+	}
+
+	if c.TLS.Enabled() {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			check("tls.cert_file", errors.New("tls.cert_file and tls.key_file must be set together"))
+		}
+		_, err := tlsconfig.ParseVersion(c.TLS.MinVersion)
+		check("tls.min_version", err)
+		_, err = tlsconfig.ParseCipherSuites(strings.Join(c.TLS.CipherSuites, ","))
+		check("tls.cipher_suites", err)
+		if c.TLS.ClientAuth != "" {
+			_, err = tlsconfig.ParseClientAuth(c.TLS.ClientAuth)
+			check("tls.client_auth", err)
+		}
+	} else if c.TLS.ClientCAFile != "" {
+		check("tls.client_ca_file", errors.New("requires tls.cert_file and tls.key_file"))
+	}
+
+	check("cors.allowed_origins", c.CORS.Policy().Validate())
+
+	oneOf("log.level", c.Log.Level, "debug", "info", "warn", "error")
+	oneOf("log.format", c.Log.Format, "text", "json")
+
+	if c.Features.Metrics && !strings.HasPrefix(c.Metrics.Path, "/") {
+		check("metrics.path", fmt.Errorf("must start with /, got %q", c.Metrics.Path))
+	}
+
+	if c.History.Store != "" {
+		oneOf("history.store", c.History.Store, "memory", "file")
+		if c.History.Store == "file" && c.History.File == "" {
+			check("history.file", errors.New("is required for the file store"))
+		}
+		positive("history.depth", c.History.Depth)
+		oneOf("history.hash", c.History.Hash, "argon2id", "bcrypt")
+	}
+
+	_, err := ratelimit.ParseLimit(c.RateLimit.IP)
+	check("rate_limit.ip", err)
+	_, err = ratelimit.ParseTiers(c.RateLimit.Tiers)
+	check("rate_limit.tiers", err)
+	items, err := ratelimit.ParseLimit(c.RateLimit.Items)
+	check("rate_limit.items", err)
+	if err == nil && !items.Unlimited() && items.Burst < c.Limits.BatchMaxItems {
+		check("rate_limit.items", fmt.Errorf("burst %d is smaller than limits.batch_max_items (%d), so the largest batches would always be rejected", items.Burst, c.Limits.BatchMaxItems))
+	}
+	positive("rate_limit.max_keys", c.RateLimit.MaxKeys)
+	_, err = c.RateLimit.Proxies()
+	check("rate_limit.trusted_proxies", err)
+
+	positive("limits.batch_max_items", c.Limits.BatchMaxItems)
+	if c.Limits.BatchWorkers < 0 {
+		check("limits.batch_workers", fmt.Errorf("must not be negative, got %d", c.Limits.BatchWorkers))
+	}
+	positive("limits.stream_max_line_bytes", c.Limits.StreamMaxLineBytes)
+	positive("limits.stream_max_lines", c.Limits.StreamMaxLines)
+	for path, d := range map[string]time.Duration{
+		"limits.stream_line_timeout": c.Limits.StreamLineTimeout,
+		"limits.stream_max_duration": c.Limits.StreamMaxDuration,
+	} {
+		if d <= 0 {
+			check(path, fmt.Errorf("must be greater than zero, got %v", d))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Proxies parses the trusted proxy CIDRs.
+func (r RateLimit) Proxies() ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, cidr := range r.TrustedProxies {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, prefix)
+	}
+	return proxies, nil
+}
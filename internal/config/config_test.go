@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, printConfig, err := Load("api", nil, env(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if printConfig {
+		t.Error("printConfig = true without -print-config")
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want the defaults", cfg)
+	}
+	if cfg.Server.Addr() != ":8080" || cfg.Server.GRPCAddr() != ":9090" {
+		t.Errorf("addresses = %s, %s, want :8080, :9090", cfg.Server.Addr(), cfg.Server.GRPCAddr())
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+server:
+  port: 7000
+  grpc_port: 7001
+  read_timeout: 10s
+log:
+  level: debug
+  format: json
+cors:
+  allowed_origins: [https://app.example.com]
+features:
+  swagger: false
+history:
+  store: memory
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := Load("api",
+		[]string{"-log-level", "warn", "-features-grpc=false", "-rate-limit-trusted-proxies", "10.0.0.0/8"},
+		env(map[string]string{
+			FileEnv:                path,
+			"PORT":                 "7100",
+			"LOG_LEVEL":            "error",
+			"CORS_ALLOWED_ORIGINS": "https://a.example.com, https://b.example.com",
+			"TRUSTED_PROXIES":      "192.168.0.0/16",
+			"HISTORY_STORE":        "",
+		}),
+		io.Discard)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		got, want any
+	}{
+		{"file over default", cfg.Server.GRPCPort, 7001},
+		{"file duration", cfg.Server.ReadTimeout, 10 * time.Second},
+		{"env over file", cfg.Server.Port, 7100},
+		{"flag over env and file", cfg.Log.Level, "warn"},
+		{"file only", cfg.Log.Format, "json"},
+		{"env list over file", cfg.CORS.AllowedOrigins, []string{"https://a.example.com", "https://b.example.com"}},
+		{"flag list over env", cfg.RateLimit.TrustedProxies, []string{"10.0.0.0/8"}},
+		{"file boolean", cfg.Features.Swagger, false},
+		{"flag boolean", cfg.Features.GRPC, false},
+		{"default kept", cfg.Server.WriteTimeout, 60 * time.Second},
+		{"empty env over file", cfg.History.Store, ""},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// The -config flag wins over CONFIG_FILE.
+	if _, _, err := Load("api", []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(map[string]string{FileEnv: path}), io.Discard); err == nil {
+		t.Error("Load() with a missing -config file succeeded, want an error")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	file := func(content string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want []string
+	}{
+		{"unknown file key", nil, map[string]string{FileEnv: file("server:\n  prot: 80\n")}, []string{"field prot not found"}},
+		{"malformed env", nil, map[string]string{"PORT": "http", "SHUTDOWN_TIMEOUT": "30"}, []string{`PORT: invalid integer "http"`, "SHUTDOWN_TIMEOUT: invalid duration"}},
+		{"malformed flag", []string{"-features-grpc=maybe"}, nil, []string{"invalid boolean"}},
+		{"unknown flag", []string{"-port", "80"}, nil, []string{"flag provided but not defined"}},
+		{"positional argument", []string{"serve"}, nil, []string{`unexpected argument "serve"`}},
+		{"every invalid setting is reported", nil, map[string]string{
+			"PORT":            "70000",
+			"LOG_FORMAT":      "xml",
+			"RATE_LIMIT_IP":   "fast",
+			"TRUSTED_PROXIES": "10.0.0.1",
+			"HISTORY_STORE":   "file",
+			"BATCH_WORKERS":   "-1",
+		}, []string{
+			"server.port (PORT, -server-port): must be between 1 and 65535",
+			"log.format (LOG_FORMAT, -log-format)",
+			"rate_limit.ip (RATE_LIMIT_IP, -rate-limit-ip)",
+			"rate_limit.trusted_proxies",
+			"history.file (HISTORY_FILE, -history-file): is required",
+			"limits.batch_workers",
+		}},
+		{"item burst smaller than a batch", nil, map[string]string{"RATE_LIMIT_ITEMS": "10/s:100"}, []string{"rate_limit.items (RATE_LIMIT_ITEMS, -rate-limit-items): burst 100 is smaller than limits.batch_max_items (1000)"}},
+		{"stream timeouts", nil, map[string]string{"STREAM_LINE_TIMEOUT": "0s"}, []string{"limits.stream_line_timeout (STREAM_LINE_TIMEOUT, -limits-stream-line-timeout): must be greater than zero"}},
+		{"same ports", nil, map[string]string{"GRPC_PORT": "8080"}, []string{"must differ from server.port"}},
+		{"TLS key without certificate", nil, map[string]string{"TLS_KEY_FILE": "tls.key"}, []string{"set together"}},
+		{"TLS settings", nil, map[string]string{"TLS_CERT_FILE": "tls.crt", "TLS_KEY_FILE": "tls.key", "TLS_MIN_VERSION": "1.0", "TLS_CLIENT_AUTH": "maybe"}, []string{"tls.min_version", "tls.client_auth"}},
+		{"client CAs without TLS", nil, map[string]string{"TLS_CLIENT_CA_FILE": "ca.crt"}, []string{"requires tls.cert_file"}},
+		{"metrics path", []string{"-metrics-path", "metrics"}, nil, []string{"must start with /"}},
+		{"CORS origins", nil, map[string]string{"CORS_ALLOWED_ORIGINS": "*, app.example.com", "CORS_ALLOW_CREDENTIALS": "true", "CORS_MAX_AGE": "-1m"}, []string{
+			`cors.allowed_origins (CORS_ALLOWED_ORIGINS, -cors-allowed-origins): origin "*" cannot be combined with credentials`,
+			`invalid origin "app.example.com"`,
+			"cors.max_age",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Load("api", tt.args, env(tt.env), io.Discard)
+			if err == nil {
+				t.Fatal("Load() succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	var out bytes.Buffer
+	_, _, err := Load("api", []string{"-h"}, env(nil), &out)
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("Load(-h) error = %v, want flag.ErrHelp", err)
+	}
+	for _, want := range []string{"-server-port", "(env PORT)", "(default 8080)", "-print-config", "-config"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("usage does not mention %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var envs, flags []string
+	for _, s := range settings {
+		if s.env == "" || s.help == "" {
+			t.Errorf("setting %s has no env or help tag", s.path)
+		}
+		envs = append(envs, s.env)
+		flags = append(flags, s.flag)
+	}
+	slices.Sort(envs)
+	slices.Sort(flags)
+	if len(slices.Compact(envs)) != len(settings) || len(slices.Compact(flags)) != len(settings) {
+		t.Error("two settings share an environment variable or a flag")
+	}
+}
+
+func TestPrint(t *testing.T) {
+	cfg, printConfig, err := Load("api", []string{"-print-config", "-server-port", "7000"}, env(nil), io.Discard)
+	if err != nil || !printConfig {
+		t.Fatalf("Load() = %v, %v, want -print-config", printConfig, err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Print() error: %v", err)
+	}
+
+	// The output is a config file that loads back to the same settings.
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, out.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, _, err := Load("api", []string{"-config", path}, env(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("Load() of the printed config error: %v", err)
+	}
+	var again bytes.Buffer
+	reloaded.Print(&again)
+	if again.String() != out.String() || reloaded.Server.Port != 7000 {
+		t.Errorf("printed config loads as:\n%s\nwant:\n%s", again.String(), out.String())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	type credentials struct {
+		User     string `yaml:"user" env:"USER"`
+		Password string `yaml:"password" env:"PASSWORD" secret:"true"`
+		Token    string `yaml:"token" env:"TOKEN" secret:"true"`
+	}
+	type settingsFile struct {
+		Database credentials `yaml:"database"`
+	}
+
+	v := settingsFile{Database: credentials{User: "app", Password: "hunter2"}}
+	redact(reflect.ValueOf(&v).Elem(), settingsOf(reflect.TypeFor[settingsFile](), "", nil))
+
+	want := credentials{User: "app", Password: redacted}
+	if v.Database != want {
+		t.Errorf("redacted = %+v, want %+v", v.Database, want)
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg, _, err := Load("api", nil, env(map[string]string{
+		"TLS_CERT_FILE": "/etc/tls/server.crt",
+		"TLS_KEY_FILE":  "/etc/tls/server.key",
+		"API_KEYS_FILE": "/etc/api/keys.yaml",
+	}), io.Discard)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Print() error: %v", err)
+	}
+	for _, secret := range []string{"/etc/tls/server.key", "/etc/api/keys.yaml"} {
+		if strings.Contains(out.String(), secret) {
+			t.Errorf("printed config contains %q:\n%s", secret, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "/etc/tls/server.crt") {
+		t.Errorf("printed config lacks the certificate file:\n%s", out.String())
+	}
+	if cfg.TLS.KeyFile != "/etc/tls/server.key" {
+		t.Errorf("Print() changed the config: TLS.KeyFile = %q", cfg.TLS.KeyFile)
+	}
+}
+
+func TestExampleFile(t *testing.T) {
+	cfg, _, err := Load("api", []string{"-config", "../../configs/config.yaml"}, env(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	// The example documents every setting with its default value.
+	var got, want bytes.Buffer
+	cfg.Print(&got)
+	Default().Print(&want)
+	if got.String() != want.String() {
+		t.Errorf("configs/config.yaml loads as:\n%s\nwant the defaults:\n%s", got.String(), want.String())
+	}
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// FileEnv is the environment variable that names the config file when the
+// -config flag is not given.
+const FileEnv = "CONFIG_FILE"
+
+// redacted replaces the value of secret settings when the configuration is
+// printed.
+const redacted = "REDACTED"
+
+// setting is a leaf field of Config, with its names in the file, the
+// environment and the command line.
+type setting struct {
+	path   string
+	env    string
+	flag   string
+	help   string
+	secret bool
+	index  []int
+}
+
+var settings = settingsOf(reflect.TypeFor[Config](), "", nil)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// settingsOf lists the leaf fields of the struct type t, whose keys are
+// prefixed with prefix and whose field indexes start with index.
+func settingsOf(t reflect.Type, prefix string, index []int) []setting {
+	var list []setting
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		path := prefix + key
+		fieldIndex := append(slices.Clone(index), i)
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			list = append(list, settingsOf(field.Type, path+".", fieldIndex)...)
+			continue
+		}
+		list = append(list, setting{
+			path:   path,
+			env:    field.Tag.Get("env"),
+			flag:   strings.NewReplacer(".", "-", "_", "-").Replace(path),
+			help:   field.Tag.Get("help"),
+			secret: field.Tag.Get("secret") == "true",
+			index:  fieldIndex,
+		})
+	}
+	return list
+}
+
+// describe names a setting by its file key, environment variable and flag,
+// for error messages.
+func describe(path string) string {
+	for _, s := range settings {
+		if s.path == path {
+			return fmt.Sprintf("%s (%s, -%s)", s.path, s.env, s.flag)
+		}
+	}
+	return path
+}
+
+// set parses value into field. Lists are comma-separated.
+func set(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, use a value such as 30s or 1m", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, use true or false", value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// format returns the value of field as it is written in the environment or
+// on the command line.
+func format(field reflect.Value) string {
+	if field.Kind() == reflect.Slice {
+		return strings.Join(field.Interface().([]string), ",")
+	}
+	return fmt.Sprint(field.Interface())
+}
+
+// flagValue is the flag of a setting. Flags are recorded while the command
+// line is parsed and applied after the file and the environment, which they
+// override.
+type flagValue struct {
+	setting *setting
+	def     string
+	isBool  bool
+	values  *[]flagAssignment
+}
+
+type flagAssignment struct {
+	setting *setting
+	value   string
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.def
+}
+
+func (f *flagValue) Set(value string) error {
+	*f.values = append(*f.values, flagAssignment{f.setting, value})
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// Load builds the configuration from the defaults, the config file, the
+// environment, read with lookupEnv, and the command-line args, in increasing
+// order of precedence, and validates it. It reports whether -print-config
+// was given. When args ask for help, it writes the usage to output and
+// returns flag.ErrHelp.
+func Load(name string, args []string, lookupEnv func(string) (string, bool), output io.Writer) (cfg *Config, printConfig bool, err error) {
+	cfg = Default()
+	defaults := reflect.ValueOf(cfg).Elem()
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprintf(output, "Usage: %s [flags]\n\n", name)
+		fmt.Fprintf(output, "Settings come from the defaults, the config file (-config or %s), the\n", FileEnv)
+		fmt.Fprintln(output, "environment and these flags, in increasing order of precedence.")
+		fmt.Fprintln(output)
+		flags.PrintDefaults()
+	}
+
+	var path string
+	var assignments []flagAssignment
+	flags.StringVar(&path, "config", "", "YAML config file (default $"+FileEnv+")")
+	flags.BoolVar(&printConfig, "print-config", false, "print the effective configuration, with secrets redacted, and exit")
+	for i := range settings {
+		s := &settings[i]
+		field := defaults.FieldByIndex(s.index)
+		flags.Var(&flagValue{
+			setting: s,
+			def:     format(field),
+			isBool:  field.Kind() == reflect.Bool,
+			values:  &assignments,
+		}, s.flag, s.help+" (env "+s.env+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, false, err
+	}
+	if flags.NArg() > 0 {
+		return nil, false, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if path == "" {
+		path, _ = lookupEnv(FileEnv)
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		// A variable set to an empty string still overrides the file, e.g.
+		// HISTORY_STORE= disables a history the file enables.
+		if value, ok := lookupEnv(s.env); ok {
+			if err := set(defaults.FieldByIndex(s.index), value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	for _, a := range assignments {
+		if err := set(defaults.FieldByIndex(a.setting.index), a.value); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", a.setting.flag, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, false, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, false, err
+	}
+	return cfg, printConfig, nil
+}
+
+// loadFile reads a YAML config file over c, rejecting unknown keys.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Print writes the configuration as a YAML config file, with the value of
+// secret settings replaced by REDACTED.
+func (c *Config) Print(w io.Writer) error {
+	printed := *c
+	redact(reflect.ValueOf(&printed).Elem(), settings)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&printed); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// redact replaces the non-empty secret settings of v.
+func redact(v reflect.Value, list []setting) {
+	for _, s := range list {
+		if field := v.FieldByIndex(s.index); s.secret && field.Kind() == reflect.String && field.String() != "" {
+			field.SetString(redacted)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package rules
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/willherrera/itau-backend-challenge/internal/bloom"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleBlocklist           = "blocklist"
+	CodeBlocklistedPassword = "BLOCKLISTED_PASSWORD"
+)
+
+// BlocklistFormat is the layout of a blocklist file.
+type BlocklistFormat string
+
+const (
+	// BlocklistPlain is one plain-text password per line.
+	BlocklistPlain BlocklistFormat = "plain"
+	// BlocklistSHA1 is one hex SHA-1 hash per line, optionally followed by
+	// ":COUNT" as in the Have I Been Pwned downloads.
+	BlocklistSHA1 BlocklistFormat = "sha1"
+)
+
+const DefaultBlocklistFalsePositiveRate = 0.001
+
+// BlocklistValidator rejects passwords found in a local list of common or
+// breached passwords. Entries are kept in a Bloom filter of SHA-1 hashes, so
+// memory grows by about 14 bits per entry at the default false-positive rate
+// regardless of password length, and a few passwords not in the list are
+// rejected as if they were.
+type BlocklistValidator struct {
+	filter  *bloom.Filter
+	entries int
+}
+
+// LoadBlocklistValidator reads the list at path. The file is read twice, once
+// to size the filter and once to fill it, so it is never held in memory.
+func LoadBlocklistValidator(path string, format BlocklistFormat, fpRate float64) (*BlocklistValidator, error) {
+	if format != BlocklistPlain && format != BlocklistSHA1 {
+		return nil, fmt.Errorf("unsupported blocklist format %q", format)
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		return nil, fmt.Errorf("false-positive rate must be between 0 and 1, got %v", fpRate)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening blocklist: %w", err)
+	}
+	defer f.Close()
+
+	lines, err := countLines(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading blocklist %s: %w", path, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("reading blocklist %s: %w", path, err)
+	}
+
+	v := &BlocklistValidator{filter: bloom.New(uint64(lines), fpRate)}
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := bytes.TrimSuffix(scanner.Bytes(), []byte("\r"))
+		if len(line) == 0 {
+			continue
+		}
+
+		var key [sha1.Size]byte
+		if format == BlocklistSHA1 {
+			if hash, _, _ := bytes.Cut(line, []byte(":")); len(hash) != 2*sha1.Size {
+				return nil, fmt.Errorf("blocklist %s line %d: expected a 40 character SHA-1 hash", path, lineNo)
+			} else if _, err := hex.Decode(key[:], hash); err != nil {
+				return nil, fmt.Errorf("blocklist %s line %d: %w", path, lineNo, err)
+			}
+		} else {
+			key = sha1.Sum(line)
+		}
+
+		v.filter.Add(key[:])
+		v.entries++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading blocklist %s: %w", path, err)
+	}
+
+	return v, nil
+}
+
+func countLines(r io.Reader) (int, error) {
+	buf := make([]byte, 256*1024)
+	lines := 1
+	for {
+		n, err := r.Read(buf)
+		lines += bytes.Count(buf[:n], []byte("\n"))
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Entries is the number of lines loaded into the filter.
+func (v *BlocklistValidator) Entries() int {
+	return v.entries
+}
+
+// SizeBytes is the memory held by the filter.
+func (v *BlocklistValidator) SizeBytes() int {
+	return v.filter.SizeBytes()
+}
+
+func (v *BlocklistValidator) Validate(password string) error {
+	key := sha1.Sum([]byte(password))
+	if v.filter.Test(key[:]) {
+		return domain.NewViolation(CodeBlocklistedPassword, RuleBlocklist, "password is too common or has appeared in a data breach")
+	}
+	return nil
+}
+
+func (v *BlocklistValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleBlocklist,
+		Codes:       []string{CodeBlocklistedPassword},
+		Description: "Password must not appear in the list of common or breached passwords",
+		Params: map[string]any{
+			"entries":           v.entries,
+			"falsePositiveRate": v.filter.FalsePositiveRate(),
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+func writeBlocklist(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBlocklistValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   BlocklistFormat
+		content  string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "valid password not in plain list",
+			format:   BlocklistPlain,
+			content:  "123456\npassword\r\nqwerty\n",
+			password: "AbTp9!fok",
+			wantErr:  false,
+		},
+		{
+			name:     "invalid password in plain list",
+			format:   BlocklistPlain,
+			content:  "123456\npassword\r\nqwerty\n",
+			password: "password",
+			wantErr:  true,
+		},
+		{
+			name:     "plain list is case sensitive",
+			format:   BlocklistPlain,
+			content:  "password\n",
+			password: "Password",
+			wantErr:  false,
+		},
+		{
+			name:     "invalid password in sha1 list with counts",
+			format:   BlocklistSHA1,
+			content:  "7C4A8D09CA3762AF61E59520943DC26494F8941B:37359195\n5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8:9545824\n",
+			password: "password",
+			wantErr:  true,
+		},
+		{
+			name:     "valid password not in sha1 list",
+			format:   BlocklistSHA1,
+			content:  "7C4A8D09CA3762AF61E59520943DC26494F8941B\n",
+			password: "AbTp9!fok",
+			wantErr:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := LoadBlocklistValidator(writeBlocklist(t, tt.content), tt.format, DefaultBlocklistFalsePositiveRate)
+			if err != nil {
+				t.Fatalf("LoadBlocklistValidator() unexpected error: %v", err)
+			}
+
+			err = validator.Validate(tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BlocklistValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && domain.AsViolation(err).Code != CodeBlocklistedPassword {
+				t.Errorf("violation code = %s, want %s", domain.AsViolation(err).Code, CodeBlocklistedPassword)
+			}
+		})
+	}
+}
+
+func TestLoadBlocklistValidator_Entries(t *testing.T) {
+	validator, err := LoadBlocklistValidator(writeBlocklist(t, "a\n\nb\nc"), BlocklistPlain, 0.01)
+	if err != nil {
+		t.Fatalf("LoadBlocklistValidator() unexpected error: %v", err)
+	}
+	if validator.Entries() != 3 {
+		t.Errorf("Entries() = %d, want 3", validator.Entries())
+	}
+}
+
+func TestLoadBlocklistValidator_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		format  BlocklistFormat
+		fpRate  float64
+		wantErr string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.txt"), BlocklistPlain, 0.01, "opening blocklist"},
+		{"unknown format", writeBlocklist(t, "a\n"), "csv", 0.01, "unsupported blocklist format"},
+		{"bad rate", writeBlocklist(t, "a\n"), BlocklistPlain, 1, "false-positive rate"},
+		{"malformed hash", writeBlocklist(t, "5BAA6\n"), BlocklistSHA1, 0.01, "line 1"},
+		{"non hex hash", writeBlocklist(t, strings.Repeat("Z", 40)+"\n"), BlocklistSHA1, 0.01, "line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadBlocklistValidator(tt.path, tt.format, tt.fpRate)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBlocklistValidator() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleBreached               = "breached"
+	CodeBreachedPassword       = "BREACHED_PASSWORD"
+	CodeBreachCheckUnavailable = "BREACH_CHECK_UNAVAILABLE"
+)
+
+const (
+	BreachPrefixLength         = 5
+	DefaultBreachLookupTimeout = 2 * time.Second
+)
+
+// BreachRangeSource looks up breached password hashes using k-anonymity:
+// only the first BreachPrefixLength hex characters of the SHA-1 hash are
+// sent, and the source answers with every known suffix for that prefix.
+type BreachRangeSource interface {
+	// Range returns the occurrence count of each breached hash suffix (the
+	// remaining 35 upper-case hex characters) sharing the given prefix.
+	Range(ctx context.Context, prefix string) (map[string]int, error)
+}
+
+// BreachedValidator rejects passwords found in a breach corpus, as required
+// by NIST SP 800-63B. When the source cannot be reached, the password is
+// accepted (fail-open) or rejected (fail-closed) depending on configuration.
+type BreachedValidator struct {
+	source   BreachRangeSource
+	timeout  time.Duration
+	failOpen bool
+}
+
+func NewBreachedValidator(source BreachRangeSource, timeout time.Duration, failOpen bool) *BreachedValidator {
+	if timeout <= 0 {
+		timeout = DefaultBreachLookupTimeout
+	}
+	return &BreachedValidator{
+		source:   source,
+		timeout:  timeout,
+		failOpen: failOpen,
+	}
+}
+
+func (v *BreachedValidator) Validate(password string) error {
+	return v.ValidateContext(context.Background(), password)
+}
+
+func (v *BreachedValidator) ValidateContext(ctx context.Context, password string) error {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:BreachPrefixLength], hash[BreachPrefixLength:]
+
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	suffixes, err := v.source.Range(ctx, prefix)
+	if err != nil {
+		// The error names upstream hosts and network details, so it is
+		// logged rather than returned to API clients.
+		slog.Warn("Breached password lookup failed", "error", err, "fail_open", v.failOpen)
+		if v.failOpen {
+			return nil
+		}
+		return domain.NewViolation(
+			CodeBreachCheckUnavailable,
+			RuleBreached,
+			"password could not be checked against breached passwords",
+		)
+	}
+
+	if count := suffixes[suffix]; count > 0 {
+		return domain.NewViolation(CodeBreachedPassword, RuleBreached, "password has appeared in a data breach and must not be used").
+			WithParam("count", count)
+	}
+	return nil
+}
+
+func (v *BreachedValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleBreached,
+		Codes:       []string{CodeBreachedPassword, CodeBreachCheckUnavailable},
+		Description: "Password must not appear in known data breaches",
+		Params:      map[string]any{"failOpen": v.failOpen},
+	}
+}
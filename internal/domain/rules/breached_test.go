@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+type stubRangeSource struct {
+	breached map[string]int
+	err      error
+	block    bool
+	prefixes []string
+}
+
+func (s *stubRangeSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	s.prefixes = append(s.prefixes, prefix)
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	suffixes := make(map[string]int)
+	for password, count := range s.breached {
+		sum := sha1.Sum([]byte(password))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		if hash[:BreachPrefixLength] == prefix {
+			suffixes[hash[BreachPrefixLength:]] = count
+		}
+	}
+	return suffixes, nil
+}
+
+func TestBreachedValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *stubRangeSource
+		failOpen bool
+		password string
+		wantCode string
+	}{
+		{
+			name:     "valid password not breached",
+			source:   &stubRangeSource{breached: map[string]int{"password": 10}},
+			password: "AbTp9!fok",
+		},
+		{
+			name:     "invalid breached password",
+			source:   &stubRangeSource{breached: map[string]int{"password": 10}},
+			password: "password",
+			wantCode: CodeBreachedPassword,
+		},
+		{
+			name:     "source error with fail-open accepts",
+			source:   &stubRangeSource{err: errors.New("connection refused")},
+			failOpen: true,
+			password: "password",
+		},
+		{
+			name:     "source error with fail-closed rejects",
+			source:   &stubRangeSource{err: errors.New("connection refused")},
+			failOpen: false,
+			password: "AbTp9!fok",
+			wantCode: CodeBreachCheckUnavailable,
+		},
+		{
+			name:     "timeout with fail-closed rejects",
+			source:   &stubRangeSource{block: true},
+			failOpen: false,
+			password: "AbTp9!fok",
+			wantCode: CodeBreachCheckUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewBreachedValidator(tt.source, 20*time.Millisecond, tt.failOpen)
+
+			err := validator.Validate(tt.password)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("BreachedValidator.Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("BreachedValidator.Validate() expected %s violation", tt.wantCode)
+			}
+			v := domain.AsViolation(err)
+			if v.Code != tt.wantCode || v.Rule != RuleBreached {
+				t.Errorf("violation code/rule = %s/%s, want %s/%s", v.Code, v.Rule, tt.wantCode, RuleBreached)
+			}
+			if strings.Contains(v.Message, "connection refused") || strings.Contains(v.Message, "deadline") {
+				t.Errorf("violation message %q exposes the lookup error", v.Message)
+			}
+		})
+	}
+}
+
+func TestBreachedValidator_SendsOnlyPrefix(t *testing.T) {
+	source := &stubRangeSource{}
+	NewBreachedValidator(source, time.Second, true).Validate("password")
+
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	if len(source.prefixes) != 1 || source.prefixes[0] != "5BAA6" {
+		t.Errorf("source received prefixes %v, want [5BAA6]", source.prefixes)
+	}
+}
+
+func TestBreachedValidator_ReportsCount(t *testing.T) {
+	source := &stubRangeSource{breached: map[string]int{"password": 42}}
+
+	v := domain.AsViolation(NewBreachedValidator(source, time.Second, true).Validate("password"))
+	if v.Params["count"] != 42 {
+		t.Errorf("violation params = %v, want count=42", v.Params)
+	}
+}
+
+func TestBreachedValidator_HonoursCallerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	validator := NewBreachedValidator(&stubRangeSource{block: true}, time.Minute, false)
+
+	done := make(chan error, 1)
+	go func() { done <- validator.ValidateContext(ctx, "password") }()
+
+	select {
+	case err := <-done:
+		if v := domain.AsViolation(err); v.Code != CodeBreachCheckUnavailable {
+			t.Errorf("violation code = %s, want %s", v.Code, CodeBreachCheckUnavailable)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ValidateContext() ignored the cancelled context")
+	}
+}
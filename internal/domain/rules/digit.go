@@ -1,8 +1,14 @@
 package rules
 
 import (
-	"errors"
 	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleDigit   = "digit"
+	CodeNoDigit = "NO_DIGIT"
 )
 
 type DigitValidator struct{}
@@ -17,7 +23,15 @@ func (v *DigitValidator) Validate(password string) error {
 			return nil
 		}
 	}
-	return ErrNoDigit
+	return domain.NewViolation(CodeNoDigit, RuleDigit, ErrNoDigit.Message)
+}
+
+func (v *DigitValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleDigit,
+		Codes:       []string{CodeNoDigit},
+		Description: "Password must contain at least one digit",
+	}
 }
 
-var ErrNoDigit = errors.New("password must contain at least one digit")
+var ErrNoDigit = domain.NewViolation(CodeNoDigit, RuleDigit, "password must contain at least one digit")
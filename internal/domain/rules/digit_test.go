@@ -2,6 +2,8 @@ package rules
 
 import (
 	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
 )
 
 func TestDigitValidator(t *testing.T) {
@@ -53,3 +55,22 @@ func TestDigitValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestDigitValidator_LeavesSentinelUnchanged(t *testing.T) {
+	err := NewDigitValidator().Validate("abcdef")
+
+	if err == nil {
+		t.Fatal("Validate() error = nil, want a violation")
+	}
+	if err == error(ErrNoDigit) {
+		t.Fatal("Validate() returned the shared ErrNoDigit sentinel")
+	}
+	if v := domain.AsViolation(err); v.Code != ErrNoDigit.Code || v.Message != ErrNoDigit.Message {
+		t.Errorf("Validate() = %+v, want code and message of ErrNoDigit", v)
+	}
+
+	ErrNoDigit.WithParam("extra", 1).At('x', 3)
+	if ErrNoDigit.Params != nil || ErrNoDigit.Char != "" || ErrNoDigit.Position != domain.NoPosition {
+		t.Errorf("WithParam/At modified ErrNoDigit: %+v", *ErrNoDigit)
+	}
+}
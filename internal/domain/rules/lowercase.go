@@ -1,8 +1,14 @@
 package rules
 
 import (
-	"errors"
 	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleLowercase   = "lowercase"
+	CodeNoLowercase = "NO_LOWERCASE"
 )
 
 type LowercaseValidator struct{}
@@ -17,7 +23,15 @@ func (v *LowercaseValidator) Validate(password string) error {
 			return nil
 		}
 	}
-	return ErrNoLowercase
+	return domain.NewViolation(CodeNoLowercase, RuleLowercase, ErrNoLowercase.Message)
+}
+
+func (v *LowercaseValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleLowercase,
+		Codes:       []string{CodeNoLowercase},
+		Description: "Password must contain at least one lowercase letter",
+	}
 }
 
-var ErrNoLowercase = errors.New("password must contain at least one lowercase letter")
+var ErrNoLowercase = domain.NewViolation(CodeNoLowercase, RuleLowercase, "password must contain at least one lowercase letter")
@@ -2,6 +2,13 @@ package rules
 
 import (
 	"fmt"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleMinLength = "min_length"
+	CodeMinLength = "MIN_LENGTH"
 )
 
 type MinLengthValidator struct {
@@ -16,7 +23,20 @@ func NewMinLengthValidator(minLength int) *MinLengthValidator {
 
 func (v *MinLengthValidator) Validate(password string) error {
 	if len(password) < v.minLength {
-		return fmt.Errorf("password must have at least %d characters", v.minLength)
+		return domain.NewViolation(
+			CodeMinLength,
+			RuleMinLength,
+			fmt.Sprintf("password must have at least %d characters", v.minLength),
+		).WithParam("minLength", v.minLength).WithParam("actualLength", len(password))
 	}
 	return nil
 }
+
+func (v *MinLengthValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleMinLength,
+		Codes:       []string{CodeMinLength},
+		Description: fmt.Sprintf("Password must have at least %d characters", v.minLength),
+		Params:      map[string]any{"minLength": v.minLength},
+	}
+}
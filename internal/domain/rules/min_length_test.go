@@ -2,6 +2,8 @@ package rules
 
 import (
 	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
 )
 
 func TestMinLengthValidator(t *testing.T) {
@@ -48,3 +50,18 @@ func TestMinLengthValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestMinLengthValidator_Violation(t *testing.T) {
+	validator := NewMinLengthValidator(12)
+
+	v := domain.AsViolation(validator.Validate("short"))
+	if v.Code != CodeMinLength || v.Rule != RuleMinLength {
+		t.Errorf("violation code/rule = %s/%s, want %s/%s", v.Code, v.Rule, CodeMinLength, RuleMinLength)
+	}
+	if v.Params["minLength"] != 12 || v.Params["actualLength"] != 5 {
+		t.Errorf("violation params = %v, want minLength=12 actualLength=5", v.Params)
+	}
+	if v.Message != "password must have at least 12 characters" {
+		t.Errorf("violation message = %q", v.Message)
+	}
+}
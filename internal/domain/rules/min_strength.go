@@ -0,0 +1,51 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+)
+
+const (
+	RuleMinStrength  = "min_strength"
+	CodeWeakPassword = "WEAK_PASSWORD"
+)
+
+// MinStrengthValidator rejects passwords whose estimated strength score
+// (0-4) is below the minimum, even if they satisfy every composition rule.
+type MinStrengthValidator struct {
+	minScore int
+}
+
+func NewMinStrengthValidator(minScore int) *MinStrengthValidator {
+	return &MinStrengthValidator{
+		minScore: minScore,
+	}
+}
+
+func (v *MinStrengthValidator) Validate(password string) error {
+	result := strength.Estimate(password)
+	if result.Score >= v.minScore {
+		return nil
+	}
+
+	violation := domain.NewViolation(
+		CodeWeakPassword,
+		RuleMinStrength,
+		fmt.Sprintf("password is too easy to guess (strength %d, minimum %d)", result.Score, v.minScore),
+	).WithParam("minScore", v.minScore).WithParam("score", result.Score)
+	if result.Warning != "" {
+		violation = violation.WithParam("warning", result.Warning)
+	}
+	return violation
+}
+
+func (v *MinStrengthValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleMinStrength,
+		Codes:       []string{CodeWeakPassword},
+		Description: fmt.Sprintf("Password must reach a strength score of at least %d (0-4)", v.minScore),
+		Params:      map[string]any{"minScore": v.minScore},
+	}
+}
@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+func TestMinStrengthValidator(t *testing.T) {
+	validator := NewMinStrengthValidator(3)
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "valid password from example",
+			password: "AbTp9!fok",
+			wantErr:  false,
+		},
+		{
+			name:     "valid long random password",
+			password: "vX7#qL2!mZ9@rT4$",
+			wantErr:  false,
+		},
+		{
+			name:     "invalid common password meeting composition rules",
+			password: "Password1!",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid l33t password",
+			password: "P@ssw0rd",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid keyboard walk",
+			password: "qwertyuiop",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid empty password",
+			password: "",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MinStrengthValidator.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMinStrengthValidator_Violation(t *testing.T) {
+	v := domain.AsViolation(NewMinStrengthValidator(2).Validate("password"))
+
+	if v.Code != CodeWeakPassword || v.Rule != RuleMinStrength {
+		t.Errorf("violation code/rule = %s/%s, want %s/%s", v.Code, v.Rule, CodeWeakPassword, RuleMinStrength)
+	}
+	if v.Params["minScore"] != 2 || v.Params["score"] != 0 || v.Params["warning"] == nil {
+		t.Errorf("violation params = %v", v.Params)
+	}
+}
@@ -1,8 +1,19 @@
 package rules
 
 import (
-	"errors"
 	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleNoDuplicates  = "no_duplicates"
+	CodeDuplicateChar = "DUPLICATE_CHAR"
+	CodeWhitespace    = "WHITESPACE"
+
+	// RuleNoWhitespace is the Violation.Rule of CodeWhitespace. The check is
+	// part of no_duplicates, but is reported apart from repeated characters.
+	RuleNoWhitespace = "no_whitespace"
 )
 
 type NoDuplicatesValidator struct{}
@@ -12,22 +23,35 @@ func NewNoDuplicatesValidator() *NoDuplicatesValidator {
 }
 
 func (v *NoDuplicatesValidator) Validate(password string) error {
-	seen := make(map[rune]bool)
+	seen := make(map[rune]int)
 
+	position := 0
 	for _, char := range password {
 		if unicode.IsSpace(char) {
-			return ErrContainsWhitespace
+			return domain.NewViolation(CodeWhitespace, RuleNoWhitespace, ErrContainsWhitespace.Message).
+				At(char, position)
 		}
 
-		if seen[char] {
-			return ErrDuplicateChar
+		if first, ok := seen[char]; ok {
+			return domain.NewViolation(CodeDuplicateChar, RuleNoDuplicates, ErrDuplicateChar.Message).
+				At(char, position).
+				WithParam("firstPosition", first)
 		}
-		seen[char] = true
+		seen[char] = position
+		position++
 	}
 
 	return nil
 }
 
-var ErrDuplicateChar = errors.New("password must not contain repeated characters")
+func (v *NoDuplicatesValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleNoDuplicates,
+		Codes:       []string{CodeDuplicateChar, CodeWhitespace},
+		Description: "Password must not contain repeated or whitespace characters",
+	}
+}
+
+var ErrDuplicateChar = domain.NewViolation(CodeDuplicateChar, RuleNoDuplicates, "password must not contain repeated characters")
 
-var ErrContainsWhitespace = errors.New("password must not contain whitespace characters")
+var ErrContainsWhitespace = domain.NewViolation(CodeWhitespace, RuleNoWhitespace, "password must not contain whitespace characters")
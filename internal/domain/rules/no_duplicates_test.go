@@ -1,7 +1,10 @@
 package rules
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
 )
 
 func TestNoDuplicatesValidator(t *testing.T) {
@@ -83,3 +86,62 @@ func TestNoDuplicatesValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestNoDuplicatesValidator_Violation(t *testing.T) {
+	validator := NewNoDuplicatesValidator()
+
+	tests := []struct {
+		name         string
+		password     string
+		wantCode     string
+		wantRule     string
+		wantChar     string
+		wantPosition int
+		wantSentinel error
+	}{
+		{
+			name:         "duplicate o reports second occurrence",
+			password:     "AbTp9!foo",
+			wantCode:     CodeDuplicateChar,
+			wantRule:     RuleNoDuplicates,
+			wantChar:     "o",
+			wantPosition: 8,
+			wantSentinel: ErrDuplicateChar,
+		},
+		{
+			name:         "whitespace reports its position",
+			password:     "AbTp9 fok",
+			wantCode:     CodeWhitespace,
+			wantRule:     RuleNoWhitespace,
+			wantChar:     " ",
+			wantPosition: 5,
+			wantSentinel: ErrContainsWhitespace,
+		},
+		{
+			name:         "positions are counted in runes",
+			password:     "çaç",
+			wantCode:     CodeDuplicateChar,
+			wantRule:     RuleNoDuplicates,
+			wantChar:     "ç",
+			wantPosition: 2,
+			wantSentinel: ErrDuplicateChar,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.password)
+			if !errors.Is(err, tt.wantSentinel) {
+				t.Fatalf("NoDuplicatesValidator.Validate() error = %v, want %v", err, tt.wantSentinel)
+			}
+
+			v := domain.AsViolation(err)
+			if v.Code != tt.wantCode || v.Rule != tt.wantRule {
+				t.Errorf("violation code/rule = %s/%s, want %s/%s", v.Code, v.Rule, tt.wantCode, tt.wantRule)
+			}
+			if v.Char != tt.wantChar || v.Position != tt.wantPosition {
+				t.Errorf("violation char/position = %q/%d, want %q/%d", v.Char, v.Position, tt.wantChar, tt.wantPosition)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RulePasswordHistory    = "password_history"
+	CodePasswordReused     = "PASSWORD_REUSED"
+	CodeHistoryUnavailable = "HISTORY_UNAVAILABLE"
+)
+
+// PasswordHistory answers whether a subject used a password recently.
+type PasswordHistory interface {
+	Contains(ctx context.Context, subject, password string) (bool, error)
+	// Depth is how many previous passwords are checked.
+	Depth() int
+}
+
+// PasswordHistoryValidator rejects a password the subject named in the
+// validation context (see domain.WithSubject) used recently. Without a
+// subject every password is accepted. When the history cannot be read the
+// password is rejected, since reuse could not be ruled out.
+type PasswordHistoryValidator struct {
+	history PasswordHistory
+}
+
+func NewPasswordHistoryValidator(history PasswordHistory) *PasswordHistoryValidator {
+	return &PasswordHistoryValidator{history: history}
+}
+
+func (v *PasswordHistoryValidator) Validate(password string) error {
+	return v.ValidateContext(context.Background(), password)
+}
+
+func (v *PasswordHistoryValidator) ValidateContext(ctx context.Context, password string) error {
+	subject, ok := domain.SubjectFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	used, err := v.history.Contains(ctx, subject, password)
+	if err != nil {
+		// Store errors can name files and I/O failures, so they are logged
+		// rather than returned to API clients.
+		slog.Error("Password history lookup failed", "error", err)
+		return domain.NewViolation(
+			CodeHistoryUnavailable,
+			RulePasswordHistory,
+			"password history could not be checked",
+		)
+	}
+	if used {
+		return domain.NewViolation(
+			CodePasswordReused,
+			RulePasswordHistory,
+			fmt.Sprintf("password must not be one of the last %d passwords used", v.history.Depth()),
+		).WithParam("depth", v.history.Depth())
+	}
+	return nil
+}
+
+func (v *PasswordHistoryValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RulePasswordHistory,
+		Codes:       []string{CodePasswordReused, CodeHistoryUnavailable},
+		Description: "Password must not be one of the subject's recent passwords",
+		Params:      map[string]any{"depth": v.history.Depth()},
+	}
+}
@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+type stubHistory struct {
+	used map[string]string
+	err  error
+}
+
+func (h *stubHistory) Contains(ctx context.Context, subject, password string) (bool, error) {
+	return h.used[subject] == password, h.err
+}
+
+func (h *stubHistory) Depth() int {
+	return 5
+}
+
+func TestPasswordHistoryValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  *stubHistory
+		subject  string
+		password string
+		wantCode string
+	}{
+		{
+			name:     "valid password not used before",
+			history:  &stubHistory{used: map[string]string{"user-42": "Old#Pass123"}},
+			subject:  "user-42",
+			password: "AbTp9!fok",
+		},
+		{
+			name:     "invalid reused password",
+			history:  &stubHistory{used: map[string]string{"user-42": "Old#Pass123"}},
+			subject:  "user-42",
+			password: "Old#Pass123",
+			wantCode: CodePasswordReused,
+		},
+		{
+			name:     "valid without subject",
+			history:  &stubHistory{used: map[string]string{"": "Old#Pass123"}},
+			password: "Old#Pass123",
+		},
+		{
+			name:     "invalid when history is unavailable",
+			history:  &stubHistory{err: errors.New("disk full")},
+			subject:  "user-42",
+			password: "AbTp9!fok",
+			wantCode: CodeHistoryUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := domain.WithSubject(context.Background(), tt.subject)
+			err := NewPasswordHistoryValidator(tt.history).ValidateContext(ctx, tt.password)
+
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("PasswordHistoryValidator.ValidateContext() unexpected error: %v", err)
+				}
+				return
+			}
+			v := domain.AsViolation(err)
+			if v.Code != tt.wantCode {
+				t.Errorf("violation code = %s, want %s", v.Code, tt.wantCode)
+			}
+			if strings.Contains(v.Message, "disk full") {
+				t.Errorf("violation message %q exposes the store error", v.Message)
+			}
+		})
+	}
+}
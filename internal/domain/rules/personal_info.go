@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RulePersonalInfo         = "personal_info"
+	CodeContainsPersonalInfo = "CONTAINS_PERSONAL_INFO"
+)
+
+const DefaultMinPersonalTokenLength = 3
+
+// PersonalInfoValidator rejects passwords containing the user's username,
+// email, name or company, read from the validation context with
+// domain.UserInfoFromContext. Matching ignores case and accents and also
+// catches reversed tokens and common l33t substitutions ("j04o", "oaoj").
+// Without user information in the context, every password is accepted.
+type PersonalInfoValidator struct {
+	minTokenLength int
+}
+
+func NewPersonalInfoValidator(minTokenLength int) *PersonalInfoValidator {
+	if minTokenLength < 1 {
+		minTokenLength = DefaultMinPersonalTokenLength
+	}
+	return &PersonalInfoValidator{minTokenLength: minTokenLength}
+}
+
+func (v *PersonalInfoValidator) Validate(password string) error {
+	return v.ValidateContext(context.Background(), password)
+}
+
+func (v *PersonalInfoValidator) ValidateContext(ctx context.Context, password string) error {
+	user, ok := domain.UserInfoFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	folded := fold(password)
+	for _, t := range v.tokens(user) {
+		if containsVariant(folded, t.value) || containsVariant(folded, reverse(t.value)) {
+			return domain.NewViolation(
+				CodeContainsPersonalInfo,
+				RulePersonalInfo,
+				fmt.Sprintf("password must not contain your %s", t.description),
+			).WithParam("field", t.field)
+		}
+	}
+	return nil
+}
+
+func (v *PersonalInfoValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RulePersonalInfo,
+		Codes:       []string{CodeContainsPersonalInfo},
+		Description: "Password must not contain the username, email, name or company of the user",
+		Params:      map[string]any{"minTokenLength": v.minTokenLength},
+	}
+}
+
+type personalToken struct {
+	field       string
+	description string
+	value       []rune
+}
+
+// tokens splits each field into the whole value and its words, dropping
+// anything shorter than the minimum length. Longer tokens come first so the
+// most specific match is reported.
+func (v *PersonalInfoValidator) tokens(user domain.UserInfo) []personalToken {
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	fields := []struct {
+		name        string
+		description string
+		value       string
+	}{
+		{"username", "username", user.Username},
+		{"email", "email address", localPart},
+		{"firstName", "first name", user.FirstName},
+		{"lastName", "last name", user.LastName},
+		{"companyName", "company name", user.CompanyName},
+	}
+
+	var tokens []personalToken
+	seen := make(map[string]bool)
+	add := func(field, description string, value []rune) {
+		if len(value) < v.minTokenLength || seen[string(value)] {
+			return
+		}
+		seen[string(value)] = true
+		tokens = append(tokens, personalToken{field: field, description: description, value: value})
+	}
+
+	for _, f := range fields {
+		words := strings.FieldsFunc(string(fold(f.value)), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		add(f.name, f.description, []rune(strings.Join(words, "")))
+		for _, word := range words {
+			add(f.name, f.description, []rune(word))
+		}
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return len(tokens[i].value) > len(tokens[j].value)
+	})
+	return tokens
+}
+
+// personalLeet maps characters commonly used in place of letters to the
+// letters they may stand for.
+var personalLeet = map[rune]string{
+	'4': "a", '@': "a", '8': "b", '(': "c", '3': "e", '6': "g", '9': "g",
+	'1': "il", '!': "i", '|': "il", '0': "o", '$': "s", '5': "s",
+	'7': "t", '+': "t", '2': "z", '%': "x",
+}
+
+// containsVariant reports whether token occurs in password, letting each
+// password character match the token character itself or any letter it is
+// a l33t substitute for.
+func containsVariant(password, token []rune) bool {
+	for start := 0; start+len(token) <= len(password); start++ {
+		matched := true
+		for i, t := range token {
+			p := password[start+i]
+			if p != t && !strings.ContainsRune(personalLeet[p], t) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+var accents = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n', 'ý': 'y', 'ÿ': 'y',
+}
+
+// fold lower-cases s and strips the accents used in Portuguese and Spanish.
+func fold(s string) []rune {
+	runes := []rune(strings.ToLower(s))
+	for i, r := range runes {
+		if plain, ok := accents[r]; ok {
+			runes[i] = plain
+		}
+	}
+	return runes
+}
+
+func reverse(runes []rune) []rune {
+	reversed := make([]rune, len(runes))
+	for i, r := range runes {
+		reversed[len(runes)-1-i] = r
+	}
+	return reversed
+}
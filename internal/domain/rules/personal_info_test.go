@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+func TestPersonalInfoValidator(t *testing.T) {
+	user := domain.UserInfo{
+		Username:    "jsilva",
+		Email:       "joao.silva@itau.com.br",
+		FirstName:   "João",
+		LastName:    "Silva",
+		CompanyName: "Itaú Unibanco",
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		wantErr   bool
+		wantField string
+	}{
+		{name: "valid password without personal data", password: "AbTp9!fok", wantErr: false},
+		{name: "invalid contains username", password: "Xjsilva#2024", wantErr: true, wantField: "username"},
+		{name: "invalid contains email local part", password: "joao.silva!", wantErr: true, wantField: "email"},
+		{name: "invalid contains first name without accent", password: "Joao@1234", wantErr: true, wantField: "email"},
+		{name: "invalid contains last name in upper case", password: "SILVA-9876", wantErr: true, wantField: "email"},
+		{name: "invalid contains reversed name", password: "#avlis9Kx", wantErr: true, wantField: "email"},
+		{name: "invalid contains l33t name", password: "Xs1lv4!Pq", wantErr: true, wantField: "email"},
+		{name: "invalid contains company word", password: "Unib4nco2024", wantErr: true, wantField: "companyName"},
+		{name: "valid short tokens are ignored", password: "Ita#R9zqW", wantErr: false},
+		{name: "invalid contains l33t company", password: "K1t@u#R9z", wantErr: true, wantField: "companyName"},
+	}
+
+	validator := NewPersonalInfoValidator(4)
+	ctx := domain.WithUserInfo(context.Background(), user)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateContext(ctx, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PersonalInfoValidator.ValidateContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+
+			v := domain.AsViolation(err)
+			if v.Code != CodeContainsPersonalInfo || v.Params["field"] != tt.wantField {
+				t.Errorf("violation = %s field=%v, want %s field=%s", v.Code, v.Params["field"], CodeContainsPersonalInfo, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestPersonalInfoValidator_MinTokenLength(t *testing.T) {
+	ctx := domain.WithUserInfo(context.Background(), domain.UserInfo{FirstName: "Ana"})
+
+	if err := NewPersonalInfoValidator(4).ValidateContext(ctx, "Ana#2024x"); err != nil {
+		t.Errorf("token shorter than minTokenLength rejected: %v", err)
+	}
+	if err := NewPersonalInfoValidator(3).ValidateContext(ctx, "Ana#2024x"); err == nil {
+		t.Error("token with minTokenLength characters accepted")
+	}
+}
+
+func TestPersonalInfoValidator_WithoutUserInfo(t *testing.T) {
+	if err := NewPersonalInfoValidator(3).Validate("jsilva2024"); err != nil {
+		t.Errorf("PersonalInfoValidator.Validate() without user info error = %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleSimilarity        = "similarity"
+	CodeSimilarToPrevious = "SIMILAR_TO_PREVIOUS"
+)
+
+const (
+	DefaultMinEditDistance = 3
+	minBaseWordLength      = 4
+	maxSimilarityLength    = 256
+)
+
+// Reasons reported in the "reason" parameter of a similarity violation.
+const (
+	SimilarityEditDistance = "edit_distance"
+	SimilarityNumberChange = "number_changed"
+	SimilaritySameBaseWord = "same_base_word"
+)
+
+// SimilarityValidator rejects a new password that is a small variation of
+// the previous one, read from the validation context with
+// domain.PreviousPasswordFromContext. Comparison ignores case. A password is
+// too similar when:
+//
+//   - fewer than minDistance edits (insertions, deletions, substitutions or
+//     transpositions of adjacent characters) turn one into the other;
+//   - only the last number changed ("Summer2024!" and "Summer2025!");
+//   - both start with the same word of four or more letters
+//     ("Summer2024!" and "Summer#Rain").
+//
+// Without a previous password every password is accepted.
+type SimilarityValidator struct {
+	minDistance int
+}
+
+func NewSimilarityValidator(minDistance int) *SimilarityValidator {
+	if minDistance < 1 {
+		minDistance = DefaultMinEditDistance
+	}
+	return &SimilarityValidator{minDistance: minDistance}
+}
+
+func (v *SimilarityValidator) Validate(password string) error {
+	return v.ValidateContext(context.Background(), password)
+}
+
+func (v *SimilarityValidator) ValidateContext(ctx context.Context, password string) error {
+	previous, ok := domain.PreviousPasswordFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	oldRunes := []rune(strings.ToLower(previous))
+	newRunes := []rune(strings.ToLower(password))
+
+	if distance := editDistance(oldRunes, newRunes); distance < v.minDistance {
+		return v.violation(SimilarityEditDistance, "password must differ from the previous password in at least %d characters", v.minDistance).
+			WithParam("distance", distance)
+	}
+	if onlyNumberChanged(oldRunes, newRunes) {
+		return v.violation(SimilarityNumberChange, "password must not be the previous password with a different number")
+	}
+	if base := baseWord(newRunes); base != "" && base == baseWord(oldRunes) {
+		return v.violation(SimilaritySameBaseWord, "password must not start with the same word as the previous password")
+	}
+	return nil
+}
+
+func (v *SimilarityValidator) violation(reason, format string, args ...any) *domain.Violation {
+	return domain.NewViolation(CodeSimilarToPrevious, RuleSimilarity, fmt.Sprintf(format, args...)).
+		WithParam("reason", reason).
+		WithParam("minDistance", v.minDistance)
+}
+
+func (v *SimilarityValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleSimilarity,
+		Codes:       []string{CodeSimilarToPrevious},
+		Description: "New password must not be a small variation of the previous password",
+		Params:      map[string]any{"minDistance": v.minDistance},
+	}
+}
+
+// editDistance is the optimal string alignment distance: Levenshtein
+// distance that also counts a swap of adjacent characters as one edit. Only
+// the first maxSimilarityLength characters are compared, bounding the cost
+// of very long inputs.
+func editDistance(a, b []rune) int {
+	if len(a) > maxSimilarityLength {
+		a = a[:maxSimilarityLength]
+	}
+	if len(b) > maxSimilarityLength {
+		b = b[:maxSimilarityLength]
+	}
+
+	// Only the two previous rows are needed.
+	prevPrev := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, prevPrev[j-2]+1)
+			}
+			curr[j] = d
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[len(b)]
+}
+
+// onlyNumberChanged reports whether the passwords are the same apart from
+// their last run of digits.
+func onlyNumberChanged(a, b []rune) bool {
+	prefixA, numberA, suffixA := splitLastNumber(a)
+	prefixB, numberB, suffixB := splitLastNumber(b)
+	return numberA != "" && numberB != "" && numberA != numberB &&
+		prefixA == prefixB && suffixA == suffixB
+}
+
+func splitLastNumber(runes []rune) (prefix, number, suffix string) {
+	end := len(runes)
+	for end > 0 && !unicode.IsDigit(runes[end-1]) {
+		end--
+	}
+	start := end
+	for start > 0 && unicode.IsDigit(runes[start-1]) {
+		start--
+	}
+	return string(runes[:start]), string(runes[start:end]), string(runes[end:])
+}
+
+// baseWord returns the leading run of letters when it is long enough to be a
+// meaningful word.
+func baseWord(runes []rune) string {
+	n := 0
+	for n < len(runes) && unicode.IsLetter(runes[n]) {
+		n++
+	}
+	if n < minBaseWordLength {
+		return ""
+	}
+	return string(runes[:n])
+}
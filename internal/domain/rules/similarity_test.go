@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+func TestSimilarityValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		previous   string
+		password   string
+		wantReason string
+	}{
+		{name: "valid unrelated password", previous: "Summer2024!", password: "Kp9#vXw2q"},
+		{name: "valid without previous password", previous: "", password: "Summer2024!"},
+		{name: "invalid same password", previous: "Summer2024!", password: "Summer2024!", wantReason: SimilarityEditDistance},
+		{name: "invalid incremented year", previous: "Summer2024!", password: "Summer2025!", wantReason: SimilarityEditDistance},
+		{name: "invalid only case changed", previous: "Summer2024!", password: "sUMMER2024!", wantReason: SimilarityEditDistance},
+		{name: "invalid transposed characters", previous: "Kp9#vXw2q", password: "pK9#vxW2q", wantReason: SimilarityEditDistance},
+		{name: "invalid number changed beyond distance", previous: "Tiger#1999x", password: "Tiger#2000x", wantReason: SimilarityNumberChange},
+		{name: "invalid same base word", previous: "Summer2024!", password: "summer#Rain7", wantReason: SimilaritySameBaseWord},
+		{name: "valid short shared prefix", previous: "Sun#2024rk", password: "Sunny!Q8zp", wantReason: ""},
+	}
+
+	validator := NewSimilarityValidator(DefaultMinEditDistance)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := domain.WithPreviousPassword(context.Background(), tt.previous)
+			err := validator.ValidateContext(ctx, tt.password)
+
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("SimilarityValidator.ValidateContext() unexpected error: %v", err)
+				}
+				return
+			}
+			v := domain.AsViolation(err)
+			if v.Code != CodeSimilarToPrevious || v.Params["reason"] != tt.wantReason {
+				t.Errorf("violation = %s reason=%v, want %s reason=%s", v.Code, v.Params["reason"], CodeSimilarToPrevious, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"ab", "ba", 1},
+		{"ca", "abc", 3},
+		{"çasa", "casa", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	long := []rune(strings.Repeat("a", 10000))
+	if got := editDistance(long, []rune("b")); got != maxSimilarityLength {
+		t.Errorf("editDistance() of long input = %d, want %d", got, maxSimilarityLength)
+	}
+}
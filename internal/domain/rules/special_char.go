@@ -1,8 +1,15 @@
 package rules
 
 import (
-	"errors"
+	"fmt"
 	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleSpecialChar   = "special_char"
+	CodeNoSpecialChar = "NO_SPECIAL_CHAR"
 )
 
 type SpecialCharValidator struct {
@@ -21,7 +28,20 @@ func (v *SpecialCharValidator) Validate(password string) error {
 			return nil
 		}
 	}
-	return ErrNoSpecialChar
+	return domain.NewViolation(
+		CodeNoSpecialChar,
+		RuleSpecialChar,
+		fmt.Sprintf("password must contain at least one special character (%s)", v.allowedChars),
+	).WithParam("allowedChars", v.allowedChars)
+}
+
+func (v *SpecialCharValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleSpecialChar,
+		Codes:       []string{CodeNoSpecialChar},
+		Description: fmt.Sprintf("Password must contain at least one special character (%s)", v.allowedChars),
+		Params:      map[string]any{"allowedChars": v.allowedChars},
+	}
 }
 
-var ErrNoSpecialChar = errors.New("password must contain at least one special character (!@#$%^&*()-+)")
+var ErrNoSpecialChar = domain.NewViolation(CodeNoSpecialChar, RuleSpecialChar, "password must contain at least one special character (!@#$%^&*()-+)")
@@ -1,8 +1,14 @@
 package rules
 
 import (
-	"errors"
 	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+const (
+	RuleUppercase   = "uppercase"
+	CodeNoUppercase = "NO_UPPERCASE"
 )
 
 type UppercaseValidator struct{}
@@ -17,7 +23,15 @@ func (v *UppercaseValidator) Validate(password string) error {
 			return nil
 		}
 	}
-	return ErrNoUppercase
+	return domain.NewViolation(CodeNoUppercase, RuleUppercase, ErrNoUppercase.Message)
+}
+
+func (v *UppercaseValidator) Describe() domain.RuleDescription {
+	return domain.RuleDescription{
+		Rule:        RuleUppercase,
+		Codes:       []string{CodeNoUppercase},
+		Description: "Password must contain at least one uppercase letter",
+	}
 }
 
-var ErrNoUppercase = errors.New("password must contain at least one uppercase letter")
+var ErrNoUppercase = domain.NewViolation(CodeNoUppercase, RuleUppercase, "password must contain at least one uppercase letter")
@@ -0,0 +1,257 @@
+package strength
+
+import (
+	"bufio"
+	"embed"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Dictionary names reported in matches.
+const (
+	DictionaryPasswords  = "passwords"
+	DictionaryEnglish    = "english"
+	DictionaryPortuguese = "portuguese"
+	DictionaryNames      = "names"
+	DictionarySurnames   = "surnames"
+	DictionaryUserInputs = "user_inputs"
+)
+
+//go:embed data/*.txt
+var dataFS embed.FS
+
+var dictionaryFiles = map[string]string{
+	DictionaryPasswords:  "data/passwords.txt",
+	DictionaryEnglish:    "data/english.txt",
+	DictionaryPortuguese: "data/portuguese.txt",
+	DictionaryNames:      "data/names.txt",
+	DictionarySurnames:   "data/surnames.txt",
+}
+
+// loadDictionaries reads the embedded newline-delimited lists, ranking each
+// word by its (1-based) line number. It also returns the length, in runes,
+// of the longest word, which bounds the substrings worth looking up.
+func loadDictionaries() (map[string]map[string]int, int) {
+	dictionaries := make(map[string]map[string]int, len(dictionaryFiles))
+	longest := 0
+	for name, path := range dictionaryFiles {
+		f, err := dataFS.Open(path)
+		if err != nil {
+			panic("strength: missing embedded dictionary " + path)
+		}
+
+		ranked := make(map[string]int)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if word := strings.TrimSpace(scanner.Text()); word != "" {
+				if _, exists := ranked[word]; !exists {
+					ranked[word] = len(ranked) + 1
+					longest = max(longest, len([]rune(word)))
+				}
+			}
+		}
+		f.Close()
+
+		dictionaries[name] = ranked
+	}
+	return dictionaries, longest
+}
+
+func rankedUserInputs(inputs []string) map[string]int {
+	ranked := make(map[string]int)
+	for _, input := range inputs {
+		word := strings.ToLower(strings.TrimSpace(input))
+		if word != "" {
+			if _, exists := ranked[word]; !exists {
+				ranked[word] = len(ranked) + 1
+			}
+		}
+	}
+	return ranked
+}
+
+// dictionaryMatches finds every substring of the (lowercased) password that
+// appears in one of the dictionaries.
+func (m *matcher) dictionaryMatches(runes []rune) []Match {
+	lower := toLower(runes)
+	var matches []Match
+
+	lookup := func(name string, ranked map[string]int) {
+		longest := m.estimator.longestWord
+		if name == DictionaryUserInputs {
+			longest = len(lower)
+		}
+		for i := range lower {
+			for j := i; j < len(lower) && j-i < longest; j++ {
+				word := string(lower[i : j+1])
+				if rank, ok := ranked[word]; ok {
+					matches = append(matches, Match{
+						Pattern:     PatternDictionary,
+						I:           i,
+						J:           j,
+						Token:       string(runes[i : j+1]),
+						MatchedWord: word,
+						Rank:        rank,
+						Dictionary:  name,
+					})
+				}
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(m.estimator.dictionaries) {
+		lookup(name, m.estimator.dictionaries[name])
+	}
+	if len(m.userInputs) > 0 {
+		lookup(DictionaryUserInputs, m.userInputs)
+	}
+	return matches
+}
+
+func (m *matcher) reverseDictionaryMatches(runes []rune) []Match {
+	reversed := reverseRunes(runes)
+	n := len(runes)
+
+	var matches []Match
+	for _, match := range m.dictionaryMatches(reversed) {
+		match.Token = reverseString(match.Token)
+		match.Reversed = true
+		match.I, match.J = n-1-match.J, n-1-match.I
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// l33tTable maps each letter to the characters commonly substituted for it.
+var l33tTable = map[rune][]rune{
+	'a': {'4', '@'},
+	'b': {'8'},
+	'c': {'(', '{', '[', '<'},
+	'e': {'3'},
+	'g': {'6', '9'},
+	'i': {'1', '!', '|'},
+	'l': {'1', '|', '7'},
+	'o': {'0'},
+	's': {'$', '5'},
+	't': {'+', '7'},
+	'x': {'%'},
+	'z': {'2'},
+}
+
+// maxL33tSubstitutions bounds how many substitution tables are tried when
+// the password contains characters with several possible meanings.
+const maxL33tSubstitutions = 64
+
+// l33tMatches undoes l33t substitutions and looks the result up in the
+// dictionaries, keeping only matches that actually used a substitution.
+func (m *matcher) l33tMatches(runes []rune) []Match {
+	lower := toLower(runes)
+
+	var matches []Match
+	for _, sub := range l33tSubstitutions(lower) {
+		translated := make([]rune, len(lower))
+		for i, r := range lower {
+			if letter, ok := sub[r]; ok {
+				translated[i] = letter
+			} else {
+				translated[i] = r
+			}
+		}
+
+		for _, match := range m.dictionaryMatches(translated) {
+			token := lower[match.I : match.J+1]
+			if match.I == match.J || string(token) == match.MatchedWord {
+				continue
+			}
+
+			used := make(map[rune]rune)
+			for _, r := range token {
+				if letter, ok := sub[r]; ok {
+					used[r] = letter
+				}
+			}
+
+			match.Token = string(runes[match.I : match.J+1])
+			match.L33t = true
+			match.Substitutions = used
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// l33tSubstitutions enumerates the ways of reading the l33t characters found
+// in the password, each character mapping to exactly one letter.
+func l33tSubstitutions(lower []rune) []map[rune]rune {
+	candidates := make(map[rune][]rune)
+	for letter, subs := range l33tTable {
+		for _, sub := range subs {
+			for _, r := range lower {
+				if r == sub {
+					candidates[sub] = append(candidates[sub], letter)
+					break
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	keys := make([]rune, 0, len(candidates))
+	for k := range candidates {
+		keys = append(keys, k)
+		sort.Slice(candidates[k], func(i, j int) bool { return candidates[k][i] < candidates[k][j] })
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	subs := []map[rune]rune{{}}
+	for _, k := range keys {
+		var next []map[rune]rune
+		for _, sub := range subs {
+			for _, letter := range candidates[k] {
+				if len(next) >= maxL33tSubstitutions {
+					break
+				}
+				extended := make(map[rune]rune, len(sub)+1)
+				for kk, vv := range sub {
+					extended[kk] = vv
+				}
+				extended[k] = letter
+				next = append(next, extended)
+			}
+		}
+		subs = next
+	}
+	return subs
+}
+
+func toLower(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+func reverseRunes(runes []rune) []rune {
+	reversed := make([]rune, len(runes))
+	for i, r := range runes {
+		reversed[len(runes)-1-i] = r
+	}
+	return reversed
+}
+
+func reverseString(s string) string {
+	return string(reverseRunes([]rune(s)))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+package strength
+
+import "strings"
+
+// keyboard is an adjacency graph built from a keyboard layout. Each key maps
+// to its neighbours in a fixed direction order, so that a change of index
+// between consecutive keys counts as a turn. Empty strings mark directions
+// without a key.
+type keyboard struct {
+	name      string
+	adjacency map[rune][]string
+	shifted   map[rune]bool
+	// starting positions and average degree feed the spatial guess estimate.
+	startingPositions float64
+	averageDegree     float64
+}
+
+// Layout rows list keys as "unshifted+shifted" tokens. Slanted layouts have
+// each row shifted half a key to the right of the previous one.
+var (
+	qwertyRows = []string{
+		"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+",
+		"qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|",
+		"aA sS dD fF gG hH jJ kK lL ;: '\"",
+		"zZ xX cC vV bB nN mM ,< .> /?",
+	}
+	// Row offsets (in keys) for the slanted layout: q sits between 1 and 2.
+	qwertyOffsets = []int{0, 1, 1, 1}
+
+	keypadRows = []string{
+		"/ * -",
+		"7 8 9 +",
+		"4 5 6",
+		"1 2 3",
+		"0 .",
+	}
+	keypadOffsets = []int{1, 0, 0, 0, 0}
+)
+
+var keyboards = []*keyboard{
+	buildKeyboard("qwerty", qwertyRows, qwertyOffsets, true),
+	buildKeyboard("keypad", keypadRows, keypadOffsets, false),
+}
+
+func buildKeyboard(name string, rows []string, offsets []int, slanted bool) *keyboard {
+	type pos struct{ x, y int }
+	tokens := make(map[pos]string)
+	for y, row := range rows {
+		for i, token := range strings.Fields(row) {
+			tokens[pos{i + offsets[y], y}] = token
+		}
+	}
+
+	var directions []pos
+	if slanted {
+		directions = []pos{{-1, 0}, {0, -1}, {1, -1}, {1, 0}, {0, 1}, {-1, 1}}
+	} else {
+		directions = []pos{{-1, 0}, {-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}}
+	}
+
+	kb := &keyboard{name: name, adjacency: make(map[rune][]string), shifted: make(map[rune]bool)}
+	degrees := 0
+	for p, token := range tokens {
+		neighbours := make([]string, len(directions))
+		for i, d := range directions {
+			if t, ok := tokens[pos{p.x + d.x, p.y + d.y}]; ok {
+				neighbours[i] = t
+				degrees++
+			}
+		}
+		for i, r := range []rune(token) {
+			kb.adjacency[r] = neighbours
+			kb.shifted[r] = i > 0
+		}
+	}
+
+	kb.startingPositions = float64(len(tokens))
+	kb.averageDegree = float64(degrees) / float64(len(tokens))
+	return kb
+}
+
+// isShifted reports whether r is the shifted character of its key.
+func (kb *keyboard) isShifted(r rune) bool {
+	return kb.shifted[r]
+}
+
+// direction returns the neighbour index of next relative to current, or -1
+// when the keys are not adjacent.
+func (kb *keyboard) direction(current, next rune) (int, bool) {
+	for i, token := range kb.adjacency[current] {
+		if token != "" && strings.ContainsRune(token, next) {
+			return i, strings.IndexRune(token, next) > 0
+		}
+	}
+	return -1, false
+}
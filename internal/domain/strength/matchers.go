@@ -0,0 +1,316 @@
+package strength
+
+import (
+	"regexp"
+	"strconv"
+	"unicode"
+)
+
+// Pattern identifies how a part of the password was recognised.
+type Pattern string
+
+const (
+	PatternDictionary Pattern = "dictionary"
+	PatternSpatial    Pattern = "spatial"
+	PatternSequence   Pattern = "sequence"
+	PatternRepeat     Pattern = "repeat"
+	PatternDate       Pattern = "date"
+	PatternBruteforce Pattern = "bruteforce"
+)
+
+// Match is a recognised part of the password, spanning runes I..J inclusive.
+type Match struct {
+	Pattern Pattern
+	I, J    int
+	Token   string
+	Guesses float64
+
+	// Dictionary matches.
+	Dictionary    string
+	MatchedWord   string
+	Rank          int
+	Reversed      bool
+	L33t          bool
+	Substitutions map[rune]rune
+
+	// Spatial matches.
+	Graph        string
+	Turns        int
+	ShiftedCount int
+
+	// Sequence matches.
+	Ascending bool
+
+	// Repeat matches.
+	BaseToken   string
+	BaseGuesses float64
+	RepeatCount int
+
+	// Date matches.
+	Year      int
+	Separator string
+}
+
+type matcher struct {
+	estimator  *Estimator
+	userInputs map[string]int
+	year       int
+}
+
+func (m *matcher) omnimatch(runes []rune) []Match {
+	var matches []Match
+	matches = append(matches, m.dictionaryMatches(runes)...)
+	matches = append(matches, m.reverseDictionaryMatches(runes)...)
+	matches = append(matches, m.l33tMatches(runes)...)
+	matches = append(matches, spatialMatches(runes)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, m.repeatMatches(runes)...)
+	matches = append(matches, m.dateMatches(runes)...)
+	return matches
+}
+
+// spatialMatches finds keyboard walks of at least three keys, such as
+// "qwerty", "asdf" or "7896".
+func spatialMatches(runes []rune) []Match {
+	var matches []Match
+	for _, kb := range keyboards {
+		i := 0
+		for i < len(runes)-1 {
+			j := i + 1
+			lastDirection := -1
+			turns := 0
+			shifted := 0
+			if kb.isShifted(runes[i]) {
+				shifted++
+			}
+
+			for j < len(runes) {
+				dir, isShifted := kb.direction(runes[j-1], runes[j])
+				if dir < 0 {
+					break
+				}
+				if isShifted {
+					shifted++
+				}
+				if dir != lastDirection {
+					turns++
+					lastDirection = dir
+				}
+				j++
+			}
+
+			if j-i > 2 {
+				matches = append(matches, Match{
+					Pattern:      PatternSpatial,
+					I:            i,
+					J:            j - 1,
+					Token:        string(runes[i:j]),
+					Graph:        kb.name,
+					Turns:        turns,
+					ShiftedCount: shifted,
+				})
+			}
+			i = j
+		}
+	}
+	return matches
+}
+
+// maxSequenceDelta is the largest code point step still considered a
+// sequence ("aceg" has a step of 2).
+const maxSequenceDelta = 5
+
+// sequenceMatches finds runs with a constant step, such as "abcd", "9753"
+// or "zyx".
+func sequenceMatches(runes []rune) []Match {
+	if len(runes) < 2 {
+		return nil
+	}
+
+	var matches []Match
+	add := func(i, j, delta int) {
+		if j-i >= 2 && delta != 0 && abs(delta) <= maxSequenceDelta {
+			matches = append(matches, Match{
+				Pattern:   PatternSequence,
+				I:         i,
+				J:         j,
+				Token:     string(runes[i : j+1]),
+				Ascending: delta > 0,
+			})
+		}
+	}
+
+	i := 0
+	lastDelta := int(runes[1]) - int(runes[0])
+	for k := 2; k < len(runes); k++ {
+		delta := int(runes[k]) - int(runes[k-1])
+		if delta == lastDelta {
+			continue
+		}
+		add(i, k-1, lastDelta)
+		i = k - 1
+		lastDelta = delta
+	}
+	add(i, len(runes)-1, lastDelta)
+
+	return matches
+}
+
+// repeatMatches finds repeated characters or blocks, such as "aaa" or
+// "abcabc". The repeated block is scored recursively.
+func (m *matcher) repeatMatches(runes []rune) []Match {
+	var matches []Match
+	i := 0
+	for i < len(runes) {
+		bestUnit, bestCount := 0, 0
+		for unit := 1; i+2*unit <= len(runes); unit++ {
+			count := 1
+			for i+(count+1)*unit <= len(runes) &&
+				string(runes[i+count*unit:i+(count+1)*unit]) == string(runes[i:i+unit]) {
+				count++
+			}
+			if count > 1 && count*unit > bestCount*bestUnit {
+				bestUnit, bestCount = unit, count
+			}
+		}
+
+		if bestCount < 2 {
+			i++
+			continue
+		}
+
+		base := runes[i : i+bestUnit]
+		baseGuesses, _ := m.mostGuessableSequence(base, m.omnimatch(base))
+		end := i + bestUnit*bestCount
+		matches = append(matches, Match{
+			Pattern:     PatternRepeat,
+			I:           i,
+			J:           end - 1,
+			Token:       string(runes[i:end]),
+			BaseToken:   string(base),
+			BaseGuesses: baseGuesses,
+			RepeatCount: bestCount,
+		})
+		i = end
+	}
+	return matches
+}
+
+var (
+	yearPattern          = regexp.MustCompile(`^(19\d\d|20\d\d)$`)
+	separatedDatePattern = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+)
+
+const (
+	minYear      = 1000
+	maxYear      = 2050
+	minYearSpace = 20
+)
+
+// dateMatches finds years ("1987") and day/month/year dates written with
+// or without separators ("13051987", "13/5/87", "1987-05-13").
+func (m *matcher) dateMatches(runes []rune) []Match {
+	var matches []Match
+
+	for i := range runes {
+		for j := i + 3; j < len(runes) && j < i+10; j++ {
+			token := string(runes[i : j+1])
+
+			if yearPattern.MatchString(token) {
+				year, _ := strconv.Atoi(token)
+				matches = append(matches, Match{Pattern: PatternDate, I: i, J: j, Token: token, Year: year})
+				continue
+			}
+
+			if year, ok := m.compactDate(token); ok {
+				matches = append(matches, Match{Pattern: PatternDate, I: i, J: j, Token: token, Year: year})
+				continue
+			}
+
+			if parts := separatedDatePattern.FindStringSubmatch(token); parts != nil && parts[2] == parts[4] {
+				a, _ := strconv.Atoi(parts[1])
+				b, _ := strconv.Atoi(parts[3])
+				c, _ := strconv.Atoi(parts[5])
+				if year, ok := m.dateYear([3]int{a, b, c}, [3]int{len(parts[1]), len(parts[3]), len(parts[5])}); ok {
+					matches = append(matches, Match{Pattern: PatternDate, I: i, J: j, Token: token, Year: year, Separator: parts[2]})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// compactDate checks whether a run of 4 to 8 digits can be split into a
+// valid day, month and year.
+func (m *matcher) compactDate(token string) (int, bool) {
+	if len(token) < 4 || len(token) > 8 {
+		return 0, false
+	}
+	for _, r := range token {
+		if !unicode.IsDigit(r) || r > '9' {
+			return 0, false
+		}
+	}
+
+	bestYear, found := 0, false
+	for k := 1; k < len(token)-1; k++ {
+		for l := k + 1; l < len(token); l++ {
+			parts := [3]string{token[:k], token[k:l], token[l:]}
+			var values, lengths [3]int
+			for idx, part := range parts {
+				values[idx], _ = strconv.Atoi(part)
+				lengths[idx] = len(part)
+			}
+			if year, ok := m.dateYear(values, lengths); ok {
+				if !found || abs(year-m.year) < abs(bestYear-m.year) {
+					bestYear, found = year, true
+				}
+			}
+		}
+	}
+	return bestYear, found
+}
+
+// dateYear interprets three numbers as a date with the year first or last,
+// returning the year of the first valid reading.
+func (m *matcher) dateYear(values, lengths [3]int) (int, bool) {
+	readings := []struct{ year, month, day, yearLength int }{
+		{values[2], values[1], values[0], lengths[2]}, // day month year
+		{values[2], values[0], values[1], lengths[2]}, // month day year
+		{values[0], values[1], values[2], lengths[0]}, // year month day
+		{values[0], values[2], values[1], lengths[0]}, // year day month
+	}
+
+	for _, r := range readings {
+		year := r.year
+		switch r.yearLength {
+		case 2:
+			year = twoDigitYear(year)
+		case 4:
+			if year < minYear || year > maxYear {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if r.month >= 1 && r.month <= 12 && r.day >= 1 && r.day <= 31 {
+			return year, true
+		}
+	}
+	return 0, false
+}
+
+func twoDigitYear(year int) int {
+	if year > 50 {
+		return 1900 + year
+	}
+	return 2000 + year
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
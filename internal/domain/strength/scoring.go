@@ -0,0 +1,279 @@
+package strength
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	// maxAnalyzedLength caps the pattern search; the rest of a longer
+	// password is scored as brute force.
+	maxAnalyzedLength = 64
+
+	bruteforceCardinality              = 10
+	minGuessesBeforeGrowingSequence    = 10000
+	minSubmatchGuessesSingleChar       = 10
+	minSubmatchGuessesMultiChar        = 50
+	referenceYearMinDistanceMultiplier = 365
+)
+
+// mostGuessableSequence finds the sequence of non-overlapping matches
+// (filling gaps with brute force) that needs the fewest guesses, following
+// the dynamic programming approach of zxcvbn.
+func (m *matcher) mostGuessableSequence(runes []rune, matches []Match) (float64, []Match) {
+	n := len(runes)
+	if n == 0 {
+		return 1, nil
+	}
+
+	byEnd := make([][]Match, n)
+	for _, match := range matches {
+		byEnd[match.J] = append(byEnd[match.J], match)
+	}
+
+	// best[k][l] is the cheapest way to cover runes[0..k] with l matches,
+	// where the last match ends at k.
+	type state struct {
+		match Match
+		pi    float64
+		g     float64
+	}
+	best := make([]map[int]state, n)
+	for k := range best {
+		best[k] = make(map[int]state)
+	}
+
+	update := func(match Match, l int) {
+		k := match.J
+		pi := m.guesses(match)
+		if l > 1 {
+			pi *= best[match.I-1][l-1].pi
+		}
+		g := factorial(l)*pi + math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+
+		for otherL, other := range best[k] {
+			if otherL <= l && other.g <= g {
+				return
+			}
+		}
+		best[k][l] = state{match: match, pi: pi, g: g}
+	}
+
+	bruteforce := func(i, j int) Match {
+		return Match{Pattern: PatternBruteforce, I: i, J: j, Token: string(runes[i : j+1])}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, match := range byEnd[k] {
+			if match.I > 0 {
+				for l := range best[match.I-1] {
+					update(match, l+1)
+				}
+			} else {
+				update(match, 1)
+			}
+		}
+
+		update(bruteforce(0, k), 1)
+		for i := 1; i <= k; i++ {
+			for l, last := range best[i-1] {
+				// Adjacent brute force segments are always merged.
+				if last.match.Pattern == PatternBruteforce {
+					continue
+				}
+				update(bruteforce(i, k), l+1)
+			}
+		}
+	}
+
+	bestL, bestG := 0, math.Inf(1)
+	for l, s := range best[n-1] {
+		if s.g < bestG || (s.g == bestG && l < bestL) {
+			bestL, bestG = l, s.g
+		}
+	}
+
+	sequence := make([]Match, bestL)
+	k := n - 1
+	for l := bestL; l > 0; l-- {
+		s := best[k][l]
+		s.match.Guesses = m.guesses(s.match)
+		sequence[l-1] = s.match
+		k = s.match.I - 1
+	}
+
+	return bestG, sequence
+}
+
+// guesses estimates how many attempts an attacker needs to hit the match.
+func (m *matcher) guesses(match Match) float64 {
+	length := len([]rune(match.Token))
+
+	var g float64
+	switch match.Pattern {
+	case PatternBruteforce:
+		g = math.Pow(bruteforceCardinality, float64(length))
+		minimum := float64(minSubmatchGuessesMultiChar)
+		if length == 1 {
+			minimum = minSubmatchGuessesSingleChar
+		}
+		return math.Max(g, minimum+1)
+	case PatternDictionary:
+		g = float64(match.Rank) * uppercaseVariations(match.Token) * l33tVariations(match)
+		if match.Reversed {
+			g *= 2
+		}
+	case PatternSpatial:
+		g = spatialGuesses(match)
+	case PatternSequence:
+		g = sequenceGuesses(match)
+	case PatternRepeat:
+		g = match.BaseGuesses * float64(match.RepeatCount)
+	case PatternDate:
+		g = math.Max(float64(abs(match.Year-m.year)), minYearSpace)
+		if len(match.Token) > 4 {
+			g *= referenceYearMinDistanceMultiplier
+		}
+		if match.Separator != "" {
+			g *= 4
+		}
+	}
+
+	minimum := float64(minSubmatchGuessesMultiChar)
+	if length == 1 {
+		minimum = minSubmatchGuessesSingleChar
+	}
+	return math.Max(g, minimum)
+}
+
+// uppercaseVariations accounts for the capitalisation of a dictionary word:
+// common patterns (first, last or all letters) only double the guesses.
+func uppercaseVariations(token string) float64 {
+	upper, lower := 0, 0
+	runes := []rune(token)
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(runes[0]) || unicode.IsUpper(runes[len(runes)-1]))) {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+func l33tVariations(match Match) float64 {
+	if !match.L33t {
+		return 1
+	}
+
+	variations := 1.0
+	lower := toLower([]rune(match.Token))
+	for sub, letter := range match.Substitutions {
+		subbed, unsubbed := 0, 0
+		for _, r := range lower {
+			switch r {
+			case sub:
+				subbed++
+			case letter:
+				unsubbed++
+			}
+		}
+
+		if subbed == 0 || unsubbed == 0 {
+			variations *= 2
+			continue
+		}
+		possibilities := 0.0
+		for i := 1; i <= min(subbed, unsubbed); i++ {
+			possibilities += binomial(subbed+unsubbed, i)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+func spatialGuesses(match Match) float64 {
+	var kb *keyboard
+	for _, k := range keyboards {
+		if k.name == match.Graph {
+			kb = k
+		}
+	}
+
+	length := len([]rune(match.Token))
+	guesses := 0.0
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= min(match.Turns, i-1); j++ {
+			guesses += binomial(i-1, j-1) * kb.startingPositions * math.Pow(kb.averageDegree, float64(j))
+		}
+	}
+
+	if match.ShiftedCount > 0 {
+		shifted, unshifted := match.ShiftedCount, length-match.ShiftedCount
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			variations := 0.0
+			for i := 1; i <= min(shifted, unshifted); i++ {
+				variations += binomial(shifted+unshifted, i)
+			}
+			guesses *= variations
+		}
+	}
+	return guesses
+}
+
+func sequenceGuesses(match Match) float64 {
+	first := []rune(match.Token)[0]
+
+	var base float64
+	switch {
+	case first == 'a' || first == 'A' || first == 'z' || first == 'Z' || first == '0' || first == '1' || first == '9':
+		base = 4
+	case unicode.IsDigit(first):
+		base = 10
+	default:
+		base = 26
+	}
+	if !match.Ascending {
+		base *= 2
+	}
+	return base * float64(len([]rune(match.Token)))
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+	return f
+}
+
+func binomial(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+	if k == 0 {
+		return 1
+	}
+	r := 1.0
+	for d := 1; d <= k; d++ {
+		r *= float64(n)
+		r /= float64(d)
+		n--
+	}
+	return r
+}
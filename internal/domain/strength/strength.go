@@ -0,0 +1,232 @@
+// Package strength estimates how hard a password is to guess, in the spirit
+// of zxcvbn: the password is split into the cheapest sequence of recognisable
+// patterns (dictionary words, l33t substitutions, keyboard walks, sequences,
+// repeats and dates) and the guesses needed for each are multiplied together.
+package strength
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Score thresholds, in guesses, taken from zxcvbn. A small delta keeps
+// passwords sitting exactly on a boundary in the lower score.
+const (
+	scoreDelta = 5
+
+	MinScore = 0
+	MaxScore = 4
+)
+
+// Result is the outcome of a strength estimation.
+type Result struct {
+	Score        int
+	Guesses      float64
+	GuessesLog10 float64
+	CrackTimes   CrackTimes
+	// CrackTimeDisplay is a human-readable OfflineSlowHashing estimate.
+	CrackTimeDisplay string
+	Warning          string
+	Sequence         []Match
+}
+
+// CrackTimes estimates, in seconds, how long an attacker needs to guess the
+// password under different attack scenarios.
+type CrackTimes struct {
+	OnlineThrottling   float64 // 100 guesses per hour
+	OnlineNoThrottling float64 // 10 guesses per second
+	OfflineSlowHashing float64 // 10^4 guesses per second (bcrypt, argon2)
+	OfflineFastHashing float64 // 10^10 guesses per second (unsalted fast hashes)
+}
+
+// Estimator holds the ranked dictionaries used for matching. It is safe for
+// concurrent use.
+type Estimator struct {
+	dictionaries map[string]map[string]int
+	longestWord  int
+	now          func() time.Time
+}
+
+// NewEstimator loads the embedded dictionaries.
+func NewEstimator() *Estimator {
+	dictionaries, longest := loadDictionaries()
+	return &Estimator{
+		dictionaries: dictionaries,
+		longestWord:  longest,
+		now:          time.Now,
+	}
+}
+
+var (
+	defaultEstimator     *Estimator
+	defaultEstimatorOnce sync.Once
+)
+
+// Estimate scores a password with the shared default estimator. userInputs
+// are extra words (such as the username) treated as the most likely guesses.
+func Estimate(password string, userInputs ...string) Result {
+	defaultEstimatorOnce.Do(func() {
+		defaultEstimator = NewEstimator()
+	})
+	return defaultEstimator.Estimate(password, userInputs...)
+}
+
+// Estimate scores a password. See the package-level Estimate.
+func (e *Estimator) Estimate(password string, userInputs ...string) Result {
+	runes := []rune(password)
+	if len(runes) > maxAnalyzedLength {
+		runes = runes[:maxAnalyzedLength]
+	}
+
+	m := &matcher{
+		estimator:  e,
+		userInputs: rankedUserInputs(userInputs),
+		year:       e.now().Year(),
+	}
+	guesses, sequence := m.mostGuessableSequence(runes, m.omnimatch(runes))
+
+	// Characters beyond the analysed prefix are counted as brute force. The
+	// sum is kept in log10 space: a few hundred extra characters are already
+	// more guesses than a float64 can hold.
+	guessesLog10 := math.Log10(guesses)
+	if extra := len([]rune(password)) - len(runes); extra > 0 {
+		guessesLog10 += float64(extra) * math.Log10(bruteforceCardinality)
+		guesses = pow10(guessesLog10)
+	}
+
+	result := Result{
+		Score:        scoreFromGuesses(guesses),
+		Guesses:      guesses,
+		GuessesLog10: guessesLog10,
+		Sequence:     sequence,
+	}
+	result.CrackTimes = CrackTimes{
+		OnlineThrottling:   pow10(guessesLog10 - math.Log10(100.0/3600.0)),
+		OnlineNoThrottling: pow10(guessesLog10 - 1),
+		OfflineSlowHashing: pow10(guessesLog10 - 4),
+		OfflineFastHashing: pow10(guessesLog10 - 10),
+	}
+	result.CrackTimeDisplay = displayTime(result.CrackTimes.OfflineSlowHashing)
+	result.Warning = warning(result.Score, sequence)
+	return result
+}
+
+// pow10 is 10^exp capped at math.MaxFloat64, so that results stay finite
+// and can be encoded as JSON.
+func pow10(exp float64) float64 {
+	return min(math.Pow(10, exp), math.MaxFloat64)
+}
+
+func scoreFromGuesses(guesses float64) int {
+	switch {
+	case guesses < 1e3+scoreDelta:
+		return 0
+	case guesses < 1e6+scoreDelta:
+		return 1
+	case guesses < 1e8+scoreDelta:
+		return 2
+	case guesses < 1e10+scoreDelta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func displayTime(seconds float64) string {
+	const (
+		minute  = 60.0
+		hour    = minute * 60
+		day     = hour * 24
+		month   = day * 31
+		year    = month * 12
+		century = year * 100
+	)
+
+	unit := func(n float64, name string) string {
+		v := int64(math.Round(n))
+		if v == 1 {
+			return "1 " + name
+		}
+		return formatInt(v) + " " + name + "s"
+	}
+
+	switch {
+	case seconds < 1:
+		return "less than a second"
+	case seconds < minute:
+		return unit(seconds, "second")
+	case seconds < hour:
+		return unit(seconds/minute, "minute")
+	case seconds < day:
+		return unit(seconds/hour, "hour")
+	case seconds < month:
+		return unit(seconds/day, "day")
+	case seconds < year:
+		return unit(seconds/month, "month")
+	case seconds < century:
+		return unit(seconds/year, "year")
+	default:
+		return "centuries"
+	}
+}
+
+func formatInt(v int64) string {
+	if v == 0 {
+		return "0"
+	}
+	var buf [20]byte
+	i := len(buf)
+	for v > 0 {
+		i--
+		buf[i] = byte('0' + v%10)
+		v /= 10
+	}
+	return string(buf[i:])
+}
+
+func warning(score int, sequence []Match) string {
+	if len(sequence) == 0 || score > 2 {
+		return ""
+	}
+
+	longest := sequence[0]
+	for _, m := range sequence[1:] {
+		if len(m.Token) > len(longest.Token) {
+			longest = m
+		}
+	}
+
+	switch longest.Pattern {
+	case PatternDictionary:
+		switch longest.Dictionary {
+		case DictionaryPasswords:
+			switch {
+			case len(sequence) == 1 && !longest.L33t && !longest.Reversed && longest.Rank <= 10:
+				return "This is a top-10 common password"
+			case len(sequence) == 1 && !longest.L33t && !longest.Reversed && longest.Rank <= 100:
+				return "This is a top-100 common password"
+			default:
+				return "This is similar to a commonly used password"
+			}
+		case DictionaryEnglish, DictionaryPortuguese:
+			if len(sequence) == 1 {
+				return "A word by itself is easy to guess"
+			}
+			return "Common words are easy to guess, even with substitutions"
+		case DictionaryNames, DictionarySurnames:
+			return "Names and surnames are easy to guess"
+		case DictionaryUserInputs:
+			return "Avoid using personal information"
+		}
+	case PatternSpatial:
+		return "Keyboard patterns are easy to guess"
+	case PatternRepeat:
+		return "Repeated characters or words are easy to guess"
+	case PatternSequence:
+		return "Sequences like abc or 6543 are easy to guess"
+	case PatternDate:
+		return "Dates and years are easy to guess"
+	}
+	return ""
+}
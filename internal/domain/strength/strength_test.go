@@ -0,0 +1,171 @@
+package strength
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimate_Scores(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		minScore int
+		maxScore int
+	}{
+		{name: "empty password", password: "", minScore: 0, maxScore: 0},
+		{name: "top common password", password: "password", minScore: 0, maxScore: 0},
+		{name: "common password with digit and symbol", password: "Password1!", minScore: 0, maxScore: 1},
+		{name: "l33t common password", password: "P@ssw0rd", minScore: 0, maxScore: 1},
+		{name: "keyboard walk", password: "qwertyuiop", minScore: 0, maxScore: 1},
+		{name: "keypad walk", password: "147896325", minScore: 0, maxScore: 1},
+		{name: "sequence", password: "abcdefghij", minScore: 0, maxScore: 1},
+		{name: "repeat", password: "aaaaaaaaaaaa", minScore: 0, maxScore: 1},
+		{name: "repeated word", password: "dragondragondragon", minScore: 0, maxScore: 1},
+		{name: "date", password: "13/05/1987", minScore: 0, maxScore: 2},
+		{name: "portuguese word with year", password: "Flamengo2024", minScore: 0, maxScore: 2},
+		{name: "example from requirements", password: "AbTp9!fok", minScore: 3, maxScore: 4},
+		{name: "random long password", password: "vX7#qL2!mZ9@rT4$", minScore: 4, maxScore: 4},
+		{name: "passphrase", password: "correct-horse-battery-staple-orbit", minScore: 4, maxScore: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Estimate(tt.password)
+			if result.Score < tt.minScore || result.Score > tt.maxScore {
+				t.Errorf("Estimate(%q) score = %d (guesses 10^%.1f, sequence %v), want between %d and %d",
+					tt.password, result.Score, result.GuessesLog10, patterns(result.Sequence), tt.minScore, tt.maxScore)
+			}
+		})
+	}
+}
+
+func TestEstimate_Patterns(t *testing.T) {
+	tests := []struct {
+		password    string
+		wantPattern Pattern
+		check       func(Match) bool
+	}{
+		{"password", PatternDictionary, func(m Match) bool { return m.Dictionary == DictionaryPasswords && m.Rank == 1 }},
+		{"drowssap", PatternDictionary, func(m Match) bool { return m.Reversed }},
+		{"p4ssw0rd", PatternDictionary, func(m Match) bool { return m.L33t && m.MatchedWord == "password" }},
+		{"kjhgfd", PatternSpatial, func(m Match) bool { return m.Graph == "qwerty" }},
+		{"9876", PatternSequence, func(m Match) bool { return !m.Ascending }},
+		{"xyzxyzxyz", PatternRepeat, func(m Match) bool { return m.BaseToken == "xyz" && m.RepeatCount == 3 }},
+		{"1987", PatternDate, func(m Match) bool { return m.Year == 1987 }},
+		{"13051987", PatternDate, func(m Match) bool { return m.Year == 1987 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			result := Estimate(tt.password)
+			if len(result.Sequence) != 1 {
+				t.Fatalf("Estimate(%q) sequence = %v, want a single %s match", tt.password, patterns(result.Sequence), tt.wantPattern)
+			}
+			m := result.Sequence[0]
+			if m.Pattern != tt.wantPattern || !tt.check(m) {
+				t.Errorf("Estimate(%q) match = %+v, want %s", tt.password, m, tt.wantPattern)
+			}
+		})
+	}
+}
+
+func TestEstimate_UserInputs(t *testing.T) {
+	without := Estimate("Zorblax9!")
+	with := Estimate("Zorblax9!", "zorblax")
+
+	if with.Guesses >= without.Guesses {
+		t.Errorf("user input did not lower guesses: %v >= %v", with.Guesses, without.Guesses)
+	}
+	if with.Sequence[0].Dictionary != DictionaryUserInputs {
+		t.Errorf("first match dictionary = %s, want %s", with.Sequence[0].Dictionary, DictionaryUserInputs)
+	}
+}
+
+func TestEstimate_LongPassword(t *testing.T) {
+	long := "Tr0ub4dor&3-correct-horse-battery-staple-with-many-extra-characters-at-the-end"
+	result := Estimate(long)
+	if result.Score != MaxScore {
+		t.Errorf("Estimate(long) score = %d, want %d", result.Score, MaxScore)
+	}
+}
+
+func TestEstimate_VeryLongPasswordIsFinite(t *testing.T) {
+	result := Estimate("Ab1!" + strings.Repeat("xQ", 498))
+	for name, v := range map[string]float64{
+		"Guesses":            result.Guesses,
+		"GuessesLog10":       result.GuessesLog10,
+		"OnlineThrottling":   result.CrackTimes.OnlineThrottling,
+		"OnlineNoThrottling": result.CrackTimes.OnlineNoThrottling,
+		"OfflineSlowHashing": result.CrackTimes.OfflineSlowHashing,
+		"OfflineFastHashing": result.CrackTimes.OfflineFastHashing,
+	} {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			t.Errorf("%s = %v, want a finite number", name, v)
+		}
+	}
+	if result.GuessesLog10 < 900 {
+		t.Errorf("GuessesLog10 = %v, want at least 900 for 1000 characters", result.GuessesLog10)
+	}
+	if result.Score != MaxScore {
+		t.Errorf("Score = %d, want %d", result.Score, MaxScore)
+	}
+	if _, err := json.Marshal(result); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
+
+func TestEstimate_CrackTimes(t *testing.T) {
+	result := Estimate("password")
+	if result.CrackTimeDisplay != "less than a second" {
+		t.Errorf("CrackTimeDisplay = %q, want less than a second", result.CrackTimeDisplay)
+	}
+	if result.CrackTimes.OnlineThrottling <= result.CrackTimes.OfflineFastHashing {
+		t.Errorf("online throttled crack time should exceed offline fast hashing: %+v", result.CrackTimes)
+	}
+	if result.Warning != "This is a top-10 common password" {
+		t.Errorf("Warning = %q", result.Warning)
+	}
+}
+
+func TestEstimator_DateRelativeToNow(t *testing.T) {
+	e := NewEstimator()
+	e.now = func() time.Time { return time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	recent := e.Estimate("1989")
+	old := e.Estimate("1920")
+	if recent.Guesses >= old.Guesses {
+		t.Errorf("year close to now should be cheaper: 1989=%v 1920=%v", recent.Guesses, old.Guesses)
+	}
+}
+
+func TestDisplayTime(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0.5, "less than a second"},
+		{1, "1 second"},
+		{45, "45 seconds"},
+		{3600 * 3, "3 hours"},
+		{86400 * 2, "2 days"},
+		{86400 * 31 * 12 * 5, "5 years"},
+		{1e12, "centuries"},
+	}
+
+	for _, tt := range tests {
+		if got := displayTime(tt.seconds); got != tt.want {
+			t.Errorf("displayTime(%v) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func patterns(sequence []Match) []string {
+	out := make([]string, len(sequence))
+	for i, m := range sequence {
+		out[i] = string(m.Pattern) + ":" + m.Token
+	}
+	return out
+}
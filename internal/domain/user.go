@@ -0,0 +1,68 @@
+package domain
+
+import "context"
+
+// UserInfo describes the account a password is being chosen for. Rules that
+// look for personal data in the password read it from the validation
+// context, so validators that do not care about it are unaffected.
+type UserInfo struct {
+	Username    string
+	Email       string
+	FirstName   string
+	LastName    string
+	CompanyName string
+}
+
+// Inputs lists the non-empty fields, for use as user-specific dictionary
+// words when estimating strength.
+func (u UserInfo) Inputs() []string {
+	var inputs []string
+	for _, field := range []string{u.Username, u.Email, u.FirstName, u.LastName, u.CompanyName} {
+		if field != "" {
+			inputs = append(inputs, field)
+		}
+	}
+	return inputs
+}
+
+type userInfoKey struct{}
+
+// WithUserInfo returns a copy of ctx carrying the user information.
+func WithUserInfo(ctx context.Context, user UserInfo) context.Context {
+	return context.WithValue(ctx, userInfoKey{}, user)
+}
+
+// UserInfoFromContext returns the user information stored by WithUserInfo.
+func UserInfoFromContext(ctx context.Context) (UserInfo, bool) {
+	user, ok := ctx.Value(userInfoKey{}).(UserInfo)
+	return user, ok
+}
+
+type subjectKey struct{}
+
+// WithSubject returns a copy of ctx identifying the subject (user, account or
+// service) whose password is being validated.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFromContext returns the subject stored by WithSubject.
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok && subject != ""
+}
+
+type previousPasswordKey struct{}
+
+// WithPreviousPassword returns a copy of ctx carrying the password being
+// replaced, for rules that compare the new password with it.
+func WithPreviousPassword(ctx context.Context, password string) context.Context {
+	return context.WithValue(ctx, previousPasswordKey{}, password)
+}
+
+// PreviousPasswordFromContext returns the password stored by
+// WithPreviousPassword.
+func PreviousPasswordFromContext(ctx context.Context) (string, bool) {
+	password, ok := ctx.Value(previousPasswordKey{}).(string)
+	return password, ok && password != ""
+}
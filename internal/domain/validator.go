@@ -1,5 +1,52 @@
 package domain
 
+import "context"
+
 type PasswordValidator interface {
 	Validate(password string) error
 }
+
+// ContextValidator is implemented by validators that perform I/O, such as
+// remote lookups, and therefore need to honour cancellation and deadlines.
+// Callers that have a context should prefer ValidateContext over Validate.
+type ContextValidator interface {
+	PasswordValidator
+	ValidateContext(ctx context.Context, password string) error
+}
+
+// ValidateContext runs the validator with ctx when it supports contexts and
+// falls back to Validate otherwise.
+func ValidateContext(ctx context.Context, v PasswordValidator, password string) error {
+	if cv, ok := v.(ContextValidator); ok {
+		return cv.ValidateContext(ctx, password)
+	}
+	return v.Validate(password)
+}
+
+// RuleDescription documents a rule for clients, using the same codes and
+// parameter names found in the violations the rule produces.
+type RuleDescription struct {
+	Rule        string
+	Codes       []string
+	Description string
+	Params      map[string]any
+}
+
+// Describer is implemented by validators that can describe themselves, so
+// the active policy can be published without duplicating its text.
+type Describer interface {
+	Describe() RuleDescription
+}
+
+// Describe returns the description of v, reporting validators that do not
+// implement Describer as unknown custom rules.
+func Describe(v PasswordValidator) RuleDescription {
+	if d, ok := v.(Describer); ok {
+		return d.Describe()
+	}
+	return RuleDescription{
+		Rule:        RuleUnknown,
+		Codes:       []string{CodeUnknown},
+		Description: "Custom rule",
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "errors"
+
+// Violation is the structured error returned by validators when a password
+// breaks a rule. Code is a stable, machine-readable identifier that clients
+// can rely on, while Message keeps the legacy human-readable text.
+type Violation struct {
+	Code     string
+	Rule     string
+	Message  string
+	Params   map[string]any
+	Char     string
+	Position int
+}
+
+// NoPosition marks violations that are not tied to a specific character.
+const NoPosition = -1
+
+// Unknown violation fields used for validators that return plain errors.
+const (
+	CodeUnknown = "UNKNOWN"
+	RuleUnknown = "unknown"
+)
+
+func NewViolation(code, rule, message string) *Violation {
+	return &Violation{
+		Code:     code,
+		Rule:     rule,
+		Message:  message,
+		Position: NoPosition,
+	}
+}
+
+// WithParam returns a copy of the violation with a rule parameter (e.g. the
+// required length) attached. The violation itself is left unchanged, so it
+// is safe to call on the shared sentinel errors.
+func (v *Violation) WithParam(key string, value any) *Violation {
+	c := *v
+	c.Params = make(map[string]any, len(v.Params)+1)
+	for k, p := range v.Params {
+		c.Params[k] = p
+	}
+	c.Params[key] = value
+	return &c
+}
+
+// At returns a copy of the violation recording the offending character and
+// its position (in runes). The violation itself is left unchanged.
+func (v *Violation) At(char rune, position int) *Violation {
+	c := *v
+	c.Char = string(char)
+	c.Position = position
+	return &c
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// Is reports whether target is a violation with the same code, so that
+// errors.Is keeps working against the exported sentinel errors.
+func (v *Violation) Is(target error) bool {
+	t, ok := target.(*Violation)
+	return ok && t.Code == v.Code
+}
+
+// AsViolation converts any validator error into a Violation. Errors that are
+// not violations are reported with the UNKNOWN code and their original message.
+func AsViolation(err error) Violation {
+	var v *Violation
+	if errors.As(err, &v) {
+		return *v
+	}
+	return *NewViolation(CodeUnknown, RuleUnknown, err.Error())
+}
@@ -0,0 +1,371 @@
+// Package generator creates random passwords and passphrases that meet the
+// character requirements of a password policy, using crypto/rand.
+package generator
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+const (
+	ModeRandom     = "random"
+	ModePassphrase = "passphrase"
+)
+
+const (
+	DefaultLength = 16
+	MaxLength     = 128
+	DefaultWords  = 4
+	MaxWords      = 12
+	MaxCount      = 20
+)
+
+const (
+	lowercaseChars = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+
+	// ambiguousChars are easily confused when read or typed by hand.
+	ambiguousChars = "0O1lI|"
+
+	// defaultSeparators join passphrase words when the policy does not ask
+	// for special characters.
+	defaultSeparators = "-._"
+)
+
+var (
+	ErrInvalidOptions = errors.New("invalid generator options")
+	ErrUnsatisfiable  = errors.New("policy requirements cannot be met with these options")
+)
+
+//go:embed data/words.txt
+var wordsData string
+
+var words = strings.Fields(wordsData)
+
+// Requirements are the character constraints a generated password must meet.
+type Requirements struct {
+	MinLength    int
+	Lowercase    bool
+	Uppercase    bool
+	Digit        bool
+	Specials     string // when not empty, at least one of these is required
+	NoDuplicates bool
+}
+
+// RequirementsFromRules derives the requirements from the published
+// description of a policy. Rules it does not know about are ignored; callers
+// should still validate the result against the policy.
+func RequirementsFromRules(descriptions []domain.RuleDescription) Requirements {
+	var req Requirements
+	for _, d := range descriptions {
+		switch d.Rule {
+		case rules.RuleMinLength:
+			if n, ok := d.Params["minLength"].(int); ok && n > req.MinLength {
+				req.MinLength = n
+			}
+		case rules.RuleLowercase:
+			req.Lowercase = true
+		case rules.RuleUppercase:
+			req.Uppercase = true
+		case rules.RuleDigit:
+			req.Digit = true
+		case rules.RuleSpecialChar:
+			if chars, ok := d.Params["allowedChars"].(string); ok {
+				req.Specials = chars
+			}
+		case rules.RuleNoDuplicates:
+			req.NoDuplicates = true
+		}
+	}
+	return req
+}
+
+// Options tune the generated passwords. Zero values select the defaults.
+type Options struct {
+	Mode             string
+	Length           int // random mode only
+	Words            int // passphrase mode only
+	ExcludeAmbiguous bool
+}
+
+// Generate creates one password meeting req.
+func Generate(req Requirements, opts Options) (string, error) {
+	switch opts.Mode {
+	case "", ModeRandom:
+		return generateRandom(req, opts)
+	case ModePassphrase:
+		return generatePassphrase(req, opts)
+	default:
+		return "", fmt.Errorf("%w: mode must be %q or %q", ErrInvalidOptions, ModeRandom, ModePassphrase)
+	}
+}
+
+func generateRandom(req Requirements, opts Options) (string, error) {
+	length := opts.Length
+	if length == 0 {
+		length = max(DefaultLength, req.MinLength)
+	}
+	if length < req.MinLength || length > MaxLength {
+		return "", fmt.Errorf("%w: length must be between %d and %d", ErrInvalidOptions, max(req.MinLength, 1), MaxLength)
+	}
+
+	filter := func(chars string) []rune {
+		if !opts.ExcludeAmbiguous {
+			return []rune(chars)
+		}
+		return withoutAmbiguous([]rune(chars))
+	}
+
+	lower, upper, digits, specials := filter(lowercaseChars), filter(uppercaseChars), filter(digitChars), filter(req.Specials)
+
+	var required [][]rune
+	if req.Lowercase {
+		required = append(required, lower)
+	}
+	if req.Uppercase {
+		required = append(required, upper)
+	}
+	if req.Digit {
+		required = append(required, digits)
+	}
+	if req.Specials != "" {
+		required = append(required, specials)
+	}
+
+	pool := append(append(append([]rune{}, lower...), upper...), digits...)
+	if req.Specials != "" {
+		pool = append(pool, specials...)
+	}
+	pool = uniqueRunes(pool)
+	if req.NoDuplicates && length > len(pool) {
+		return "", fmt.Errorf("%w: at most %d distinct characters are available", ErrUnsatisfiable, len(pool))
+	}
+
+	used := make(map[rune]bool)
+	password := make([]rune, 0, length)
+	pick := func(from []rune) error {
+		candidates := from
+		if req.NoDuplicates {
+			candidates = candidates[:0:0]
+			for _, r := range from {
+				if !used[r] {
+					candidates = append(candidates, r)
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			return ErrUnsatisfiable
+		}
+		i, err := randIndex(len(candidates))
+		if err != nil {
+			return err
+		}
+		used[candidates[i]] = true
+		password = append(password, candidates[i])
+		return nil
+	}
+
+	for _, class := range required {
+		if err := pick(class); err != nil {
+			return "", err
+		}
+	}
+	for len(password) < length {
+		if err := pick(pool); err != nil {
+			return "", err
+		}
+	}
+
+	if err := shuffle(password); err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+func generatePassphrase(req Requirements, opts Options) (string, error) {
+	count := opts.Words
+	if count == 0 {
+		count = DefaultWords
+	}
+	if count < 1 || count > MaxWords {
+		return "", fmt.Errorf("%w: words must be between 1 and %d", ErrInvalidOptions, MaxWords)
+	}
+
+	// Without repeated characters an early choice of words can leave no word
+	// that fits, so start over a few times before giving up.
+	const attempts = 20
+
+	var err error
+	for range attempts {
+		var passphrase string
+		passphrase, err = buildPassphrase(req, opts, count)
+		if err == nil || !errors.Is(err, ErrUnsatisfiable) {
+			return passphrase, err
+		}
+	}
+	return "", err
+}
+
+func buildPassphrase(req Requirements, opts Options, count int) (string, error) {
+	separators := []rune(defaultSeparators)
+	if req.Specials != "" {
+		separators = []rune(req.Specials)
+	}
+	if opts.ExcludeAmbiguous {
+		separators = withoutAmbiguous(separators)
+	}
+
+	used := make(map[rune]bool)
+	var b strings.Builder
+	written := 0
+	for written < count || b.Len() < req.MinLength {
+		if written == MaxWords {
+			return "", fmt.Errorf("%w: minimum length not reached with %d words", ErrUnsatisfiable, MaxWords)
+		}
+		if written > 0 {
+			if err := writeRune(&b, separators, used, req.NoDuplicates); err != nil {
+				return "", err
+			}
+		}
+
+		word, err := pickWord(used, req.NoDuplicates, opts.ExcludeAmbiguous)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(word)
+		written++
+	}
+
+	// A single word has no separator to carry the special character.
+	if written == 1 && req.Specials != "" {
+		if err := writeRune(&b, separators, used, req.NoDuplicates); err != nil {
+			return "", err
+		}
+	}
+	if req.Digit {
+		digits := []rune(digitChars)
+		if opts.ExcludeAmbiguous {
+			digits = withoutAmbiguous(digits)
+		}
+		if err := writeRune(&b, digits, used, req.NoDuplicates); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// pickWord draws a capitalised word. With noDuplicates, only words that
+// repeat no character, neither within themselves nor from the passphrase so
+// far, are candidates.
+func pickWord(used map[rune]bool, noDuplicates, excludeAmbiguous bool) (string, error) {
+	var candidates []string
+	for _, w := range words {
+		word := []rune(w)
+		word[0] = unicode.ToUpper(word[0])
+		if fits(word, used, noDuplicates, excludeAmbiguous) {
+			candidates = append(candidates, string(word))
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: not enough words without repeated characters", ErrUnsatisfiable)
+	}
+
+	i, err := randIndex(len(candidates))
+	if err != nil {
+		return "", err
+	}
+	for _, r := range candidates[i] {
+		used[r] = true
+	}
+	return candidates[i], nil
+}
+
+func fits(word []rune, used map[rune]bool, noDuplicates, excludeAmbiguous bool) bool {
+	if excludeAmbiguous && strings.ContainsAny(string(word), ambiguousChars) {
+		return false
+	}
+	if !noDuplicates {
+		return true
+	}
+	seen := make(map[rune]bool, len(word))
+	for _, r := range word {
+		if used[r] || seen[r] {
+			return false
+		}
+		seen[r] = true
+	}
+	return true
+}
+
+// writeRune appends a random rune from the set, skipping used ones when
+// noDuplicates is set.
+func writeRune(b *strings.Builder, from []rune, used map[rune]bool, noDuplicates bool) error {
+	var candidates []rune
+	for _, r := range from {
+		if !noDuplicates || !used[r] {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("%w: not enough distinct separators or digits", ErrUnsatisfiable)
+	}
+
+	i, err := randIndex(len(candidates))
+	if err != nil {
+		return err
+	}
+	used[candidates[i]] = true
+	b.WriteRune(candidates[i])
+	return nil
+}
+
+func randIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// shuffle is a Fisher-Yates shuffle driven by crypto/rand.
+func shuffle(runes []rune) error {
+	for i := len(runes) - 1; i > 0; i-- {
+		j, err := randIndex(i + 1)
+		if err != nil {
+			return err
+		}
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return nil
+}
+
+func withoutAmbiguous(runes []rune) []rune {
+	var out []rune
+	for _, r := range runes {
+		if !strings.ContainsRune(ambiguousChars, r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func uniqueRunes(runes []rune) []rune {
+	seen := make(map[rune]bool, len(runes))
+	out := runes[:0]
+	for _, r := range runes {
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	return out
+}
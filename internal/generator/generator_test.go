@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+func defaultValidators(specials string) []domain.PasswordValidator {
+	return []domain.PasswordValidator{
+		rules.NewMinLengthValidator(9),
+		rules.NewDigitValidator(),
+		rules.NewLowercaseValidator(),
+		rules.NewUppercaseValidator(),
+		rules.NewSpecialCharValidator(specials),
+		rules.NewNoDuplicatesValidator(),
+	}
+}
+
+func describe(validators []domain.PasswordValidator) []domain.RuleDescription {
+	var descriptions []domain.RuleDescription
+	for _, v := range validators {
+		descriptions = append(descriptions, v.(domain.Describer).Describe())
+	}
+	return descriptions
+}
+
+func TestRequirementsFromRules(t *testing.T) {
+	req := RequirementsFromRules(describe(defaultValidators("!@#")))
+
+	want := Requirements{MinLength: 9, Lowercase: true, Uppercase: true, Digit: true, Specials: "!@#", NoDuplicates: true}
+	if req != want {
+		t.Errorf("RequirementsFromRules() = %+v, want %+v", req, want)
+	}
+}
+
+func TestGenerate_SatisfiesPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		specials string
+		opts     Options
+	}{
+		{name: "random default length", specials: "!@#$%^&*()-+", opts: Options{}},
+		{name: "random minimum length", specials: "!@#$%^&*()-+", opts: Options{Length: 9}},
+		{name: "random single special", specials: "+", opts: Options{Length: 40}},
+		{name: "random without ambiguous", specials: "!@#$%^&*()-+", opts: Options{Length: 20, ExcludeAmbiguous: true}},
+		{name: "passphrase", specials: "!@#$%^&*()-+", opts: Options{Mode: ModePassphrase}},
+		{name: "passphrase single word", specials: "!@#$%^&*()-+", opts: Options{Mode: ModePassphrase, Words: 1}},
+		{name: "passphrase without ambiguous", specials: "!@#$%^&*()-+", opts: Options{Mode: ModePassphrase, Words: 3, ExcludeAmbiguous: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validators := defaultValidators(tt.specials)
+			req := RequirementsFromRules(describe(validators))
+
+			for range 200 {
+				password, err := Generate(req, tt.opts)
+				if err != nil {
+					t.Fatalf("Generate() unexpected error: %v", err)
+				}
+				for _, v := range validators {
+					if err := v.Validate(password); err != nil {
+						t.Fatalf("Generate() = %q, rejected: %v", password, err)
+					}
+				}
+				if tt.opts.ExcludeAmbiguous && strings.ContainsAny(password, ambiguousChars) {
+					t.Fatalf("Generate() = %q contains ambiguous characters", password)
+				}
+				if tt.opts.Length != 0 && len(password) != tt.opts.Length {
+					t.Fatalf("Generate() = %q, want length %d", password, tt.opts.Length)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_IsRandom(t *testing.T) {
+	req := Requirements{MinLength: 9, Lowercase: true}
+	seen := make(map[string]bool)
+	for range 100 {
+		password, _ := Generate(req, Options{})
+		if seen[password] {
+			t.Fatalf("Generate() repeated %q", password)
+		}
+		seen[password] = true
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     Requirements
+		opts    Options
+		wantErr error
+	}{
+		{"length below policy minimum", Requirements{MinLength: 12}, Options{Length: 8}, ErrInvalidOptions},
+		{"length above maximum", Requirements{}, Options{Length: MaxLength + 1}, ErrInvalidOptions},
+		{"unknown mode", Requirements{}, Options{Mode: "pin"}, ErrInvalidOptions},
+		{"too many words", Requirements{}, Options{Mode: ModePassphrase, Words: MaxWords + 1}, ErrInvalidOptions},
+		{"more unique characters than available", Requirements{NoDuplicates: true}, Options{Length: 63}, ErrUnsatisfiable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.req, tt.opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWordList(t *testing.T) {
+	if len(words) != 2048 {
+		t.Errorf("word list has %d words, want 2048", len(words))
+	}
+}
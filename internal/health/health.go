@@ -0,0 +1,72 @@
+// Package health tracks whether the server is ready to take traffic. The
+// parts of the server that are not always ready (the policies while they
+// load, the whole server while it shuts down) report it on a Probe, which
+// the readiness endpoints expose.
+package health
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Probe collects the reasons the server is not ready. It is safe for
+// concurrent use; a nil Probe is always ready.
+type Probe struct {
+	mu       sync.RWMutex
+	pending  map[string]string
+	watchers []func(ready bool)
+}
+
+func NewProbe() *Probe {
+	return &Probe{pending: make(map[string]string)}
+}
+
+// NotReady marks component as not ready, for reason.
+func (p *Probe) NotReady(component, reason string) {
+	p.update(func() { p.pending[component] = reason })
+}
+
+// Ready marks component as ready again.
+func (p *Probe) Ready(component string) {
+	p.update(func() { delete(p.pending, component) })
+}
+
+func (p *Probe) update(change func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	before := len(p.pending) == 0
+	change()
+	if after := len(p.pending) == 0; after != before {
+		for _, watch := range p.watchers {
+			watch(after)
+		}
+	}
+}
+
+// Watch calls f with the current state and then whenever the server becomes
+// ready or stops being ready, e.g. to update the gRPC health service. Calls
+// are made in order, with the probe locked: f must not use the probe.
+func (p *Probe) Watch(f func(ready bool)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.watchers = append(p.watchers, f)
+	f(len(p.pending) == 0)
+}
+
+// Status reports whether every component is ready and, if not, why, as
+// "component: reason" lines in a stable order.
+func (p *Probe) Status() (ready bool, reasons []string) {
+	if p == nil {
+		return true, nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, component := range slices.Sorted(maps.Keys(p.pending)) {
+		reasons = append(reasons, component+": "+p.pending[component])
+	}
+	return len(reasons) == 0, reasons
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	probe := NewProbe()
+	var changes []bool
+	probe.Watch(func(ready bool) { changes = append(changes, ready) })
+
+	if ready, reasons := probe.Status(); !ready || reasons != nil {
+		t.Fatalf("Status() = %v, %v, want a new probe to be ready", ready, reasons)
+	}
+
+	probe.NotReady("shutdown", "draining connections")
+	probe.NotReady("policies", "loading")
+	ready, reasons := probe.Status()
+	if want := []string{"policies: loading", "shutdown: draining connections"}; ready || !slices.Equal(reasons, want) {
+		t.Errorf("Status() = %v, %v, want not ready with %v", ready, reasons, want)
+	}
+
+	probe.Ready("policies")
+	probe.Ready("unknown")
+	if ready, _ := probe.Status(); ready {
+		t.Error("Status() is ready while shutting down")
+	}
+	probe.Ready("shutdown")
+	if ready, _ := probe.Status(); !ready {
+		t.Error("Status() is not ready once every component is")
+	}
+
+	// Watchers see the initial state and every transition, once.
+	if want := []bool{true, false, true}; !slices.Equal(changes, want) {
+		t.Errorf("watched changes = %v, want %v", changes, want)
+	}
+}
+
+func TestProbe_Nil(t *testing.T) {
+	var probe *Probe
+	if ready, reasons := probe.Status(); !ready || reasons != nil {
+		t.Errorf("Status() = %v, %v, want a nil probe to be ready", ready, reasons)
+	}
+}
@@ -0,0 +1,89 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps password history in a JSON file mapping each subject to
+// its hashes, newest first. Every Append rewrites the file through a
+// temporary file and a rename, so a crash never leaves it half written. The
+// whole history is held in memory; it suits deployments with up to a few
+// hundred thousand subjects served by a single instance.
+type FileStore struct {
+	path string
+
+	mu       sync.RWMutex
+	subjects map[string][]string
+}
+
+// NewFileStore opens the history file at path, creating it on the first
+// Append if it does not exist.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, subjects: make(map[string][]string)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading password history file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.subjects); err != nil {
+		return nil, fmt.Errorf("parsing password history file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *FileStore) Recent(ctx context.Context, subject string, n int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return recent(s.subjects[subject], n), nil
+}
+
+func (s *FileStore) Append(ctx context.Context, subject, hash string, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.subjects[subject]
+	s.subjects[subject] = prepend(previous, hash, keep)
+	if err := s.save(); err != nil {
+		s.subjects[subject] = previous
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) save() error {
+	data, err := json.Marshal(s.subjects)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,102 @@
+// Package history remembers the passwords each subject has used, as slow
+// hashes, so that recently used passwords can be refused.
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultDepth is how many previous passwords are remembered per subject.
+const DefaultDepth = 5
+
+// MaxSubjectLength bounds subject identifiers accepted by History.
+const MaxSubjectLength = 256
+
+var ErrInvalidSubject = errors.New("subject id must be between 1 and 256 characters")
+
+// PasswordHistoryStore persists password hashes per subject. Implementations
+// never see plain-text passwords.
+type PasswordHistoryStore interface {
+	// Recent returns up to n hashes for subject, newest first.
+	Recent(ctx context.Context, subject string, n int) ([]string, error)
+	// Append records hash as the newest entry for subject and discards all
+	// but the keep newest entries.
+	Append(ctx context.Context, subject, hash string, keep int) error
+}
+
+// History checks and records passwords against the last Depth entries of
+// each subject.
+type History struct {
+	store  PasswordHistoryStore
+	hasher Hasher
+	depth  int
+}
+
+func NewHistory(store PasswordHistoryStore, hasher Hasher, depth int) *History {
+	if depth < 1 {
+		depth = DefaultDepth
+	}
+	return &History{
+		store:  store,
+		hasher: hasher,
+		depth:  depth,
+	}
+}
+
+// Depth is the number of previous passwords remembered per subject.
+func (h *History) Depth() int {
+	return h.depth
+}
+
+// Contains reports whether password is one of the subject's recent
+// passwords. Hashes made with any supported algorithm are recognised, so the
+// configured hasher can change without forgetting older entries.
+func (h *History) Contains(ctx context.Context, subject, password string) (bool, error) {
+	if err := validSubject(subject); err != nil {
+		return false, err
+	}
+
+	hashes, err := h.store.Recent(ctx, subject, h.depth)
+	if err != nil {
+		return false, fmt.Errorf("reading password history: %w", err)
+	}
+
+	for _, hash := range hashes {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		match, err := Verify(hash, password)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Record stores password as the subject's newest password.
+func (h *History) Record(ctx context.Context, subject, password string) error {
+	if err := validSubject(subject); err != nil {
+		return err
+	}
+
+	hash, err := h.hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+	if err := h.store.Append(ctx, subject, hash, h.depth); err != nil {
+		return fmt.Errorf("recording password history: %w", err)
+	}
+	return nil
+}
+
+func validSubject(subject string) error {
+	if subject == "" || len(subject) > MaxSubjectLength {
+		return ErrInvalidSubject
+	}
+	return nil
+}
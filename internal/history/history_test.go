@@ -0,0 +1,131 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testArgon2Params keep the tests fast; production uses DefaultArgon2Params.
+var testArgon2Params = Argon2Params{Memory: 64, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32}
+
+func TestHashers(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher Hasher
+		prefix string
+	}{
+		{"argon2id", NewArgon2idHasher(testArgon2Params), "$argon2id$v=19$m=64,t=1,p=1$"},
+		{"bcrypt", NewBcryptHasher(4), "$2a$04$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("AbTp9!fok")
+			if err != nil {
+				t.Fatalf("Hash() unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(hash, tt.prefix) || strings.Contains(hash, "AbTp9!fok") {
+				t.Errorf("Hash() = %q, want prefix %q", hash, tt.prefix)
+			}
+
+			if ok, err := Verify(hash, "AbTp9!fok"); !ok || err != nil {
+				t.Errorf("Verify() with the same password = %v, %v", ok, err)
+			}
+			if ok, err := Verify(hash, "AbTp9!foK"); ok || err != nil {
+				t.Errorf("Verify() with another password = %v, %v", ok, err)
+			}
+
+			other, _ := tt.hasher.Hash("AbTp9!fok")
+			if other == hash {
+				t.Error("Hash() is not salted")
+			}
+		})
+	}
+}
+
+func TestVerify_UnknownHash(t *testing.T) {
+	for _, hash := range []string{"", "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", "$argon2id$v=19$broken"} {
+		if _, err := Verify(hash, "password"); !errors.Is(err, ErrUnknownHash) {
+			t.Errorf("Verify(%q) error = %v, want ErrUnknownHash", hash, err)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	h := NewHistory(NewMemoryStore(), NewArgon2idHasher(testArgon2Params), 3)
+
+	for _, password := range []string{"first-1A!", "second-2B!", "third-3C!", "fourth-4D!"} {
+		if err := h.Record(ctx, "user-42", password); err != nil {
+			t.Fatalf("Record() unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		subject  string
+		password string
+		want     bool
+	}{
+		{"user-42", "fourth-4D!", true},
+		{"user-42", "second-2B!", true},
+		{"user-42", "first-1A!", false}, // beyond the depth of 3
+		{"user-42", "never-used", false},
+		{"user-7", "fourth-4D!", false},
+	}
+
+	for _, tt := range tests {
+		got, err := h.Contains(ctx, tt.subject, tt.password)
+		if err != nil || got != tt.want {
+			t.Errorf("Contains(%s, %s) = %v, %v; want %v", tt.subject, tt.password, got, err, tt.want)
+		}
+	}
+}
+
+func TestHistory_InvalidSubject(t *testing.T) {
+	h := NewHistory(NewMemoryStore(), NewBcryptHasher(4), 0)
+
+	if h.Depth() != DefaultDepth {
+		t.Errorf("Depth() = %d, want %d", h.Depth(), DefaultDepth)
+	}
+	if err := h.Record(context.Background(), "", "password"); !errors.Is(err, ErrInvalidSubject) {
+		t.Errorf("Record() error = %v, want ErrInvalidSubject", err)
+	}
+	if _, err := h.Contains(context.Background(), strings.Repeat("x", MaxSubjectLength+1), "password"); !errors.Is(err, ErrInvalidSubject) {
+		t.Errorf("Contains() error = %v, want ErrInvalidSubject", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() unexpected error: %v", err)
+	}
+	store.Append(ctx, "user-42", "$hash-1", 2)
+	store.Append(ctx, "user-42", "$hash-2", 2)
+	store.Append(ctx, "user-42", "$hash-3", 2)
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() reopening: %v", err)
+	}
+	hashes, _ := reopened.Recent(ctx, "user-42", 5)
+	if strings.Join(hashes, ",") != "$hash-3,$hash-2" {
+		t.Errorf("Recent() after reopening = %v, want [$hash-3 $hash-2]", hashes)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("history file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	os.WriteFile(path, []byte("{not json"), 0o600)
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("NewFileStore() expected error for corrupt file")
+	}
+}
@@ -0,0 +1,48 @@
+package history
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps password history in memory. History is lost on restart,
+// so it is meant for tests and single-instance deployments without audit
+// requirements.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	subjects map[string][]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{subjects: make(map[string][]string)}
+}
+
+func (s *MemoryStore) Recent(ctx context.Context, subject string, n int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return recent(s.subjects[subject], n), nil
+}
+
+func (s *MemoryStore) Append(ctx context.Context, subject, hash string, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects[subject] = prepend(s.subjects[subject], hash, keep)
+	return nil
+}
+
+// recent copies the n newest hashes.
+func recent(hashes []string, n int) []string {
+	if n > len(hashes) {
+		n = len(hashes)
+	}
+	return append([]string(nil), hashes[:n]...)
+}
+
+// prepend adds hash as the newest entry and trims the list to keep entries.
+func prepend(hashes []string, hash string, keep int) []string {
+	hashes = append([]string{hash}, hashes...)
+	if len(hashes) > keep {
+		hashes = hashes[:keep]
+	}
+	return hashes
+}
@@ -0,0 +1,178 @@
+// Package i18n translates violation messages. English is the language of the
+// rules themselves, so an English request gets Violation.Message unchanged;
+// the other locales have a catalog in locales/, keyed by violation code.
+//
+// Codes whose message depends on a parameter use the key CODE.value, where
+// value is the "reason" or "field" parameter of the violation (e.g.
+// "CONTAINS_PERSONAL_INFO.email"). Templates refer to other parameters as
+// {name}. A missing entry or parameter falls back to the English message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+)
+
+// Supported locales, as BCP 47 tags.
+const (
+	English             = "en"
+	BrazilianPortuguese = "pt-BR"
+	Spanish             = "es"
+)
+
+// Default is the locale used when the client does not ask for a supported one.
+const Default = English
+
+// variantParams are the violation parameters that select a variant of a
+// code's message.
+var variantParams = []string{"reason", "field"}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// catalogs maps each locale but English to its messages.
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := localesFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localesFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", file.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return catalogs
+}
+
+// Locales lists the supported locales, English first.
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs)+1)
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return append([]string{English}, locales...)
+}
+
+// Match returns the supported locale for tag, ignoring case and matching
+// other regions of the same language ("pt", "pt_PT" and "PT-br" all give
+// pt-BR). It returns "" when the language is not supported.
+func Match(tag string) string {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range Locales() {
+		base, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(language, base) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// Negotiate picks the supported locale the client prefers in an
+// Accept-Language header, or Default.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale  string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if locale := Match(tag); locale != "" && quality > 0 {
+			candidates = append(candidates, candidate{locale, quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	if len(candidates) == 0 {
+		return Default
+	}
+	return candidates[0].locale
+}
+
+// Select returns the locale of a request: the locale field when it names a
+// supported language, otherwise the one negotiated from Accept-Language.
+func Select(field, acceptLanguage string) string {
+	if locale := Match(field); locale != "" {
+		return locale
+	}
+	return Negotiate(acceptLanguage)
+}
+
+// Message returns the message of v in locale.
+func Message(locale string, v domain.Violation) string {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return v.Message
+	}
+
+	template, ok := "", false
+	for _, param := range variantParams {
+		if value, has := v.Params[param]; has {
+			template, ok = catalog[fmt.Sprintf("%s.%v", v.Code, value)]
+			break
+		}
+	}
+	if !ok {
+		template, ok = catalog[v.Code]
+	}
+	if !ok {
+		return v.Message
+	}
+
+	missing := false
+	message := placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		value, has := v.Params[match[1:len(match)-1]]
+		if !has {
+			missing = true
+			return match
+		}
+		return fmt.Sprint(value)
+	})
+	if missing {
+		return v.Message
+	}
+	return message
+}
+
+// Localize returns a copy of violations with their messages in locale.
+func Localize(locale string, violations []domain.Violation) []domain.Violation {
+	localized := make([]domain.Violation, len(violations))
+	for i, v := range violations {
+		v.Message = Message(locale, v)
+		localized[i] = v
+	}
+	return localized
+}
@@ -0,0 +1,156 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", English},
+		{"pt-BR", BrazilianPortuguese},
+		{"pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7", BrazilianPortuguese},
+		{"pt", BrazilianPortuguese},
+		{"pt-PT", BrazilianPortuguese},
+		{"es-AR, en;q=0.5", Spanish},
+		{"en;q=0.5, es;q=0.8", Spanish},
+		{"fr-FR, de;q=0.9", English},
+		{"fr-FR, es;q=0.1", Spanish},
+		{"es;q=0, pt-BR;q=0.2", BrazilianPortuguese},
+		{"*", English},
+		{"pt-BR;q=abc, es", Spanish},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		field, header string
+		want          string
+	}{
+		{"", "es", Spanish},
+		{"pt-BR", "es", BrazilianPortuguese},
+		{"pt_br", "", BrazilianPortuguese},
+		{"EN", "pt-BR", English},
+		{"fr", "es", Spanish},
+		{"fr", "", English},
+	}
+
+	for _, tt := range tests {
+		if got := Select(tt.field, tt.header); got != tt.want {
+			t.Errorf("Select(%q, %q) = %q, want %q", tt.field, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	minLength := *domain.NewViolation(rules.CodeMinLength, rules.RuleMinLength, "password must have at least 9 characters").
+		WithParam("minLength", 9)
+	email := *domain.NewViolation(rules.CodeContainsPersonalInfo, rules.RulePersonalInfo, "password must not contain your email address").
+		WithParam("field", "email")
+
+	tests := []struct {
+		name      string
+		locale    string
+		violation domain.Violation
+		want      string
+	}{
+		{"english is the rule message", English, minLength, "password must have at least 9 characters"},
+		{"parameters are interpolated", BrazilianPortuguese, minLength, "a senha deve ter pelo menos 9 caracteres"},
+		{"variant by parameter", Spanish, email, "la contraseña no debe contener su dirección de correo electrónico"},
+		{"unknown locale", "fr", minLength, "password must have at least 9 characters"},
+		{"unknown code", BrazilianPortuguese, *domain.NewViolation("CUSTOM", "custom", "custom message"), "custom message"},
+		{"missing parameter", BrazilianPortuguese, *domain.NewViolation(rules.CodeMinLength, rules.RuleMinLength, "too short"), "too short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.locale, tt.violation); got != tt.want {
+				t.Errorf("Message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	if got := Locales(); !slices.Equal(got, []string{English, Spanish, BrazilianPortuguese}) {
+		t.Fatalf("Locales() = %v", got)
+	}
+
+	// Every catalog translates the same keys.
+	keys := slices.Sorted(maps.Keys(catalogs[BrazilianPortuguese]))
+	for locale, catalog := range catalogs {
+		if got := slices.Sorted(maps.Keys(catalog)); !slices.Equal(got, keys) {
+			t.Errorf("%s keys = %v, want %v", locale, got, keys)
+		}
+	}
+
+	// Every violation of the built-in rules is translated, with all of its
+	// placeholders filled in.
+	ctx := domain.WithUserInfo(context.Background(), domain.UserInfo{
+		Username: "jsilva", Email: "joao@example.com", FirstName: "Maria", LastName: "Souza", CompanyName: "Acme",
+	})
+	cases := []struct {
+		validator domain.PasswordValidator
+		ctx       context.Context
+		password  string
+	}{
+		{rules.NewMinLengthValidator(9), ctx, "abc"},
+		{rules.NewDigitValidator(), ctx, "abc"},
+		{rules.NewLowercaseValidator(), ctx, "ABC"},
+		{rules.NewUppercaseValidator(), ctx, "abc"},
+		{rules.NewSpecialCharValidator("!@#"), ctx, "abc"},
+		{rules.NewNoDuplicatesValidator(), ctx, "aab"},
+		{rules.NewNoDuplicatesValidator(), ctx, "a b"},
+		{rules.NewMinStrengthValidator(4), ctx, "password"},
+		{rules.NewPersonalInfoValidator(3), ctx, "xjsilvax"},
+		{rules.NewPersonalInfoValidator(3), ctx, "xjoaox"},
+		{rules.NewPersonalInfoValidator(3), ctx, "xmariax"},
+		{rules.NewPersonalInfoValidator(3), ctx, "xsouzax"},
+		{rules.NewPersonalInfoValidator(3), ctx, "xacmex"},
+		{rules.NewSimilarityValidator(3), domain.WithPreviousPassword(ctx, "Winter#1986"), "Winter#1987"},
+		{rules.NewSimilarityValidator(1), domain.WithPreviousPassword(ctx, "Winter#1986"), "Winter#2024"},
+		{rules.NewSimilarityValidator(1), domain.WithPreviousPassword(ctx, "Summer#1986!x"), "Summer-Tk9$Zq"},
+		{rules.NewPasswordHistoryValidator(staticHistory{used: true}), domain.WithSubject(ctx, "user-42"), "abc"},
+		{rules.NewPasswordHistoryValidator(staticHistory{err: errors.New("down")}), domain.WithSubject(ctx, "user-42"), "abc"},
+	}
+	for locale := range catalogs {
+		for _, c := range cases {
+			err := domain.ValidateContext(c.ctx, c.validator, c.password)
+			if err == nil {
+				t.Fatalf("%T accepted %q", c.validator, c.password)
+			}
+			v := domain.AsViolation(err)
+			got := Message(locale, v)
+			if got == v.Message || strings.Contains(got, "{") {
+				t.Errorf("%s: %s %v = %q, want a translation", locale, v.Code, v.Params, got)
+			}
+		}
+	}
+}
+
+type staticHistory struct {
+	used bool
+	err  error
+}
+
+func (h staticHistory) Contains(ctx context.Context, subject, password string) (bool, error) {
+	return h.used, h.err
+}
+
+func (h staticHistory) Depth() int { return 5 }
@@ -0,0 +1,31 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+const (
+	DefaultMinLength           = 9
+	DefaultAllowedSpecialChars = "!@#$%^&*()-+"
+	DefaultMinStrength         = 3
+	DefaultBreachCacheTTL      = time.Hour
+	DefaultBreachCacheSize     = 10000
+)
+
+// Default returns the built-in policy used when no policy file is configured.
+func Default() *Document {
+	return &Document{
+		Version: "default",
+		Hash:    "builtin",
+		Rules: []RuleConfig{
+			{Name: rules.RuleMinLength, Params: map[string]any{"length": DefaultMinLength}},
+			{Name: rules.RuleDigit},
+			{Name: rules.RuleLowercase},
+			{Name: rules.RuleUppercase},
+			{Name: rules.RuleSpecialChar, Params: map[string]any{"allowed": DefaultAllowedSpecialChars}},
+			{Name: rules.RuleNoDuplicates},
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Params gives rule factories typed, strict access to the raw parameters of
+// a rule. Any parameter not read by the factory is reported as unknown.
+type Params struct {
+	values map[string]any
+	used   map[string]bool
+}
+
+func newParams(values map[string]any) *Params {
+	return &Params{
+		values: values,
+		used:   make(map[string]bool),
+	}
+}
+
+func (p *Params) lookup(key string) (any, bool) {
+	p.used[key] = true
+	v, ok := p.values[key]
+	return v, ok && v != nil
+}
+
+func (p *Params) Int(key string, def int) (int, error) {
+	raw, ok := p.lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	switch v := raw.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("parameter %q must be an integer, got %v", key, v)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("parameter %q must be an integer, got %T", key, raw)
+	}
+}
+
+func (p *Params) String(key string, def string) (string, error) {
+	raw, ok := p.lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	v, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("parameter %q must be a string, got %T", key, raw)
+	}
+	return v, nil
+}
+
+func (p *Params) Bool(key string, def bool) (bool, error) {
+	raw, ok := p.lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	v, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("parameter %q must be a boolean, got %T", key, raw)
+	}
+	return v, nil
+}
+
+func (p *Params) Float(key string, def float64) (float64, error) {
+	raw, ok := p.lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("parameter %q must be a number, got %T", key, raw)
+	}
+}
+
+// Duration reads a Go duration string such as "500ms" or "1h".
+func (p *Params) Duration(key string, def time.Duration) (time.Duration, error) {
+	raw, ok := p.lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	text, ok := raw.(string)
+	if !ok {
+		return 0, fmt.Errorf("parameter %q must be a duration string such as \"2s\", got %T", key, raw)
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("parameter %q must be a positive duration such as \"2s\", got %q", key, text)
+	}
+	return d, nil
+}
+
+func (p *Params) checkUnused() error {
+	var unknown []string
+	for key := range p.values {
+		if !p.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown parameter(s): %s", strings.Join(unknown, ", "))
+}
@@ -0,0 +1,253 @@
+// Package policy turns a declarative policy document (YAML or JSON) into the
+// validators consumed by application.PasswordService.
+package policy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// Document is the on-disk representation of the password policies. A file
+// either lists Rules directly, which defines the default policy, or declares
+// several named Policies, one of which must be called "default".
+type Document struct {
+	Version  string                  `json:"version,omitempty" yaml:"version,omitempty"`
+	Rules    []RuleConfig            `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Policies map[string]PolicyConfig `json:"policies,omitempty" yaml:"policies,omitempty"`
+
+	// Hash identifies the exact content the document was parsed from.
+	Hash string `json:"-" yaml:"-"`
+
+	// built holds the validators Parse built to check the document, until
+	// Build hands them out.
+	built map[string][]domain.PasswordValidator
+}
+
+// PolicyConfig is a named set of rules, applied in the order they are listed.
+type PolicyConfig struct {
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Rules       []RuleConfig `json:"rules" yaml:"rules"`
+}
+
+// RuleConfig enables a single rule. Rules are enabled unless Enabled is
+// explicitly set to false.
+type RuleConfig struct {
+	Name    string         `json:"name" yaml:"name"`
+	Enabled *bool          `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Params  map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+func (r RuleConfig) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported policy file format (expected .yaml, .yml or .json)")
+
+// Load reads and parses a policy file, picking the format from its extension.
+func Load(path string) (*Document, error) {
+	data, format, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(path, data, format)
+}
+
+// readFile returns the content and format of the policy file at path.
+func readFile(path string) ([]byte, Format, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading policy file: %w", err)
+	}
+	return data, format, nil
+}
+
+// parseFile parses the content of the policy file at path.
+func parseFile(path string, data []byte, format Format) (*Document, error) {
+	doc, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
+
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Parse decodes a policy document, rejecting unknown fields, and checks that
+// every rule can be built. The validators built for the check are kept for
+// the first call to Build, so rules that load files (such as blocklist) read
+// them only once.
+func Parse(data []byte, format Format) (*Document, error) {
+	var doc Document
+
+	switch format {
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("decoding policy: %w", err)
+		}
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("decoding policy: %w", err)
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	built, err := doc.Build()
+	if err != nil {
+		return nil, err
+	}
+	doc.built = built
+	doc.Hash = contentHash(data)
+	return &doc, nil
+}
+
+// contentHash is the Document.Hash of a policy file with content data.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+var policyNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Named returns the policies declared by the document, keyed by name, with
+// the single-policy shorthand expanded to the default policy.
+func (d *Document) Named() (map[string]PolicyConfig, error) {
+	if len(d.Rules) > 0 && len(d.Policies) > 0 {
+		return nil, errors.New("policy must declare either rules or policies, not both")
+	}
+
+	if len(d.Policies) == 0 {
+		return map[string]PolicyConfig{
+			application.DefaultPolicy: {Rules: d.Rules},
+		}, nil
+	}
+
+	if _, ok := d.Policies[application.DefaultPolicy]; !ok {
+		return nil, application.ErrNoDefaultPolicy
+	}
+	for name := range d.Policies {
+		if !policyNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid policy name %q (use lowercase letters, digits, '-' and '_')", name)
+		}
+	}
+	return d.Policies, nil
+}
+
+// Build creates the validators of every policy in the document. On a
+// document returned by Parse, the first call returns the validators Parse
+// already built; later calls build new ones.
+func (d *Document) Build() (map[string][]domain.PasswordValidator, error) {
+	if built := d.built; built != nil {
+		d.built = nil
+		return built, nil
+	}
+
+	named, err := d.Named()
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string][]domain.PasswordValidator, len(named))
+	for name, cfg := range named {
+		validators, err := BuildRules(cfg.Rules)
+		if err != nil {
+			if len(d.Policies) > 0 {
+				return nil, fmt.Errorf("policies.%s: %w", name, err)
+			}
+			return nil, err
+		}
+		policies[name] = validators
+	}
+	return policies, nil
+}
+
+// BuildRules creates the validators for a list of rules, in order. It fails
+// on unknown rule names, repeated rules and invalid parameters.
+func BuildRules(ruleConfigs []RuleConfig) ([]domain.PasswordValidator, error) {
+	if len(ruleConfigs) == 0 {
+		return nil, errors.New("policy must declare at least one rule")
+	}
+
+	seen := make(map[string]bool)
+	validators := make([]domain.PasswordValidator, 0, len(ruleConfigs))
+
+	for i, rule := range ruleConfigs {
+		factory, ok := registry[rule.Name]
+		if !ok {
+			return nil, fmt.Errorf("rules[%d]: unknown rule %q", i, rule.Name)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("rules[%d]: rule %q is declared more than once", i, rule.Name)
+		}
+		seen[rule.Name] = true
+
+		// Disabled rules are not built, so a disabled blocklist does not
+		// load its file.
+		if !rule.IsEnabled() {
+			continue
+		}
+
+		p := newParams(rule.Params)
+		validator, err := factory(p)
+		if err == nil {
+			err = p.checkUnused()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d] (%s): %w", i, rule.Name, err)
+		}
+
+		validators = append(validators, validator)
+	}
+
+	if len(validators) == 0 {
+		return nil, errors.New("policy must enable at least one rule")
+	}
+	return validators, nil
+}
+
+// NewService builds every policy in the document and returns a service
+// serving them.
+func (d *Document) NewService() (*application.PasswordService, error) {
+	policies, err := d.Build()
+	if err != nil {
+		return nil, err
+	}
+	return application.NewPasswordServiceWithPolicies(policies)
+}
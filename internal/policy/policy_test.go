@@ -0,0 +1,426 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    Format
+		data      string
+		wantErr   string
+		wantRules int
+		password  string
+		wantValid bool
+	}{
+		{
+			name:   "yaml policy",
+			format: FormatYAML,
+			data: `
+version: "1"
+rules:
+  - name: min_length
+    params:
+      length: 12
+  - name: digit
+`,
+			wantRules: 2,
+			password:  "abcdefghijk1",
+			wantValid: true,
+		},
+		{
+			name:      "json policy",
+			format:    FormatJSON,
+			data:      `{"rules":[{"name":"special_char","params":{"allowed":"_"}},{"name":"min_length","params":{"length":4}}]}`,
+			wantRules: 2,
+			password:  "ab_c",
+			wantValid: true,
+		},
+		{
+			name:   "disabled rule is skipped",
+			format: FormatYAML,
+			data: `
+rules:
+  - name: digit
+  - name: uppercase
+    enabled: false
+`,
+			wantRules: 1,
+			password:  "abc1",
+			wantValid: true,
+		},
+		{
+			name:    "unknown rule",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: max_length\n",
+			wantErr: `unknown rule "max_length"`,
+		},
+		{
+			name:    "unknown parameter",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: min_length\n    params:\n      lenght: 9\n",
+			wantErr: "unknown parameter(s): lenght",
+		},
+		{
+			name:    "parameter with wrong type",
+			format:  FormatJSON,
+			data:    `{"rules":[{"name":"min_length","params":{"length":"nine"}}]}`,
+			wantErr: `parameter "length" must be an integer`,
+		},
+		{
+			name:    "non integral number",
+			format:  FormatJSON,
+			data:    `{"rules":[{"name":"min_length","params":{"length":8.5}}]}`,
+			wantErr: `parameter "length" must be an integer`,
+		},
+		{
+			name:    "invalid length",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: min_length\n    params:\n      length: 0\n",
+			wantErr: "must be greater than zero",
+		},
+		{
+			name:    "empty special char set",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: special_char\n    params:\n      allowed: \"\"\n",
+			wantErr: "must not be empty",
+		},
+		{
+			name:   "min strength rule",
+			format: FormatYAML,
+			data: `
+rules:
+  - name: min_strength
+    params:
+      score: 2
+`,
+			wantRules: 1,
+			password:  "AbTp9!fok",
+			wantValid: true,
+		},
+		{
+			name:    "min strength out of range",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: min_strength\n    params:\n      score: 5\n",
+			wantErr: "must be between 0 and 4",
+		},
+		{
+			name:   "breached rule with directory source",
+			format: FormatYAML,
+			data: `
+rules:
+  - name: breached
+    params:
+      source: directory
+      path: .
+      timeout: 500ms
+      failOpen: false
+      cacheTTL: 10m
+      cacheSize: 100
+`,
+			wantRules: 1,
+			password:  "AbTp9!fok",
+			wantValid: true,
+		},
+		{
+			name:    "breached rule with unknown source",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: breached\n    params:\n      source: ldap\n",
+			wantErr: `must be "api" or "directory"`,
+		},
+		{
+			name:    "breached rule with missing directory",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: breached\n    params:\n      source: directory\n      path: /does/not/exist\n",
+			wantErr: "breach corpus directory",
+		},
+		{
+			name:    "breached rule with bad timeout",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: breached\n    params:\n      timeout: soon\n",
+			wantErr: `parameter "timeout" must be a positive duration`,
+		},
+		{
+			name:    "breached rule with non boolean failOpen",
+			format:  FormatJSON,
+			data:    `{"rules":[{"name":"breached","params":{"failOpen":"yes"}}]}`,
+			wantErr: `parameter "failOpen" must be a boolean`,
+		},
+		{
+			name:    "repeated rule",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: digit\n  - name: digit\n",
+			wantErr: "declared more than once",
+		},
+		{
+			name:    "unknown top level field",
+			format:  FormatYAML,
+			data:    "rule:\n  - name: digit\n",
+			wantErr: "field rule not found",
+		},
+		{
+			name:    "unknown json field",
+			format:  FormatJSON,
+			data:    `{"rules":[{"name":"digit","param":{}}]}`,
+			wantErr: `unknown field "param"`,
+		},
+		{
+			name:    "empty policy",
+			format:  FormatYAML,
+			data:    "",
+			wantErr: "at least one rule",
+		},
+		{
+			name:    "all rules disabled",
+			format:  FormatYAML,
+			data:    "rules:\n  - name: digit\n    enabled: false\n",
+			wantErr: "enable at least one rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.data), tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			policies, err := doc.Build()
+			if err != nil {
+				t.Fatalf("Build() unexpected error: %v", err)
+			}
+			validators := policies[application.DefaultPolicy]
+			if len(validators) != tt.wantRules {
+				t.Errorf("Build() got %d validators, want %d", len(validators), tt.wantRules)
+			}
+
+			result := application.NewPasswordService(validators).Validate(tt.password)
+			if result.IsValid != tt.wantValid {
+				t.Errorf("Validate(%q) IsValid = %v, want %v. Errors: %v", tt.password, result.IsValid, tt.wantValid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestBuild_PreservesOrder(t *testing.T) {
+	doc, err := Parse([]byte("rules:\n  - name: uppercase\n  - name: min_length\n    params: {length: 5}\n  - name: digit\n"), FormatYAML)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	service, _ := doc.NewService()
+	result := service.Validate("a")
+
+	want := []string{"uppercase", "min_length", "digit"}
+	if len(result.Violations) != len(want) {
+		t.Fatalf("got %d violations, want %d", len(result.Violations), len(want))
+	}
+	for i, rule := range want {
+		if result.Violations[i].Rule != rule {
+			t.Errorf("violation[%d].Rule = %s, want %s", i, result.Violations[i].Rule, rule)
+		}
+	}
+}
+
+func TestNamedPolicies(t *testing.T) {
+	data := `
+policies:
+  default:
+    rules:
+      - name: min_length
+        params: {length: 4}
+  admin:
+    description: Stricter rules for the admin portal
+    rules:
+      - name: min_length
+        params: {length: 12}
+      - name: digit
+`
+	doc, err := Parse([]byte(data), FormatYAML)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	service, err := doc.NewService()
+	if err != nil {
+		t.Fatalf("NewService() unexpected error: %v", err)
+	}
+
+	if got := service.Policies(); len(got) != 2 || got[0] != "admin" || got[1] != "default" {
+		t.Errorf("Policies() = %v, want [admin default]", got)
+	}
+
+	if !service.Validate("abcd").IsValid {
+		t.Error("default policy rejected abcd")
+	}
+
+	result, err := service.ValidatePolicy("admin", "abcd")
+	if err != nil {
+		t.Fatalf("ValidatePolicy() unexpected error: %v", err)
+	}
+	if result.IsValid || len(result.Violations) != 2 || result.Policy != "admin" {
+		t.Errorf("ValidatePolicy(admin) = %+v, want 2 violations", result)
+	}
+}
+
+func TestNamedPolicies_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "missing default policy",
+			data:    "policies:\n  admin:\n    rules:\n      - name: digit\n",
+			wantErr: `a policy named "default" is required`,
+		},
+		{
+			name:    "rules and policies together",
+			data:    "rules:\n  - name: digit\npolicies:\n  default:\n    rules:\n      - name: digit\n",
+			wantErr: "either rules or policies",
+		},
+		{
+			name:    "invalid policy name",
+			data:    "policies:\n  default:\n    rules:\n      - name: digit\n  Admin Portal:\n    rules:\n      - name: digit\n",
+			wantErr: `invalid policy name "Admin Portal"`,
+		},
+		{
+			name:    "error names the policy",
+			data:    "policies:\n  default:\n    rules:\n      - name: digit\n  pin:\n    rules:\n      - name: digits_only\n",
+			wantErr: `policies.pin: rules[0]: unknown rule "digits_only"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), FormatYAML)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	service, err := Default().NewService()
+	if err != nil {
+		t.Fatalf("Default().NewService() unexpected error: %v", err)
+	}
+
+	if !service.Validate("AbTp9!fok").IsValid {
+		t.Error("default policy rejected AbTp9!fok")
+	}
+	if service.Validate("AbTp9!foo").IsValid {
+		t.Error("default policy accepted AbTp9!foo")
+	}
+}
+
+func TestBlocklistRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "common.txt")
+	os.WriteFile(path, []byte("123456\npassword\nAbTp9!foo\n"), 0o600)
+
+	tests := []struct {
+		name      string
+		params    string
+		password  string
+		wantValid bool
+		wantErr   string
+	}{
+		{name: "listed password", params: "{path: " + path + "}", password: "password", wantValid: false},
+		{name: "unlisted password", params: "{path: " + path + ", falsePositiveRate: 0.01}", password: "AbTp9!fok", wantValid: true},
+		{name: "missing path", params: "{format: plain}", wantErr: `parameter "path" is required`},
+		{name: "unknown format", params: "{path: " + path + ", format: csv}", wantErr: `must be "plain" or "sha1"`},
+		{name: "bad rate", params: "{path: " + path + ", falsePositiveRate: 2}", wantErr: `"falsePositiveRate" must be between 0 and 1`},
+		{name: "missing file", params: "{path: " + path + ".gz}", wantErr: "opening blocklist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte("rules:\n  - name: blocklist\n    params: "+tt.params+"\n"), FormatYAML)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			service, _ := doc.NewService()
+			if result := service.Validate(tt.password); result.IsValid != tt.wantValid {
+				t.Errorf("Validate(%q) IsValid = %v, want %v", tt.password, result.IsValid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestBuildRules_SkipsDisabledRules(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	data := "rules:\n  - name: digit\n  - name: blocklist\n    enabled: false\n    params: {path: " + missing + "}\n"
+
+	doc, err := Parse([]byte(data), FormatYAML)
+	if err != nil {
+		t.Fatalf("Parse() error = %v; a disabled blocklist must not load its file", err)
+	}
+	policies, err := doc.Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+	if got := len(policies[application.DefaultPolicy]); got != 1 {
+		t.Errorf("built %d rules, want 1", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "policy.yml")
+	os.WriteFile(yamlPath, []byte("version: v2\nrules:\n  - name: digit\n"), 0o600)
+
+	doc, err := Load(yamlPath)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if doc.Version != "v2" || len(doc.Rules) != 1 {
+		t.Errorf("Load() = %+v", doc)
+	}
+
+	if _, err := Load(filepath.Join(dir, "policy.toml")); err != ErrUnsupportedFormat {
+		t.Errorf("Load() error = %v, want ErrUnsupportedFormat", err)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Load() expected error for missing file")
+	}
+}
+
+func TestLoad_ExampleConfigs(t *testing.T) {
+	doc, err := Load("../../configs/policy.yaml")
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if len(doc.Rules) != len(Default().Rules) {
+		t.Errorf("example config has %d rules, want %d", len(doc.Rules), len(Default().Rules))
+	}
+
+	doc, err = Load("../../configs/policies.yaml")
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if _, ok := doc.Policies["admin"]; !ok {
+		t.Error("example multi-policy config has no admin policy")
+	}
+}
@@ -0,0 +1,188 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/breach"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// Factory builds a validator from the parameters declared in a policy.
+type Factory func(p *Params) (domain.PasswordValidator, error)
+
+var registry = map[string]Factory{
+	rules.RuleMinLength: func(p *Params) (domain.PasswordValidator, error) {
+		length, err := p.Int("length", DefaultMinLength)
+		if err != nil {
+			return nil, err
+		}
+		if length < 1 {
+			return nil, fmt.Errorf("parameter %q must be greater than zero", "length")
+		}
+		return rules.NewMinLengthValidator(length), nil
+	},
+	rules.RuleDigit: func(p *Params) (domain.PasswordValidator, error) {
+		return rules.NewDigitValidator(), nil
+	},
+	rules.RuleLowercase: func(p *Params) (domain.PasswordValidator, error) {
+		return rules.NewLowercaseValidator(), nil
+	},
+	rules.RuleUppercase: func(p *Params) (domain.PasswordValidator, error) {
+		return rules.NewUppercaseValidator(), nil
+	},
+	rules.RuleSpecialChar: func(p *Params) (domain.PasswordValidator, error) {
+		allowed, err := p.String("allowed", DefaultAllowedSpecialChars)
+		if err != nil {
+			return nil, err
+		}
+		if allowed == "" {
+			return nil, fmt.Errorf("parameter %q must not be empty", "allowed")
+		}
+		return rules.NewSpecialCharValidator(allowed), nil
+	},
+	rules.RuleNoDuplicates: func(p *Params) (domain.PasswordValidator, error) {
+		return rules.NewNoDuplicatesValidator(), nil
+	},
+	rules.RuleMinStrength: func(p *Params) (domain.PasswordValidator, error) {
+		score, err := p.Int("score", DefaultMinStrength)
+		if err != nil {
+			return nil, err
+		}
+		if score < strength.MinScore || score > strength.MaxScore {
+			return nil, fmt.Errorf("parameter %q must be between %d and %d", "score", strength.MinScore, strength.MaxScore)
+		}
+		return rules.NewMinStrengthValidator(score), nil
+	},
+	rules.RulePersonalInfo: func(p *Params) (domain.PasswordValidator, error) {
+		minTokenLength, err := p.Int("minTokenLength", rules.DefaultMinPersonalTokenLength)
+		if err != nil {
+			return nil, err
+		}
+		if minTokenLength < 1 {
+			return nil, fmt.Errorf("parameter %q must be greater than zero", "minTokenLength")
+		}
+		return rules.NewPersonalInfoValidator(minTokenLength), nil
+	},
+	rules.RuleSimilarity: func(p *Params) (domain.PasswordValidator, error) {
+		minDistance, err := p.Int("minDistance", rules.DefaultMinEditDistance)
+		if err != nil {
+			return nil, err
+		}
+		if minDistance < 1 {
+			return nil, fmt.Errorf("parameter %q must be greater than zero", "minDistance")
+		}
+		return rules.NewSimilarityValidator(minDistance), nil
+	},
+	rules.RuleBreached:  newBreachedValidator,
+	rules.RuleBlocklist: newBlocklistValidator,
+}
+
+func newBlocklistValidator(p *Params) (domain.PasswordValidator, error) {
+	path, err := p.String("path", "")
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, fmt.Errorf("parameter %q is required", "path")
+	}
+	format, err := p.String("format", string(rules.BlocklistPlain))
+	if err != nil {
+		return nil, err
+	}
+	if format != string(rules.BlocklistPlain) && format != string(rules.BlocklistSHA1) {
+		return nil, fmt.Errorf("parameter %q must be \"plain\" or \"sha1\", got %q", "format", format)
+	}
+	fpRate, err := p.Float("falsePositiveRate", rules.DefaultBlocklistFalsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		return nil, fmt.Errorf("parameter %q must be between 0 and 1, got %v", "falsePositiveRate", fpRate)
+	}
+
+	start := time.Now()
+	validator, err := rules.LoadBlocklistValidator(path, rules.BlocklistFormat(format), fpRate)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RecordBlocklistLoad(path, validator.Entries(), validator.SizeBytes(), time.Since(start))
+	return validator, nil
+}
+
+func newBreachedValidator(p *Params) (domain.PasswordValidator, error) {
+	kind, err := p.String("source", "api")
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := p.Duration("timeout", rules.DefaultBreachLookupTimeout)
+	if err != nil {
+		return nil, err
+	}
+	failOpen, err := p.Bool("failOpen", true)
+	if err != nil {
+		return nil, err
+	}
+	cacheTTL, err := p.Duration("cacheTTL", DefaultBreachCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	cacheSize, err := p.Int("cacheSize", DefaultBreachCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	if cacheSize < 0 {
+		return nil, fmt.Errorf("parameter %q must not be negative", "cacheSize")
+	}
+
+	var source rules.BreachRangeSource
+	switch kind {
+	case "api":
+		url, err := p.String("url", breach.DefaultAPIURL)
+		if err != nil {
+			return nil, err
+		}
+		source = breach.NewHTTPSource(url, timeout)
+	case "directory":
+		path, err := p.String("path", "")
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			return nil, fmt.Errorf("parameter %q is required for the directory source", "path")
+		}
+		if source, err = breach.NewDirectorySource(path); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("parameter %q must be \"api\" or \"directory\", got %q", "source", kind)
+	}
+
+	if cacheTTL > 0 && cacheSize > 0 {
+		source = breach.NewCachedSource(source, cacheTTL, cacheSize)
+	}
+	return rules.NewBreachedValidator(source, timeout, failOpen), nil
+}
+
+// Register makes a custom rule available to policy documents. It panics if
+// the name is already taken, mirroring database/sql.Register.
+func Register(name string, factory Factory) {
+	if _, exists := registry[name]; exists {
+		panic("policy: rule " + name + " is already registered")
+	}
+	registry[name] = factory
+}
+
+// RuleNames lists every rule that can be referenced from a policy document.
+func RuleNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
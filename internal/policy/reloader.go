@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// Reloader re-reads a policy file and swaps the policies served by a
+// PasswordService. Invalid files are logged and ignored, so the previously
+// loaded policies stay active.
+type Reloader struct {
+	path    string
+	service *application.PasswordService
+	hash    string
+}
+
+// NewReloader creates a reloader for a service that was built from the file
+// at path; hash is the Document.Hash of that initial load.
+func NewReloader(path string, service *application.PasswordService, hash string) *Reloader {
+	return &Reloader{
+		path:    path,
+		service: service,
+		hash:    hash,
+	}
+}
+
+// Reload reads the policy file and, if its content changed, builds and
+// applies it. Unchanged files are not parsed, so rules that load files are
+// not rebuilt on every check. It reports whether a new policy was applied.
+func (r *Reloader) Reload() (bool, error) {
+	data, format, err := readFile(r.path)
+	if err != nil {
+		metrics.PolicyReloadsTotal.WithLabelValues("failure").Inc()
+		return false, err
+	}
+	if contentHash(data) == r.hash {
+		return false, nil
+	}
+
+	doc, err := parseFile(r.path, data, format)
+	var policies map[string][]domain.PasswordValidator
+	if err == nil {
+		policies, err = doc.Build()
+	}
+	if err == nil {
+		err = r.service.SetPolicies(policies)
+	}
+	if err != nil {
+		metrics.PolicyReloadsTotal.WithLabelValues("failure").Inc()
+		return false, err
+	}
+
+	r.hash = doc.Hash
+	metrics.PolicyReloadsTotal.WithLabelValues("success").Inc()
+	metrics.SetPolicyInfo(doc.Version, doc.Hash)
+	slog.Info("Reloaded password policy", "version", doc.Version, "hash", doc.Hash, "path", r.path)
+	return true, nil
+}
+
+// Run reloads the policy whenever a value arrives on signals (typically
+// SIGHUP) and, if interval is positive, whenever the file content changes.
+// It returns when ctx is cancelled.
+func (r *Reloader) Run(ctx context.Context, signals <-chan os.Signal, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		case <-tick:
+		}
+
+		if _, err := r.Reload(); err != nil {
+			slog.Warn("Rejected password policy reload, keeping current policy", "error", err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+)
+
+func writePolicy(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("writing policy file: %v", err)
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, path, "rules:\n  - name: min_length\n    params: {length: 4}\n")
+
+	doc, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	service, _ := doc.NewService()
+	reloader := NewReloader(path, service, doc.Hash)
+
+	if changed, err := reloader.Reload(); changed || err != nil {
+		t.Errorf("Reload() of unchanged file = %v, %v; want false, nil", changed, err)
+	}
+
+	writePolicy(t, path, "rules:\n  - name: min_length\n    params: {length: 8}\n")
+	if changed, err := reloader.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() = %v, %v; want true, nil", changed, err)
+	}
+	if service.Validate("abcde").IsValid {
+		t.Error("reloaded policy was not applied")
+	}
+
+	writePolicy(t, path, "rules:\n  - name: min_length\n    params: {length: -1}\n")
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("Reload() expected error for invalid policy")
+	}
+	if !service.Validate("abcdefgh").IsValid || service.Validate("abcde").IsValid {
+		t.Error("previous policy was not kept after an invalid reload")
+	}
+
+	writePolicy(t, path, "policies:\n  admin:\n    rules:\n      - name: digit\n")
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("Reload() expected error for policy without default")
+	}
+}
+
+func TestReloader_BuildsOnlyChangedFiles(t *testing.T) {
+	builds := 0
+	registry["counted"] = func(p *Params) (domain.PasswordValidator, error) {
+		builds++
+		return rules.NewDigitValidator(), nil
+	}
+	t.Cleanup(func() { delete(registry, "counted") })
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, path, "rules:\n  - name: counted\n")
+	doc, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	service, _ := doc.NewService()
+	if builds != 1 {
+		t.Errorf("Load() and NewService() built the rules %d times, want 1", builds)
+	}
+
+	reloader := NewReloader(path, service, doc.Hash)
+	for range 3 {
+		reloader.Reload()
+	}
+	if builds != 1 {
+		t.Errorf("Reload() of an unchanged file built the rules again (%d builds)", builds)
+	}
+
+	writePolicy(t, path, "version: v2\nrules:\n  - name: counted\n")
+	if changed, err := reloader.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() = %v, %v; want true, nil", changed, err)
+	}
+	if builds != 2 {
+		t.Errorf("Reload() of a changed file built the rules %d times, want once more", builds-1)
+	}
+}
+
+func TestReloader_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, path, "rules:\n  - name: digit\n")
+
+	doc, _ := Load(path)
+	service, _ := doc.NewService()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		NewReloader(path, service, doc.Hash).Run(ctx, signals, 0)
+		close(done)
+	}()
+
+	writePolicy(t, path, "rules:\n  - name: uppercase\n")
+	signals <- os.Interrupt
+	signals <- os.Interrupt // the second send returns once the first reload finished
+
+	if service.Validate("abc1").IsValid {
+		t.Error("policy was not reloaded on signal")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancellation")
+	}
+}
+
+func TestReloader_RunWatchesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy(t, path, `{"rules":[{"name":"digit"}]}`)
+
+	doc, _ := Load(path)
+	service, _ := doc.NewService()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewReloader(path, service, doc.Hash).Run(ctx, nil, 10*time.Millisecond)
+
+	writePolicy(t, path, `{"rules":[{"name":"uppercase"}]}`)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for service.Validate("abc1").IsValid {
+		if time.Now().After(deadline) {
+			t.Fatal("policy file change was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
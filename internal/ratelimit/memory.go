@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxKeys is the number of buckets kept by a MemoryStore by default,
+// about 10 MB of memory.
+const DefaultMaxKeys = 100_000
+
+// MemoryStore keeps buckets in memory, for a single instance. It forgets
+// buckets that have refilled, which behave like new ones, and above
+// maxKeys it evicts the least recently used, so that requests from many
+// addresses cannot exhaust memory. An evicted bucket starts full again.
+type MemoryStore struct {
+	mu      sync.Mutex
+	maxKeys int
+	entries map[string]*list.Element
+	lru     *list.List // of *memoryEntry, most recently used first
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	key    string
+	limit  Limit
+	bucket bucket
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	if maxKeys < 1 {
+		maxKeys = DefaultMaxKeys
+	}
+	return &MemoryStore{
+		maxKeys: maxKeys,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, n int) (Decision, error) {
+	if limit.Unlimited() {
+		return Decision{Allowed: true, Limit: limit}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	element, ok := s.entries[key]
+	if ok {
+		s.lru.MoveToFront(element)
+	} else {
+		s.evict(now)
+		element = s.lru.PushFront(&memoryEntry{key: key, bucket: bucket{tokens: float64(limit.Burst), last: now}})
+		s.entries[key] = element
+	}
+
+	entry := element.Value.(*memoryEntry)
+	entry.limit = limit
+	return entry.bucket.take(limit, now, n), nil
+}
+
+// evict makes room for a new bucket: it drops the buckets that have
+// refilled from the least recently used end and, if the store is still
+// full, the least recently used bucket.
+func (s *MemoryStore) evict(now time.Time) {
+	for element := s.lru.Back(); element != nil; {
+		entry := element.Value.(*memoryEntry)
+		if !entry.bucket.full(entry.limit, now) {
+			break
+		}
+		prev := element.Prev()
+		s.remove(element)
+		element = prev
+	}
+	if s.lru.Len() >= s.maxKeys {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *MemoryStore) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*memoryEntry).key)
+}
+
+// Len returns the number of buckets held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
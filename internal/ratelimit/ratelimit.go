@@ -0,0 +1,233 @@
+// Package ratelimit implements token bucket rate limits. A bucket holds up
+// to Burst tokens and refills at Rate tokens per second; each request takes
+// one token and is rejected when the bucket is empty. Requests that carry
+// several items, such as a batch of passwords, also draw one token per item
+// from a separate Budget.
+//
+// Buckets live in a Store, keyed by whatever is being limited (a client, an
+// IP address). MemoryStore keeps them in the process; a store shared by
+// several instances only needs to implement Store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is the rate and burst of a bucket. The zero Limit is unlimited.
+type Limit struct {
+	// Rate is the number of tokens added per second.
+	Rate float64
+
+	// Burst is the capacity of the bucket: how many requests can be made
+	// at once after a quiet period.
+	Burst int
+}
+
+// Unlimited reports whether l lets every request through.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// String formats l as ParseLimit expects it.
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "unlimited"
+	}
+	return strconv.FormatFloat(l.Rate, 'f', -1, 64) + "/s:" + strconv.Itoa(l.Burst)
+}
+
+// ParseLimit parses a limit written as RATE/UNIT:BURST, where UNIT is s, m
+// or h (e.g. "10/s:20" or "600/m:50"), or "unlimited". Without a burst, it
+// is the number of requests allowed per unit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "unlimited" {
+		return Limit{}, nil
+	}
+
+	spec, burst, hasBurst := strings.Cut(s, ":")
+	count, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: want RATE/UNIT[:BURST], e.g. 10/s:20", s)
+	}
+
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil || n <= 0 || math.IsInf(n, 0) {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: rate must be a positive number", s)
+	}
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid rate limit %q: unit must be s, m or h", s)
+	}
+
+	limit := Limit{Rate: n / per.Seconds(), Burst: int(math.Ceil(n))}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst < 1 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", s)
+		}
+	}
+	return limit, nil
+}
+
+// Tiers maps tier names to their limits.
+type Tiers map[string]Limit
+
+// DefaultTier is the tier used for clients whose tier has no limit of its own.
+const DefaultTier = "default"
+
+// ParseTiers parses a comma-separated list of NAME=LIMIT pairs, e.g.
+// "default=10/s:20,partner=100/s:200,internal=unlimited".
+func ParseTiers(s string) (Tiers, error) {
+	tiers := Tiers{}
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid rate limit tier %q: want NAME=LIMIT", part)
+		}
+		if _, dup := tiers[name]; dup {
+			return nil, fmt.Errorf("rate limit tier %q is defined twice", name)
+		}
+		limit, err := ParseLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("tier %s: %w", name, err)
+		}
+		tiers[name] = limit
+	}
+	return tiers, nil
+}
+
+// Limit returns the limit of tier, falling back to DefaultTier. Tiers
+// without a limit, default included, are unlimited.
+func (t Tiers) Limit(tier string) Limit {
+	if limit, ok := t[tier]; ok {
+		return limit
+	}
+	return t[DefaultTier]
+}
+
+// Decision is the outcome of taking tokens from a bucket.
+type Decision struct {
+	Allowed bool
+	Limit   Limit
+
+	// Remaining is the number of tokens left in the bucket.
+	Remaining int
+
+	// RetryAfter is how long until the tokens asked for are available;
+	// zero when Allowed.
+	RetryAfter time.Duration
+
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store holds the buckets. Implementations must be safe for concurrent use
+// and take tokens atomically.
+type Store interface {
+	// Take takes n tokens from the bucket of key, creating a full bucket
+	// for keys it has not seen. It takes none when fewer than n are left.
+	// Callers let the request through when it returns an error, e.g.
+	// because a shared store is unreachable.
+	Take(ctx context.Context, key string, limit Limit, n int) (Decision, error)
+}
+
+// bucket is the state of a token bucket at a point in time.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills b up to now and takes n tokens if there are that many.
+func (b *bucket) take(limit Limit, now time.Time, n int) Decision {
+	burst := float64(limit.Burst)
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.Rate)
+	}
+	b.last = now
+
+	d := Decision{Limit: limit}
+	if b.tokens >= float64(n) {
+		b.tokens -= float64(n)
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((float64(n) - b.tokens) / limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = seconds((burst - b.tokens) / limit.Rate)
+	return d
+}
+
+// full reports whether b has refilled by now, so that forgetting it makes
+// no difference.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*limit.Rate >= float64(limit.Burst)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// AddressKey returns the bucket key of a client IP address. IPv6 clients
+// are keyed by their /64 prefix: a host is usually given a whole /64, and
+// could otherwise rotate through addresses to get a full bucket each time,
+// evicting the buckets of other clients on the way.
+func AddressKey(address string) string {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "ip:" + address
+	}
+	addr = addr.Unmap().WithZone("")
+	if addr.Is6() {
+		prefix, _ := addr.Prefix(64)
+		return "ip:" + prefix.String()
+	}
+	return "ip:" + addr.String()
+}
+
+// Budget is the bucket a request draws from for each item it carries, such
+// as the passwords of a batch. The middleware that knows who sent the
+// request puts it in the request context, and the handler that knows how
+// many items there are takes them.
+type Budget struct {
+	Store Store
+	Key   string
+	Limit Limit
+}
+
+// Take takes n tokens from the bucket of b.
+func (b Budget) Take(ctx context.Context, n int) (Decision, error) {
+	if b.Limit.Unlimited() {
+		return Decision{Allowed: true, Limit: b.Limit}, nil
+	}
+	return b.Store.Take(ctx, b.Key, b.Limit, n)
+}
+
+type budgetKey struct{}
+
+// NewContext returns a copy of ctx that carries budget.
+func NewContext(ctx context.Context, budget Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, budget)
+}
+
+// FromContext returns the budget stored in ctx by NewContext.
+func FromContext(ctx context.Context) (Budget, bool) {
+	budget, ok := ctx.Value(budgetKey{}).(Budget)
+	return budget, ok
+}
@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "10/s:20", want: Limit{Rate: 10, Burst: 20}},
+		{in: "10/s", want: Limit{Rate: 10, Burst: 10}},
+		{in: "600/m:50", want: Limit{Rate: 10, Burst: 50}},
+		{in: "36/h", want: Limit{Rate: 0.01, Burst: 36}},
+		{in: "0.5/s", want: Limit{Rate: 0.5, Burst: 1}},
+		{in: "unlimited", want: Limit{}},
+		{in: "10", wantErr: true},
+		{in: "10/d", wantErr: true},
+		{in: "-1/s", wantErr: true},
+		{in: "10/s:0", wantErr: true},
+		{in: "10/s:x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v, want %+v (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseTiers(t *testing.T) {
+	tiers, err := ParseTiers("default=10/s:20, partner=100/s:200,internal=unlimited")
+	if err != nil {
+		t.Fatalf("ParseTiers() error: %v", err)
+	}
+	if got := tiers.Limit("partner"); got != (Limit{Rate: 100, Burst: 200}) {
+		t.Errorf("Limit(partner) = %+v", got)
+	}
+	if got := tiers.Limit("internal"); !got.Unlimited() {
+		t.Errorf("Limit(internal) = %+v, want unlimited", got)
+	}
+	if got := tiers.Limit("unknown"); got != (Limit{Rate: 10, Burst: 20}) {
+		t.Errorf("Limit(unknown) = %+v, want the default tier", got)
+	}
+	if got := (Tiers{}).Limit("unknown"); !got.Unlimited() {
+		t.Errorf("Limit() without a default tier = %+v, want unlimited", got)
+	}
+
+	for _, in := range []string{"default", "=10/s", "default=10/x", "a=1/s,a=2/s"} {
+		if _, err := ParseTiers(in); err == nil {
+			t.Errorf("ParseTiers(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func newTestStore(maxKeys int) (*MemoryStore, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(maxKeys)
+	store.now = func() time.Time { return now }
+	return store, &now
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store, now := newTestStore(10)
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Burst: 3}
+
+	// The burst is available at once.
+	for i := 2; i >= 0; i-- {
+		d, _ := store.Take(ctx, "a", limit, 1)
+		if !d.Allowed || d.Remaining != i {
+			t.Fatalf("Take() = %+v, want allowed with %d remaining", d, i)
+		}
+	}
+
+	d, _ := store.Take(ctx, "a", limit, 1)
+	if d.Allowed || d.RetryAfter != 500*time.Millisecond || d.Reset != 1500*time.Millisecond {
+		t.Fatalf("Take() on an empty bucket = %+v, want a retry after 500ms", d)
+	}
+
+	// Other keys have their own bucket.
+	if d, _ := store.Take(ctx, "b", limit, 1); !d.Allowed {
+		t.Errorf("Take(b) = %+v, want allowed", d)
+	}
+
+	// Tokens come back at the rate of the limit.
+	*now = now.Add(500 * time.Millisecond)
+	if d, _ := store.Take(ctx, "a", limit, 1); !d.Allowed || d.Remaining != 0 {
+		t.Errorf("Take() after 500ms = %+v, want one more request", d)
+	}
+	*now = now.Add(time.Hour)
+	if d, _ := store.Take(ctx, "a", limit, 1); !d.Allowed || d.Remaining != 2 {
+		t.Errorf("Take() after an hour = %+v, want a full bucket", d)
+	}
+
+	if d, _ := store.Take(ctx, "a", Limit{}, 1); !d.Allowed {
+		t.Errorf("Take() with no limit = %+v, want allowed", d)
+	}
+}
+
+func TestMemoryStore_TakeMany(t *testing.T) {
+	store, _ := newTestStore(10)
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Burst: 5}
+
+	if d, _ := store.Take(ctx, "a", limit, 4); !d.Allowed || d.Remaining != 1 {
+		t.Fatalf("Take(4) = %+v, want allowed with 1 remaining", d)
+	}
+	// Nothing is taken when there are not enough tokens.
+	if d, _ := store.Take(ctx, "a", limit, 3); d.Allowed || d.Remaining != 1 || d.RetryAfter != time.Second {
+		t.Errorf("Take(3) = %+v, want a retry after 1s with 1 remaining", d)
+	}
+	if d, _ := store.Take(ctx, "a", limit, 1); !d.Allowed {
+		t.Errorf("Take(1) = %+v, want allowed", d)
+	}
+}
+
+func TestAddressKey(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"203.0.113.7", "ip:203.0.113.7"},
+		{"::ffff:203.0.113.7", "ip:203.0.113.7"},
+		{"2001:db8:1:2:3:4:5:6", "ip:2001:db8:1:2::/64"},
+		{"2001:db8:1:2::ffff", "ip:2001:db8:1:2::/64"},
+		{"fe80::1%eth0", "ip:fe80::/64"},
+		{"not-an-ip", "ip:not-an-ip"},
+	}
+	for _, tt := range tests {
+		if got := AddressKey(tt.address); got != tt.want {
+			t.Errorf("AddressKey(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	store, _ := newTestStore(10)
+	ctx := context.Background()
+	if _, ok := FromContext(ctx); ok {
+		t.Fatal("FromContext() found a budget in an empty context")
+	}
+
+	ctx = NewContext(ctx, Budget{Store: store, Key: "items:a", Limit: Limit{Rate: 1, Burst: 2}})
+	budget, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("FromContext() found no budget")
+	}
+	if d, _ := budget.Take(ctx, 2); !d.Allowed {
+		t.Errorf("Take(2) = %+v, want allowed", d)
+	}
+	if d, _ := budget.Take(ctx, 1); d.Allowed {
+		t.Errorf("Take(1) on an empty budget = %+v, want rejected", d)
+	}
+	if d, _ := (Budget{Store: store}).Take(ctx, 1000); !d.Allowed {
+		t.Errorf("Take() on an unlimited budget = %+v, want allowed", d)
+	}
+}
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	store, now := newTestStore(3)
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := range 3 {
+		store.Take(ctx, strconv.Itoa(i), limit, 1)
+	}
+	store.Take(ctx, "0", limit, 1) // "0" is now the most recently used and empty.
+
+	// At capacity, the least recently used bucket makes room.
+	store.Take(ctx, "3", limit, 1)
+	if store.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", store.Len())
+	}
+	if d, _ := store.Take(ctx, "0", limit, 1); d.Allowed {
+		t.Errorf("Take(0) = %+v, want the recently used bucket to be kept", d)
+	}
+
+	// Buckets that have refilled are forgotten when a new key arrives.
+	*now = now.Add(time.Minute)
+	store.Take(ctx, "4", limit, 1)
+	if store.Len() != 1 {
+		t.Errorf("Len() = %d, want only the new bucket", store.Len())
+	}
+}
@@ -0,0 +1,289 @@
+// Package tlsconfig builds the TLS configuration shared by the HTTP and gRPC
+// servers: a certificate that is reloaded when its files change, the minimum
+// protocol version and cipher suites, and the optional verification of client
+// certificates (mutual TLS).
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/pkg/metrics"
+)
+
+// ClientAuth selects whether clients must present a certificate.
+type ClientAuth int
+
+const (
+	// NoClientCert does not ask clients for a certificate.
+	NoClientCert ClientAuth = iota
+
+	// OptionalClientCert asks for a certificate and verifies it when the
+	// client sends one, so clients may still authenticate by API key.
+	OptionalClientCert
+
+	// RequireClientCert rejects connections without a valid certificate.
+	RequireClientCert
+)
+
+var clientAuthNames = []string{"none", "optional", "require"}
+
+func (a ClientAuth) String() string {
+	return clientAuthNames[a]
+}
+
+// ParseClientAuth parses "none", "optional" or "require".
+func ParseClientAuth(s string) (ClientAuth, error) {
+	i := slices.Index(clientAuthNames, strings.ToLower(strings.TrimSpace(s)))
+	if i < 0 {
+		return NoClientCert, fmt.Errorf("client authentication must be \"none\", \"optional\" or \"require\", got %q", s)
+	}
+	return ClientAuth(i), nil
+}
+
+// ParseVersion parses a minimum TLS version, "1.2" or "1.3". Older versions
+// are not accepted.
+func ParseVersion(s string) (uint16, error) {
+	switch strings.TrimSpace(s) {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("TLS version must be 1.2 or 1.3, got %q", s)
+}
+
+// ParseCipherSuites parses a comma-separated list of TLS 1.2 cipher suite
+// names, such as TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. Suites Go
+// considers insecure are rejected, and so are TLS 1.3 suites, which cannot
+// be configured. An empty list returns nil, which keeps Go's defaults.
+func ParseCipherSuites(s string) ([]uint16, error) {
+	var ids []uint16
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		i := slices.IndexFunc(tls.CipherSuites(), func(suite *tls.CipherSuite) bool { return suite.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", name)
+		}
+		suite := tls.CipherSuites()[i]
+		if !slices.Contains(suite.SupportedVersions, tls.VersionTLS12) {
+			return nil, fmt.Errorf("cipher suite %s is TLS 1.3 only; TLS 1.3 suites are always enabled", name)
+		}
+		ids = append(ids, suite.ID)
+	}
+	return ids, nil
+}
+
+// Options are the settings of a server TLS configuration.
+type Options struct {
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile lists the CAs, in PEM, that client certificates must
+	// chain to. It is required unless ClientAuth is NoClientCert.
+	ClientCAFile string
+	ClientAuth   ClientAuth
+
+	// MinVersion is tls.VersionTLS12 when zero.
+	MinVersion uint16
+
+	// CipherSuites restricts the TLS 1.2 cipher suites; nil keeps Go's
+	// defaults.
+	CipherSuites []uint16
+}
+
+// Certificates holds the server certificate and the trusted client CAs, and
+// reloads them from their files. Connections always use the last files that
+// loaded successfully, so a rotation that is only half written is retried
+// instead of breaking the server.
+type Certificates struct {
+	opts Options
+
+	cert      atomic.Pointer[tls.Certificate]
+	clientCAs atomic.Pointer[x509.CertPool]
+
+	mu   sync.Mutex
+	hash [sha256.Size]byte
+}
+
+// Load validates opts and loads the certificate files.
+func Load(opts Options) (*Certificates, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("both a certificate and a key file are required")
+	}
+	if opts.ClientAuth != NoClientCert && opts.ClientCAFile == "" {
+		return nil, fmt.Errorf("client authentication %q requires a client CA file", opts.ClientAuth)
+	}
+	if opts.ClientAuth == NoClientCert && opts.ClientCAFile != "" {
+		return nil, errors.New("a client CA file is set but client authentication is \"none\"")
+	}
+	if opts.MinVersion == 0 {
+		opts.MinVersion = tls.VersionTLS12
+	}
+	if opts.MinVersion == tls.VersionTLS13 && len(opts.CipherSuites) > 0 {
+		return nil, errors.New("cipher suites only apply to TLS 1.2, but the minimum version is 1.3")
+	}
+
+	c := &Certificates{opts: opts}
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the certificate files and, if their content changed, starts
+// using them for new connections. It reports whether new files were
+// applied.
+func (c *Certificates) Reload() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	certPEM, keyPEM, caPEM, err := c.readFiles()
+	if err != nil {
+		metrics.TLSReloadsTotal.WithLabelValues("failure").Inc()
+		return false, err
+	}
+
+	hash := sha256.Sum256(bytes.Join([][]byte{certPEM, keyPEM, caPEM}, []byte{0}))
+	if hash == c.hash {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		metrics.TLSReloadsTotal.WithLabelValues("failure").Inc()
+		return false, fmt.Errorf("%s, %s: %w", c.opts.CertFile, c.opts.KeyFile, err)
+	}
+
+	var pool *x509.CertPool
+	if caPEM != nil {
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			metrics.TLSReloadsTotal.WithLabelValues("failure").Inc()
+			return false, fmt.Errorf("%s: no PEM certificates found", c.opts.ClientCAFile)
+		}
+	}
+
+	c.cert.Store(&cert)
+	c.clientCAs.Store(pool)
+	c.hash = hash
+	metrics.TLSReloadsTotal.WithLabelValues("success").Inc()
+	metrics.TLSCertificateExpiry.Set(float64(cert.Leaf.NotAfter.Unix()))
+	return true, nil
+}
+
+func (c *Certificates) readFiles() (certPEM, keyPEM, caPEM []byte, err error) {
+	if certPEM, err = os.ReadFile(c.opts.CertFile); err != nil {
+		return nil, nil, nil, fmt.Errorf("reading certificate: %w", err)
+	}
+	if keyPEM, err = os.ReadFile(c.opts.KeyFile); err != nil {
+		return nil, nil, nil, fmt.Errorf("reading key: %w", err)
+	}
+	if c.opts.ClientCAFile != "" {
+		if caPEM, err = os.ReadFile(c.opts.ClientCAFile); err != nil {
+			return nil, nil, nil, fmt.Errorf("reading client CAs: %w", err)
+		}
+	}
+	return certPEM, keyPEM, caPEM, nil
+}
+
+// Options returns the settings the certificates were loaded with.
+func (c *Certificates) Options() Options {
+	return c.opts
+}
+
+// Leaf returns the server certificate currently in use.
+func (c *Certificates) Leaf() *x509.Certificate {
+	return c.cert.Load().Leaf
+}
+
+// Run reloads the files whenever a value arrives on signals (typically
+// SIGHUP) and, if interval is positive, whenever their content changes. It
+// returns when ctx is cancelled.
+func (c *Certificates) Run(ctx context.Context, signals <-chan os.Signal, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		case <-tick:
+		}
+
+		reloaded, err := c.Reload()
+		if err != nil {
+			slog.Warn("Rejected TLS certificate reload, keeping current certificate", "error", err)
+		} else if reloaded {
+			slog.Info("Reloaded TLS certificate", "path", c.opts.CertFile, "expires", c.Leaf().NotAfter)
+		}
+	}
+}
+
+// Config returns a server configuration that always uses the current
+// certificate and client CAs.
+//
+// Client certificates are verified by the configuration itself rather than
+// by crypto/tls, so that reloaded CAs apply without a restart; the
+// ConnectionState of a connection therefore has no VerifiedChains, but its
+// PeerCertificates have been verified whenever they are present.
+func (c *Certificates) Config() *tls.Config {
+	config := &tls.Config{
+		MinVersion:   c.opts.MinVersion,
+		CipherSuites: c.opts.CipherSuites,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return c.cert.Load(), nil
+		},
+	}
+
+	switch c.opts.ClientAuth {
+	case OptionalClientCert:
+		config.ClientAuth = tls.RequestClientCert
+	case RequireClientCert:
+		config.ClientAuth = tls.RequireAnyClientCert
+	}
+	if c.opts.ClientAuth != NoClientCert {
+		config.VerifyConnection = c.verifyClient
+	}
+	return config
+}
+
+// verifyClient verifies the certificate chain sent by a client against the
+// current client CAs.
+func (c *Certificates) verifyClient(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         c.clientCAs.Load(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("client certificate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA issues certificates for the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a certificate and key, in PEM, for a server (localhost) or
+// a client named cn.
+func (ca *testCA) issue(t *testing.T, serial int64, cn string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if usage == x509.ExtKeyUsageServerAuth {
+		template.DNSNames = []string{"localhost"}
+		template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if v, err := ParseVersion("1.3"); err != nil || v != tls.VersionTLS13 {
+		t.Errorf("ParseVersion(1.3) = %v, %v", v, err)
+	}
+	if _, err := ParseVersion("1.1"); err == nil {
+		t.Error("ParseVersion(1.1) succeeded, want an error")
+	}
+
+	suites, err := ParseCipherSuites("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256")
+	if err != nil || len(suites) != 2 || suites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("ParseCipherSuites() = %v, %v", suites, err)
+	}
+	if suites, err := ParseCipherSuites(""); err != nil || suites != nil {
+		t.Errorf("ParseCipherSuites(\"\") = %v, %v, want nil", suites, err)
+	}
+	for _, name := range []string{"TLS_RSA_WITH_RC4_128_SHA", "TLS_AES_128_GCM_SHA256", "TLS_FAKE"} {
+		if _, err := ParseCipherSuites(name); err == nil {
+			t.Errorf("ParseCipherSuites(%s) succeeded, want an error", name)
+		}
+	}
+
+	if a, err := ParseClientAuth("Require"); err != nil || a != RequireClientCert {
+		t.Errorf("ParseClientAuth(Require) = %v, %v", a, err)
+	}
+	if _, err := ParseClientAuth("always"); err == nil {
+		t.Error("ParseClientAuth(always) succeeded, want an error")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, 2, "server", x509.ExtKeyUsageServerAuth)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, caFile, []byte("not a certificate"))
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"missing key", Options{CertFile: certFile}, "both a certificate and a key file"},
+		{"client auth without CAs", Options{CertFile: certFile, KeyFile: keyFile, ClientAuth: RequireClientCert}, "requires a client CA file"},
+		{"CAs without client auth", Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}, "client authentication is \"none\""},
+		{"cipher suites with TLS 1.3", Options{CertFile: certFile, KeyFile: keyFile, MinVersion: tls.VersionTLS13, CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}}, "only apply to TLS 1.2"},
+		{"not a certificate", Options{CertFile: caFile, KeyFile: keyFile}, "ca.crt"},
+		{"missing file", Options{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile}, "reading certificate"},
+		{"invalid CAs", Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: OptionalClientCert}, "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	certPEM, keyPEM := ca.issue(t, 2, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	certs, err := Load(Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if reloaded, err := certs.Reload(); reloaded || err != nil {
+		t.Errorf("Reload() of unchanged files = %v, %v, want false, nil", reloaded, err)
+	}
+
+	// A rotation caught halfway, with the new certificate but the old key,
+	// keeps the current certificate.
+	rotatedCert, rotatedKey := ca.issue(t, 3, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, rotatedCert)
+	if _, err := certs.Reload(); err == nil {
+		t.Error("Reload() with a mismatched key succeeded, want an error")
+	}
+	if serial := certs.Leaf().SerialNumber.Int64(); serial != 2 {
+		t.Errorf("serial after a failed reload = %d, want 2", serial)
+	}
+
+	writeFile(t, keyFile, rotatedKey)
+	if reloaded, err := certs.Reload(); !reloaded || err != nil {
+		t.Fatalf("Reload() = %v, %v, want true, nil", reloaded, err)
+	}
+	if serial := certs.Leaf().SerialNumber.Int64(); serial != 3 {
+		t.Errorf("serial after reload = %d, want 3", serial)
+	}
+
+	// New connections get the reloaded certificate.
+	got, err := certs.Config().GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil || got.Leaf.SerialNumber.Int64() != 3 {
+		t.Errorf("GetCertificate() serial = %v, %v, want 3", got.Leaf.SerialNumber, err)
+	}
+}
+
+func TestConfig_ClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	certPEM, keyPEM := ca.issue(t, 2, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	writeFile(t, caFile, ca.pem)
+
+	clientPEM, clientKeyPEM := ca.issue(t, 3, "billing-service", x509.ExtKeyUsageClientAuth)
+	trusted, err := tls.X509KeyPair(clientPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA := newTestCA(t)
+	otherPEM, otherKeyPEM := otherCA.issue(t, 4, "intruder", x509.ExtKeyUsageClientAuth)
+	untrusted, err := tls.X509KeyPair(otherPEM, otherKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(url string, cert *tls.Certificate) (string, error) {
+		config := &tls.Config{RootCAs: roots}
+		if cert != nil {
+			config.Certificates = []tls.Certificate{*cert}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		resp, err := client.Get(url)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		return resp.Header.Get("X-Client"), nil
+	}
+
+	tests := []struct {
+		name       string
+		clientAuth ClientAuth
+		cert       *tls.Certificate
+		want       string
+		wantErr    bool
+	}{
+		{"required and sent", RequireClientCert, &trusted, "billing-service", false},
+		{"required and missing", RequireClientCert, nil, "", true},
+		{"required and untrusted", RequireClientCert, &untrusted, "", true},
+		{"optional and missing", OptionalClientCert, nil, "", false},
+		{"optional and sent", OptionalClientCert, &trusted, "billing-service", false},
+		{"optional and untrusted", OptionalClientCert, &untrusted, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certs, err := Load(Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: tt.clientAuth})
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := &http.Server{
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if len(r.TLS.PeerCertificates) > 0 {
+						w.Header().Set("X-Client", r.TLS.PeerCertificates[0].Subject.CommonName)
+					}
+				}),
+				TLSConfig: certs.Config(),
+				ErrorLog:  log.New(io.Discard, "", 0),
+			}
+			go server.ServeTLS(listener, "", "")
+			defer server.Close()
+
+			got, err := get("https://"+listener.Addr().String(), tt.cert)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("client = %q, %v, want %q (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
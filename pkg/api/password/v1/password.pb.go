@@ -0,0 +1,984 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: password/v1/password.proto
+
+package passwordv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// UserInfo describes the account a password is for. All fields are optional
+// and are used by the personal_info rule and by the strength estimate.
+type UserInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	CompanyName   string                 `protobuf:"bytes,5,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfo) Reset() {
+	*x = UserInfo{}
+	mi := &file_password_v1_password_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfo) ProtoMessage() {}
+
+func (x *UserInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
+func (*UserInfo) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UserInfo) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserInfo) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserInfo) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *UserInfo) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *UserInfo) GetCompanyName() string {
+	if x != nil {
+		return x.CompanyName
+	}
+	return ""
+}
+
+type ValidatePasswordRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Password string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	// Policy name; empty selects "default".
+	Policy string `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	// Subject (user or account) changing its password. When password history
+	// is enabled, its recent passwords are rejected.
+	SubjectId string    `protobuf:"bytes,3,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	User      *UserInfo `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	// Language of the violation messages: pt-BR, en or es. Without it, the
+	// "accept-language" metadata is used, and then English.
+	Locale        string `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidatePasswordRequest) Reset() {
+	*x = ValidatePasswordRequest{}
+	mi := &file_password_v1_password_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidatePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatePasswordRequest) ProtoMessage() {}
+
+func (x *ValidatePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidatePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ValidatePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidatePasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ValidatePasswordRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *ValidatePasswordRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *ValidatePasswordRequest) GetUser() *UserInfo {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ValidatePasswordRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ValidatePasswordChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldPassword   string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	Policy        string                 `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	SubjectId     string                 `protobuf:"bytes,4,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	User          *UserInfo              `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	Locale        string                 `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidatePasswordChangeRequest) Reset() {
+	*x = ValidatePasswordChangeRequest{}
+	mi := &file_password_v1_password_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidatePasswordChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatePasswordChangeRequest) ProtoMessage() {}
+
+func (x *ValidatePasswordChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidatePasswordChangeRequest.ProtoReflect.Descriptor instead.
+func (*ValidatePasswordChangeRequest) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidatePasswordChangeRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ValidatePasswordChangeRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+func (x *ValidatePasswordChangeRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *ValidatePasswordChangeRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *ValidatePasswordChangeRequest) GetUser() *UserInfo {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ValidatePasswordChangeRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ValidatePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsValid       bool                   `protobuf:"varint,1,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	Errors        []string               `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Violations    []*Violation           `protobuf:"bytes,4,rep,name=violations,proto3" json:"violations,omitempty"`
+	Strength      *Strength              `protobuf:"bytes,5,opt,name=strength,proto3" json:"strength,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidatePasswordResponse) Reset() {
+	*x = ValidatePasswordResponse{}
+	mi := &file_password_v1_password_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidatePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatePasswordResponse) ProtoMessage() {}
+
+func (x *ValidatePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidatePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ValidatePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidatePasswordResponse) GetIsValid() bool {
+	if x != nil {
+		return x.IsValid
+	}
+	return false
+}
+
+func (x *ValidatePasswordResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *ValidatePasswordResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ValidatePasswordResponse) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *ValidatePasswordResponse) GetStrength() *Strength {
+	if x != nil {
+		return x.Strength
+	}
+	return nil
+}
+
+// Violation is the machine-readable form of a validation error. Clients
+// should match on code, which is stable across releases, rather than on
+// message.
+type Violation struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Rule    string                 `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	Message string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Params  *structpb.Struct       `protobuf:"bytes,4,opt,name=params,proto3" json:"params,omitempty"`
+	Char    string                 `protobuf:"bytes,5,opt,name=char,proto3" json:"char,omitempty"`
+	// Position of char in the password, in characters; unset when the
+	// violation is not about a single character.
+	Position      *int32 `protobuf:"varint,6,opt,name=position,proto3,oneof" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_password_v1_password_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Violation) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Violation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Violation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Violation) GetParams() *structpb.Struct {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Violation) GetChar() string {
+	if x != nil {
+		return x.Char
+	}
+	return ""
+}
+
+func (x *Violation) GetPosition() int32 {
+	if x != nil && x.Position != nil {
+		return *x.Position
+	}
+	return 0
+}
+
+// Strength is the estimated resistance of the password to guessing. Score
+// goes from 0 (too guessable) to 4 (very unguessable).
+type Strength struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Score             int32                  `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+	Guesses           float64                `protobuf:"fixed64,2,opt,name=guesses,proto3" json:"guesses,omitempty"`
+	GuessesLog10      float64                `protobuf:"fixed64,3,opt,name=guesses_log10,json=guessesLog10,proto3" json:"guesses_log10,omitempty"`
+	CrackTimesSeconds *CrackTimesSeconds     `protobuf:"bytes,4,opt,name=crack_times_seconds,json=crackTimesSeconds,proto3" json:"crack_times_seconds,omitempty"`
+	CrackTimeDisplay  string                 `protobuf:"bytes,5,opt,name=crack_time_display,json=crackTimeDisplay,proto3" json:"crack_time_display,omitempty"`
+	Warning           string                 `protobuf:"bytes,6,opt,name=warning,proto3" json:"warning,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Strength) Reset() {
+	*x = Strength{}
+	mi := &file_password_v1_password_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Strength) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Strength) ProtoMessage() {}
+
+func (x *Strength) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Strength.ProtoReflect.Descriptor instead.
+func (*Strength) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Strength) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Strength) GetGuesses() float64 {
+	if x != nil {
+		return x.Guesses
+	}
+	return 0
+}
+
+func (x *Strength) GetGuessesLog10() float64 {
+	if x != nil {
+		return x.GuessesLog10
+	}
+	return 0
+}
+
+func (x *Strength) GetCrackTimesSeconds() *CrackTimesSeconds {
+	if x != nil {
+		return x.CrackTimesSeconds
+	}
+	return nil
+}
+
+func (x *Strength) GetCrackTimeDisplay() string {
+	if x != nil {
+		return x.CrackTimeDisplay
+	}
+	return ""
+}
+
+func (x *Strength) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+type CrackTimesSeconds struct {
+	state                            protoimpl.MessageState `protogen:"open.v1"`
+	OnlineThrottling_100PerHour      float64                `protobuf:"fixed64,1,opt,name=online_throttling_100_per_hour,json=onlineThrottling100PerHour,proto3" json:"online_throttling_100_per_hour,omitempty"`
+	OnlineNoThrottling_10PerSecond   float64                `protobuf:"fixed64,2,opt,name=online_no_throttling_10_per_second,json=onlineNoThrottling10PerSecond,proto3" json:"online_no_throttling_10_per_second,omitempty"`
+	OfflineSlowHashing_1E4PerSecond  float64                `protobuf:"fixed64,3,opt,name=offline_slow_hashing_1e4_per_second,json=offlineSlowHashing1e4PerSecond,proto3" json:"offline_slow_hashing_1e4_per_second,omitempty"`
+	OfflineFastHashing_1E10PerSecond float64                `protobuf:"fixed64,4,opt,name=offline_fast_hashing_1e10_per_second,json=offlineFastHashing1e10PerSecond,proto3" json:"offline_fast_hashing_1e10_per_second,omitempty"`
+	unknownFields                    protoimpl.UnknownFields
+	sizeCache                        protoimpl.SizeCache
+}
+
+func (x *CrackTimesSeconds) Reset() {
+	*x = CrackTimesSeconds{}
+	mi := &file_password_v1_password_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrackTimesSeconds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrackTimesSeconds) ProtoMessage() {}
+
+func (x *CrackTimesSeconds) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrackTimesSeconds.ProtoReflect.Descriptor instead.
+func (*CrackTimesSeconds) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CrackTimesSeconds) GetOnlineThrottling_100PerHour() float64 {
+	if x != nil {
+		return x.OnlineThrottling_100PerHour
+	}
+	return 0
+}
+
+func (x *CrackTimesSeconds) GetOnlineNoThrottling_10PerSecond() float64 {
+	if x != nil {
+		return x.OnlineNoThrottling_10PerSecond
+	}
+	return 0
+}
+
+func (x *CrackTimesSeconds) GetOfflineSlowHashing_1E4PerSecond() float64 {
+	if x != nil {
+		return x.OfflineSlowHashing_1E4PerSecond
+	}
+	return 0
+}
+
+func (x *CrackTimesSeconds) GetOfflineFastHashing_1E10PerSecond() float64 {
+	if x != nil {
+		return x.OfflineFastHashing_1E10PerSecond
+	}
+	return 0
+}
+
+type GetPolicyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Policy name; empty selects "default".
+	Policy        string `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicyRequest) Reset() {
+	*x = GetPolicyRequest{}
+	mi := &file_password_v1_password_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicyRequest) ProtoMessage() {}
+
+func (x *GetPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPolicyRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+type GetPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	Rules         []*PolicyRule          `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicyResponse) Reset() {
+	*x = GetPolicyResponse{}
+	mi := &file_password_v1_password_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicyResponse) ProtoMessage() {}
+
+func (x *GetPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicyResponse.ProtoReflect.Descriptor instead.
+func (*GetPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPolicyResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *GetPolicyResponse) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// PolicyRule describes one active rule. Codes lists the violation codes the
+// rule can produce and params uses the same keys as Violation.params.
+type PolicyRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Codes         []string               `protobuf:"bytes,2,rep,name=codes,proto3" json:"codes,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Params        *structpb.Struct       `protobuf:"bytes,4,opt,name=params,proto3" json:"params,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRule) Reset() {
+	*x = PolicyRule{}
+	mi := &file_password_v1_password_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRule) ProtoMessage() {}
+
+func (x *PolicyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRule.ProtoReflect.Descriptor instead.
+func (*PolicyRule) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PolicyRule) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *PolicyRule) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetParams() *structpb.Struct {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type ListPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesRequest) Reset() {
+	*x = ListPoliciesRequest{}
+	mi := &file_password_v1_password_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesRequest) ProtoMessage() {}
+
+func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{10}
+}
+
+type ListPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []string               `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesResponse) Reset() {
+	*x = ListPoliciesResponse{}
+	mi := &file_password_v1_password_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesResponse) ProtoMessage() {}
+
+func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_password_v1_password_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_password_v1_password_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListPoliciesResponse) GetPolicies() []string {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+var File_password_v1_password_proto protoreflect.FileDescriptor
+
+const file_password_v1_password_proto_rawDesc = "" +
+	"\n" +
+	"\x1apassword/v1/password.proto\x12\vpassword.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x9b\x01\n" +
+	"\bUserInfo\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12!\n" +
+	"\fcompany_name\x18\x05 \x01(\tR\vcompanyName\"\xaf\x01\n" +
+	"\x17ValidatePasswordRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x03 \x01(\tR\tsubjectId\x12)\n" +
+	"\x04user\x18\x04 \x01(\v2\x15.password.v1.UserInfoR\x04user\x12\x16\n" +
+	"\x06locale\x18\x05 \x01(\tR\x06locale\"\xdf\x01\n" +
+	"\x1dValidatePasswordChangeRequest\x12!\n" +
+	"\fold_password\x18\x01 \x01(\tR\voldPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\x12\x16\n" +
+	"\x06policy\x18\x03 \x01(\tR\x06policy\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x04 \x01(\tR\tsubjectId\x12)\n" +
+	"\x04user\x18\x05 \x01(\v2\x15.password.v1.UserInfoR\x04user\x12\x16\n" +
+	"\x06locale\x18\x06 \x01(\tR\x06locale\"\xd0\x01\n" +
+	"\x18ValidatePasswordResponse\x12\x19\n" +
+	"\bis_valid\x18\x01 \x01(\bR\aisValid\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\x12\x16\n" +
+	"\x06errors\x18\x03 \x03(\tR\x06errors\x126\n" +
+	"\n" +
+	"violations\x18\x04 \x03(\v2\x16.password.v1.ViolationR\n" +
+	"violations\x121\n" +
+	"\bstrength\x18\x05 \x01(\v2\x15.password.v1.StrengthR\bstrength\"\xc0\x01\n" +
+	"\tViolation\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12/\n" +
+	"\x06params\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06params\x12\x12\n" +
+	"\x04char\x18\x05 \x01(\tR\x04char\x12\x1f\n" +
+	"\bposition\x18\x06 \x01(\x05H\x00R\bposition\x88\x01\x01B\v\n" +
+	"\t_position\"\xf7\x01\n" +
+	"\bStrength\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x05R\x05score\x12\x18\n" +
+	"\aguesses\x18\x02 \x01(\x01R\aguesses\x12#\n" +
+	"\rguesses_log10\x18\x03 \x01(\x01R\fguessesLog10\x12N\n" +
+	"\x13crack_times_seconds\x18\x04 \x01(\v2\x1e.password.v1.CrackTimesSecondsR\x11crackTimesSeconds\x12,\n" +
+	"\x12crack_time_display\x18\x05 \x01(\tR\x10crackTimeDisplay\x12\x18\n" +
+	"\awarning\x18\x06 \x01(\tR\awarning\"\xbe\x02\n" +
+	"\x11CrackTimesSeconds\x12B\n" +
+	"\x1eonline_throttling_100_per_hour\x18\x01 \x01(\x01R\x1aonlineThrottling100PerHour\x12I\n" +
+	"\"online_no_throttling_10_per_second\x18\x02 \x01(\x01R\x1donlineNoThrottling10PerSecond\x12K\n" +
+	"#offline_slow_hashing_1e4_per_second\x18\x03 \x01(\x01R\x1eofflineSlowHashing1e4PerSecond\x12M\n" +
+	"$offline_fast_hashing_1e10_per_second\x18\x04 \x01(\x01R\x1fofflineFastHashing1e10PerSecond\"*\n" +
+	"\x10GetPolicyRequest\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\"Z\n" +
+	"\x11GetPolicyResponse\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12-\n" +
+	"\x05rules\x18\x02 \x03(\v2\x17.password.v1.PolicyRuleR\x05rules\"\x89\x01\n" +
+	"\n" +
+	"PolicyRule\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x14\n" +
+	"\x05codes\x18\x02 \x03(\tR\x05codes\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12/\n" +
+	"\x06params\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06params\"\x15\n" +
+	"\x13ListPoliciesRequest\"2\n" +
+	"\x14ListPoliciesResponse\x12\x1a\n" +
+	"\bpolicies\x18\x01 \x03(\tR\bpolicies2\x80\x03\n" +
+	"\x0fPasswordService\x12_\n" +
+	"\x10ValidatePassword\x12$.password.v1.ValidatePasswordRequest\x1a%.password.v1.ValidatePasswordResponse\x12k\n" +
+	"\x16ValidatePasswordChange\x12*.password.v1.ValidatePasswordChangeRequest\x1a%.password.v1.ValidatePasswordResponse\x12J\n" +
+	"\tGetPolicy\x12\x1d.password.v1.GetPolicyRequest\x1a\x1e.password.v1.GetPolicyResponse\x12S\n" +
+	"\fListPolicies\x12 .password.v1.ListPoliciesRequest\x1a!.password.v1.ListPoliciesResponseBNZLgithub.com/willherrera/itau-backend-challenge/pkg/api/password/v1;passwordv1b\x06proto3"
+
+var (
+	file_password_v1_password_proto_rawDescOnce sync.Once
+	file_password_v1_password_proto_rawDescData []byte
+)
+
+func file_password_v1_password_proto_rawDescGZIP() []byte {
+	file_password_v1_password_proto_rawDescOnce.Do(func() {
+		file_password_v1_password_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_password_v1_password_proto_rawDesc), len(file_password_v1_password_proto_rawDesc)))
+	})
+	return file_password_v1_password_proto_rawDescData
+}
+
+var file_password_v1_password_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_password_v1_password_proto_goTypes = []any{
+	(*UserInfo)(nil),                      // 0: password.v1.UserInfo
+	(*ValidatePasswordRequest)(nil),       // 1: password.v1.ValidatePasswordRequest
+	(*ValidatePasswordChangeRequest)(nil), // 2: password.v1.ValidatePasswordChangeRequest
+	(*ValidatePasswordResponse)(nil),      // 3: password.v1.ValidatePasswordResponse
+	(*Violation)(nil),                     // 4: password.v1.Violation
+	(*Strength)(nil),                      // 5: password.v1.Strength
+	(*CrackTimesSeconds)(nil),             // 6: password.v1.CrackTimesSeconds
+	(*GetPolicyRequest)(nil),              // 7: password.v1.GetPolicyRequest
+	(*GetPolicyResponse)(nil),             // 8: password.v1.GetPolicyResponse
+	(*PolicyRule)(nil),                    // 9: password.v1.PolicyRule
+	(*ListPoliciesRequest)(nil),           // 10: password.v1.ListPoliciesRequest
+	(*ListPoliciesResponse)(nil),          // 11: password.v1.ListPoliciesResponse
+	(*structpb.Struct)(nil),               // 12: google.protobuf.Struct
+}
+var file_password_v1_password_proto_depIdxs = []int32{
+	0,  // 0: password.v1.ValidatePasswordRequest.user:type_name -> password.v1.UserInfo
+	0,  // 1: password.v1.ValidatePasswordChangeRequest.user:type_name -> password.v1.UserInfo
+	4,  // 2: password.v1.ValidatePasswordResponse.violations:type_name -> password.v1.Violation
+	5,  // 3: password.v1.ValidatePasswordResponse.strength:type_name -> password.v1.Strength
+	12, // 4: password.v1.Violation.params:type_name -> google.protobuf.Struct
+	6,  // 5: password.v1.Strength.crack_times_seconds:type_name -> password.v1.CrackTimesSeconds
+	9,  // 6: password.v1.GetPolicyResponse.rules:type_name -> password.v1.PolicyRule
+	12, // 7: password.v1.PolicyRule.params:type_name -> google.protobuf.Struct
+	1,  // 8: password.v1.PasswordService.ValidatePassword:input_type -> password.v1.ValidatePasswordRequest
+	2,  // 9: password.v1.PasswordService.ValidatePasswordChange:input_type -> password.v1.ValidatePasswordChangeRequest
+	7,  // 10: password.v1.PasswordService.GetPolicy:input_type -> password.v1.GetPolicyRequest
+	10, // 11: password.v1.PasswordService.ListPolicies:input_type -> password.v1.ListPoliciesRequest
+	3,  // 12: password.v1.PasswordService.ValidatePassword:output_type -> password.v1.ValidatePasswordResponse
+	3,  // 13: password.v1.PasswordService.ValidatePasswordChange:output_type -> password.v1.ValidatePasswordResponse
+	8,  // 14: password.v1.PasswordService.GetPolicy:output_type -> password.v1.GetPolicyResponse
+	11, // 15: password.v1.PasswordService.ListPolicies:output_type -> password.v1.ListPoliciesResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_password_v1_password_proto_init() }
+func file_password_v1_password_proto_init() {
+	if File_password_v1_password_proto != nil {
+		return
+	}
+	file_password_v1_password_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_password_v1_password_proto_rawDesc), len(file_password_v1_password_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_password_v1_password_proto_goTypes,
+		DependencyIndexes: file_password_v1_password_proto_depIdxs,
+		MessageInfos:      file_password_v1_password_proto_msgTypes,
+	}.Build()
+	File_password_v1_password_proto = out.File
+	file_password_v1_password_proto_goTypes = nil
+	file_password_v1_password_proto_depIdxs = nil
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ValidatePassword validates a password against req.Policy, or the default
+// policy. An invalid password is not an error: check IsValid and Violations.
+func (c *Client) ValidatePassword(ctx context.Context, req ValidatePasswordRequest) (*ValidatePasswordResponse, error) {
+	var resp ValidatePasswordResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/validate-password", body: req, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ValidatePasswordWithPolicy validates a password against the named policy,
+// which takes precedence over req.Policy.
+func (c *Client) ValidatePasswordWithPolicy(ctx context.Context, policy string, req ValidatePasswordRequest) (*ValidatePasswordResponse, error) {
+	var resp ValidatePasswordResponse
+	path := "/api/v1/policies/" + url.PathEscape(policy) + "/validate"
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: req, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ValidatePasswordChange validates a new password and rejects small
+// variations of the one it replaces.
+func (c *Client) ValidatePasswordChange(ctx context.Context, req ValidatePasswordChangeRequest) (*ValidatePasswordResponse, error) {
+	var resp ValidatePasswordResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/validate-password-change", body: req, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ValidatePasswords validates several passwords in one request. Results are
+// in the order of req.Items.
+func (c *Client) ValidatePasswords(ctx context.Context, req ValidatePasswordsRequest) (*ValidatePasswordsResponse, error) {
+	var resp ValidatePasswordsResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/validate-passwords", body: req, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GeneratePassword creates passwords that satisfy req.Policy, or the default
+// policy.
+func (c *Client) GeneratePassword(ctx context.Context, req GeneratePasswordRequest) (*GeneratePasswordResponse, error) {
+	var resp GeneratePasswordResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/generate-password", body: req, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPolicy describes the rules of the named policy; an empty name selects
+// the default policy.
+func (c *Client) GetPolicy(ctx context.Context, policy string) (*PolicyResponse, error) {
+	query := url.Values{}
+	if policy != "" {
+		query.Set("policy", policy)
+	}
+
+	var resp PolicyResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/policy", query: query, idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RecordPasswordHistory adds password to the subject's history after a
+// successful password change. It is not retried after server or network
+// errors, which could record the password twice.
+func (c *Client) RecordPasswordHistory(ctx context.Context, subject, password string) error {
+	path := "/api/v1/subjects/" + url.PathEscape(subject) + "/history"
+	return c.do(ctx, request{method: http.MethodPost, path: path, body: RecordPasswordRequest{Password: password}}, nil)
+}
+
+// Health checks that the API is up.
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	var resp HealthResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/health", idempotent: true}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,292 @@
+// Package client is a Go client for the password validator HTTP API.
+//
+// Requests and responses are copies of the server's models, kept in sync by
+// TestModelsMatchServer, so the package does not depend on server internals.
+// Every call takes a context, each attempt has its
+// own timeout, and failed attempts are retried with exponential backoff:
+// always on 429 Too Many Requests, and on 5xx responses and network errors
+// for calls that can safely be repeated.
+//
+//	c, err := client.New("http://localhost:8080")
+//	result, err := c.ValidatePassword(ctx, client.ValidatePasswordRequest{Password: "AbTp9!fok"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 2 * time.Second
+
+	// maxResponseBytes bounds the response bodies read by the client.
+	maxResponseBytes = 10 << 20
+)
+
+// Client calls the password validator HTTP API. It is safe for concurrent
+// use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+	apiKey     string
+
+	timeout    time.Duration
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to send requests, e.g. to
+// configure TLS or a proxy. Its Timeout, if any, applies to every attempt.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithTimeout sets the time limit of each attempt. Use the context to limit
+// a call as a whole, retries included. Zero disables the limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.timeout = timeout }
+}
+
+// WithRetries sets how many times a failed request is retried. Zero
+// disables retries.
+func WithRetries(maxRetries int) Option {
+	return func(c *Client) { c.maxRetries = maxRetries }
+}
+
+// WithBackoff sets the delay before the first retry, doubled on each
+// following one up to max. Delays are randomised by up to half their value
+// so that clients do not retry in lockstep.
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) { c.minBackoff, c.maxBackoff = min, max }
+}
+
+// WithAPIKey sets the API key sent with every request, for servers that
+// require one.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New returns a client for the API at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		userAgent:  "password-validator-go-client",
+		timeout:    DefaultTimeout,
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxRetries < 0 || c.minBackoff < 0 || c.maxBackoff < c.minBackoff {
+		return nil, errors.New("invalid retry settings")
+	}
+	return c, nil
+}
+
+// Error is returned for responses with an error status. Message is the
+// explanation sent by the server, when there is one.
+type Error struct {
+	StatusCode int
+	Message    string
+
+	// RetryAfter is the delay requested by the server with Retry-After, or
+	// zero.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("password validator: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("password validator: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response, returned for unknown
+// policies.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// request describes one API call.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+
+	// idempotent calls are also retried after server and network errors,
+	// which may happen after the server acted on the request.
+	idempotent bool
+}
+
+// do sends req, retrying as configured, and decodes the response into out
+// unless out is nil.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, req.method, u.String(), payload, out)
+		if err == nil || attempt >= c.maxRetries || ctx.Err() != nil || !retryable(err, req.idempotent) {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, url string, payload []byte, out any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp, data)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+func newError(resp *http.Response, data []byte) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	var body ErrorResponse
+	if json.Unmarshal(data, &body) == nil {
+		apiErr.Message = body.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			apiErr.RetryAfter = max(time.Until(date), 0)
+		}
+	}
+	return apiErr
+}
+
+// retryable reports whether a request that failed with err may succeed if
+// sent again. Rate-limited requests were not processed and are always
+// retried.
+func retryable(err error, idempotent bool) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		// Network errors and attempt timeouts.
+		return idempotent
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		return true
+	case apiErr.StatusCode == http.StatusNotImplemented:
+		return false
+	case apiErr.StatusCode >= http.StatusInternalServerError:
+		return idempotent
+	}
+	return false
+}
+
+// backoff returns the delay before retry number attempt+1.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.maxBackoff
+	if attempt < 32 && c.minBackoff<<attempt < c.maxBackoff {
+		delay = c.minBackoff << attempt
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,352 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api"
+	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	"github.com/willherrera/itau-backend-challenge/pkg/client"
+	"github.com/willherrera/itau-backend-challenge/pkg/passwordpolicy"
+)
+
+func newRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	service, err := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {
+			rules.NewMinLengthValidator(9),
+			rules.NewDigitValidator(),
+			rules.NewLowercaseValidator(),
+			rules.NewUppercaseValidator(),
+			rules.NewSpecialCharValidator("!@#$%^&*()-+"),
+			rules.NewNoDuplicatesValidator(),
+		},
+		"admin": {
+			rules.NewMinLengthValidator(14),
+			rules.NewPersonalInfoValidator(4),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.SetHistory(history.NewHistory(history.NewMemoryStore(), history.NewBcryptHasher(4), 3))
+	return api.NewRouter(handlers.NewPasswordHandler(service))
+}
+
+// newClient starts the real router behind handler wrappers and returns a
+// client with short backoffs.
+func newClient(t *testing.T, wrap func(http.Handler) http.Handler, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	handler := newRouter(t)
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]client.Option{client.WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)
+	c, err := client.New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return c
+}
+
+// failFirst answers the first n requests with status, and counts requests.
+func failFirst(n int, status int, header http.Header, calls *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if int(calls.Add(1)) <= n {
+				for k, v := range header {
+					w.Header()[k] = v
+				}
+				http.Error(w, `{"error":"failure","message":"try again"}`, status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestClient_Endpoints(t *testing.T) {
+	c := newClient(t, nil)
+	ctx := context.Background()
+
+	t.Run("validate password", func(t *testing.T) {
+		resp, err := c.ValidatePassword(ctx, client.ValidatePasswordRequest{Password: "AbTp9!foo"})
+		if err != nil {
+			t.Fatalf("ValidatePassword() error: %v", err)
+		}
+		if resp.IsValid || resp.Violations[0].Code != passwordpolicy.CodeDuplicateChar || resp.Strength == nil {
+			t.Errorf("response = %+v, want DUPLICATE_CHAR with strength", resp)
+		}
+	})
+
+	t.Run("validate with policy", func(t *testing.T) {
+		resp, err := c.ValidatePasswordWithPolicy(ctx, "admin", client.ValidatePasswordRequest{Password: "Jsilva#Kp9wQ2zX", UserDetails: client.UserDetails{Username: "jsilva"}})
+		if err != nil {
+			t.Fatalf("ValidatePasswordWithPolicy() error: %v", err)
+		}
+		if resp.Policy != "admin" || resp.Violations[0].Code != passwordpolicy.CodeContainsPersonalInfo {
+			t.Errorf("response = %+v, want CONTAINS_PERSONAL_INFO under admin", resp)
+		}
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := c.ValidatePasswordWithPolicy(ctx, "pin", client.ValidatePasswordRequest{Password: "1234"})
+		var apiErr *client.Error
+		if !errors.As(err, &apiErr) || !client.IsNotFound(err) || apiErr.Message != "Unknown policy: pin" {
+			t.Errorf("error = %v, want 404 with the server message", err)
+		}
+	})
+
+	t.Run("password change", func(t *testing.T) {
+		resp, err := c.ValidatePasswordChange(ctx, client.ValidatePasswordChangeRequest{OldPassword: "Winter#1986", NewPassword: "Winter#1987"})
+		if err != nil {
+			t.Fatalf("ValidatePasswordChange() error: %v", err)
+		}
+		if resp.IsValid {
+			t.Errorf("response = %+v, want a similarity violation", resp)
+		}
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		resp, err := c.ValidatePasswords(ctx, client.ValidatePasswordsRequest{Items: []client.ValidatePasswordItem{
+			{ID: "a", Password: "AbTp9!fok"},
+			{ID: "b", Password: "abc"},
+		}})
+		if err != nil {
+			t.Fatalf("ValidatePasswords() error: %v", err)
+		}
+		if resp.Valid != 1 || resp.Invalid != 1 || resp.Results[1].ID != "b" {
+			t.Errorf("response = %+v, want 1 valid and 1 invalid in order", resp)
+		}
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		resp, err := c.GeneratePassword(ctx, client.GeneratePasswordRequest{Count: 2})
+		if err != nil {
+			t.Fatalf("GeneratePassword() error: %v", err)
+		}
+		if len(resp.Passwords) != 2 {
+			t.Errorf("passwords = %v, want 2", resp.Passwords)
+		}
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		resp, err := c.GetPolicy(ctx, "")
+		if err != nil {
+			t.Fatalf("GetPolicy() error: %v", err)
+		}
+		// The six rules of the policy, followed by the history rule.
+		if resp.Policy != "default" || len(resp.Rules) != 7 || resp.Rules[6].Rule != passwordpolicy.RulePasswordHistory {
+			t.Errorf("response = %+v, want the 6 default rules and password history", resp)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		if err := c.RecordPasswordHistory(ctx, "user-42", "AbTp9!fok"); err != nil {
+			t.Fatalf("RecordPasswordHistory() error: %v", err)
+		}
+		resp, err := c.ValidatePassword(ctx, client.ValidatePasswordRequest{Password: "AbTp9!fok", SubjectID: "user-42"})
+		if err != nil {
+			t.Fatalf("ValidatePassword() error: %v", err)
+		}
+		if resp.IsValid {
+			t.Error("reused password is valid, want PASSWORD_REUSED")
+		}
+	})
+
+	t.Run("health", func(t *testing.T) {
+		resp, err := c.Health(ctx)
+		if err != nil {
+			t.Fatalf("Health() error: %v", err)
+		}
+		if resp.Status != "healthy" {
+			t.Errorf("status = %q, want healthy", resp.Status)
+		}
+	})
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		record    bool // call the non-idempotent RecordPasswordHistory
+		wantCalls int32
+		wantErr   int
+	}{
+		{name: "recovers from 503", failures: 2, status: http.StatusServiceUnavailable, wantCalls: 3},
+		{name: "recovers from 429", failures: 1, status: http.StatusTooManyRequests, wantCalls: 2},
+		{name: "gives up after max retries", failures: 10, status: http.StatusBadGateway, wantCalls: 4, wantErr: http.StatusBadGateway},
+		{name: "no retry on 400", failures: 1, status: http.StatusBadRequest, wantCalls: 1, wantErr: http.StatusBadRequest},
+		{name: "no retry on 501", failures: 1, status: http.StatusNotImplemented, wantCalls: 1, wantErr: http.StatusNotImplemented},
+		{name: "no retry of history on 500", failures: 1, status: http.StatusInternalServerError, record: true, wantCalls: 1, wantErr: http.StatusInternalServerError},
+		{name: "history retried on 429", failures: 1, status: http.StatusTooManyRequests, record: true, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			c := newClient(t, failFirst(tt.failures, tt.status, nil, &calls))
+
+			var err error
+			if tt.record {
+				err = c.RecordPasswordHistory(context.Background(), "user-42", "AbTp9!fok")
+			} else {
+				_, err = c.ValidatePassword(context.Background(), client.ValidatePasswordRequest{Password: "AbTp9!fok"})
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			var apiErr *client.Error
+			switch {
+			case tt.wantErr == 0 && err != nil:
+				t.Errorf("error = %v, want success", err)
+			case tt.wantErr != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantErr):
+				t.Errorf("error = %v, want status %d", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	c := newClient(t, failFirst(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}, &calls))
+
+	start := time.Now()
+	if _, err := c.Health(context.Background()); err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s of Retry-After", elapsed)
+	}
+
+	// A context that ends first stops waiting and returns the last error.
+	calls.Store(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Health(ctx)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != time.Second {
+		t.Errorf("error = %v, want 429 with Retry-After", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestClient_AttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	slowFirst := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				select {
+				case <-time.After(time.Second):
+				case <-r.Context().Done():
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	c := newClient(t, slowFirst, client.WithTimeout(50*time.Millisecond))
+
+	if _, err := c.ValidatePassword(context.Background(), client.ValidatePasswordRequest{Password: "AbTp9!fok"}); err != nil {
+		t.Fatalf("ValidatePassword() error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want the timed-out attempt to be retried", calls.Load())
+	}
+}
+
+func TestClient_APIKey(t *testing.T) {
+	var got string
+	recordKey := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("X-API-Key")
+			next.ServeHTTP(w, r)
+		})
+	}
+	c := newClient(t, recordKey, client.WithAPIKey("pwv_test-key"))
+
+	if _, err := c.Health(context.Background()); err != nil {
+		t.Fatalf("Health() error: %v", err)
+	}
+	if got != "pwv_test-key" {
+		t.Errorf("X-API-Key = %q, want the configured key", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"localhost:8080", "ftp://example.com", "://"} {
+		if _, err := client.New(baseURL); err == nil {
+			t.Errorf("New(%q) succeeded, want an error", baseURL)
+		}
+	}
+	if _, err := client.New("http://localhost:8080", client.WithRetries(-1)); err == nil {
+		t.Error("New() with negative retries succeeded, want an error")
+	}
+}
+
+// jsonFields lists the JSON keys of a struct type, with their options.
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			// Embedded structs are encoded as top-level fields.
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		fields = append(fields, field.Tag.Get("json"))
+	}
+	return fields
+}
+
+func TestModelsMatchServer(t *testing.T) {
+	pairs := []struct {
+		client, server any
+	}{
+		{client.ValidatePasswordRequest{}, models.ValidatePasswordRequest{}},
+		{client.ValidatePasswordChangeRequest{}, models.ValidatePasswordChangeRequest{}},
+		{client.RecordPasswordRequest{}, models.RecordPasswordRequest{}},
+		{client.ValidatePasswordsRequest{}, models.ValidatePasswordsRequest{}},
+		{client.ValidatePasswordItem{}, models.ValidatePasswordItem{}},
+		{client.UserDetails{}, models.UserDetails{}},
+		{client.GeneratePasswordRequest{}, models.GeneratePasswordRequest{}},
+		{client.GeneratePasswordResponse{}, models.GeneratePasswordResponse{}},
+		{client.ValidatePasswordResponse{}, models.ValidatePasswordResponse{}},
+		{client.ValidatePasswordsResponse{}, models.ValidatePasswordsResponse{}},
+		{client.ValidatePasswordResult{}, models.ValidatePasswordResult{}},
+		{client.Strength{}, models.Strength{}},
+		{client.CrackTimesSeconds{}, models.CrackTimesSeconds{}},
+		{client.Violation{}, models.Violation{}},
+		{client.PolicyResponse{}, models.PolicyResponse{}},
+		{client.PolicyRule{}, models.PolicyRule{}},
+		{client.ErrorResponse{}, models.ErrorResponse{}},
+		{client.HealthResponse{}, models.HealthResponse{}},
+	}
+	for _, p := range pairs {
+		c, s := reflect.TypeOf(p.client), reflect.TypeOf(p.server)
+		if got, want := jsonFields(c), jsonFields(s); !slices.Equal(got, want) {
+			t.Errorf("%s JSON fields = %v, want %v as in the server", c.Name(), got, want)
+		}
+	}
+}
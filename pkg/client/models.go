@@ -0,0 +1,174 @@
+package client
+
+// UserDetails are the optional details of the account a password is for,
+// used by the personal_info rule and by the strength estimate. They are sent
+// as top-level JSON fields of the requests that embed them.
+type UserDetails struct {
+	Username    string `json:"username,omitempty"`
+	Email       string `json:"email,omitempty"`
+	FirstName   string `json:"firstName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
+	CompanyName string `json:"companyName,omitempty"`
+}
+
+// ValidatePasswordRequest validates a password against Policy, or the
+// default policy.
+type ValidatePasswordRequest struct {
+	Password string `json:"password"`
+	Policy   string `json:"policy,omitempty"`
+
+	// Locale selects the language of the violation messages (pt-BR, en or
+	// es) and takes precedence over the Accept-Language header.
+	Locale string `json:"locale,omitempty"`
+
+	// SubjectID identifies the user or account changing its password. When
+	// password history is enabled, its recent passwords are rejected.
+	SubjectID string `json:"subjectId,omitempty"`
+
+	UserDetails
+}
+
+// ValidatePasswordChangeRequest validates a new password and compares it with
+// the password it replaces. The optional fields mean the same as in
+// ValidatePasswordRequest.
+type ValidatePasswordChangeRequest struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+	Policy      string `json:"policy,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	SubjectID   string `json:"subjectId,omitempty"`
+
+	UserDetails
+}
+
+// RecordPasswordRequest adds a password to a subject's history.
+type RecordPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// ValidatePasswordsRequest validates several passwords against one policy.
+type ValidatePasswordsRequest struct {
+	Policy string                 `json:"policy,omitempty"`
+	Locale string                 `json:"locale,omitempty"`
+	Items  []ValidatePasswordItem `json:"items"`
+}
+
+// ValidatePasswordItem is one password of a batch. ID is echoed back in the
+// result; the other optional fields mean the same as in
+// ValidatePasswordRequest.
+type ValidatePasswordItem struct {
+	ID        string `json:"id,omitempty"`
+	Password  string `json:"password"`
+	SubjectID string `json:"subjectId,omitempty"`
+
+	UserDetails
+}
+
+// GeneratePasswordRequest asks for passwords that satisfy a policy. All
+// fields are optional.
+type GeneratePasswordRequest struct {
+	Policy string `json:"policy,omitempty"`
+
+	// Mode is "random" (default) or "passphrase".
+	Mode string `json:"mode,omitempty"`
+
+	// Length of random passwords; defaults to 16 or the policy minimum.
+	Length int `json:"length,omitempty"`
+
+	// Words in a passphrase; defaults to 4. More are added if the policy
+	// minimum length requires it.
+	Words int `json:"words,omitempty"`
+
+	// Count of passwords to generate, from 1 (default) to 20.
+	Count int `json:"count,omitempty"`
+
+	// ExcludeAmbiguous leaves out characters that are easily confused, such
+	// as 0/O and 1/l/I.
+	ExcludeAmbiguous bool `json:"excludeAmbiguous,omitempty"`
+}
+
+type GeneratePasswordResponse struct {
+	Policy    string   `json:"policy"`
+	Passwords []string `json:"passwords"`
+}
+
+type ValidatePasswordResponse struct {
+	IsValid    bool        `json:"isValid"`
+	Policy     string      `json:"policy"`
+	Errors     []string    `json:"errors,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+	Strength   *Strength   `json:"strength,omitempty"`
+}
+
+// ValidatePasswordsResponse holds one result per item, in request order.
+type ValidatePasswordsResponse struct {
+	Policy  string                   `json:"policy"`
+	Total   int                      `json:"total"`
+	Valid   int                      `json:"valid"`
+	Invalid int                      `json:"invalid"`
+	Results []ValidatePasswordResult `json:"results"`
+}
+
+type ValidatePasswordResult struct {
+	ID         string      `json:"id,omitempty"`
+	IsValid    bool        `json:"isValid"`
+	Errors     []string    `json:"errors,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+	Strength   *Strength   `json:"strength,omitempty"`
+}
+
+// Strength is the estimated resistance of the password to guessing. Score
+// goes from 0 (too guessable) to 4 (very unguessable).
+type Strength struct {
+	Score             int               `json:"score"`
+	Guesses           float64           `json:"guesses"`
+	GuessesLog10      float64           `json:"guessesLog10"`
+	CrackTimesSeconds CrackTimesSeconds `json:"crackTimesSeconds"`
+	CrackTimeDisplay  string            `json:"crackTimeDisplay"`
+	Warning           string            `json:"warning,omitempty"`
+}
+
+type CrackTimesSeconds struct {
+	OnlineThrottling   float64 `json:"onlineThrottling100PerHour"`
+	OnlineNoThrottling float64 `json:"onlineNoThrottling10PerSecond"`
+	OfflineSlowHashing float64 `json:"offlineSlowHashing1e4PerSecond"`
+	OfflineFastHashing float64 `json:"offlineFastHashing1e10PerSecond"`
+}
+
+// Violation is the machine-readable form of a validation error. Clients
+// should match on Code, which is stable across releases, rather than on Message.
+type Violation struct {
+	Code     string         `json:"code"`
+	Rule     string         `json:"rule"`
+	Message  string         `json:"message"`
+	Params   map[string]any `json:"params,omitempty"`
+	Char     string         `json:"char,omitempty"`
+	Position *int           `json:"position,omitempty"`
+}
+
+type PolicyResponse struct {
+	Policy string       `json:"policy"`
+	Rules  []PolicyRule `json:"rules"`
+}
+
+// PolicyRule describes one active rule. Codes lists the violation codes the
+// rule can produce and Params uses the same keys as Violation.Params.
+type PolicyRule struct {
+	Rule        string         `json:"rule"`
+	Codes       []string       `json:"codes"`
+	Description string         `json:"description"`
+	Params      map[string]any `json:"params,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message,omitempty"`
+}
+
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+
+	// Checks explains why the service is not ready.
+	Checks []string `json:"checks,omitempty"`
+}
@@ -1,7 +1,7 @@
 package metrics
 
 import (
-	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -19,9 +19,9 @@ var (
 	ValidationErrorsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "password_validation_errors_total",
-			Help: "Total number of validation errors by rule",
+			Help: "Total number of validation errors by rule and violation code",
 		},
-		[]string{"rule"},
+		[]string{"rule", "code"},
 	)
 
 	RequestDuration = promauto.NewHistogram(
@@ -32,44 +32,125 @@ var (
 		},
 	)
 
+	BatchSize = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "password_validation_batch_size",
+			Help:    "Number of passwords per batch validation request",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		},
+	)
+
 	InProgress = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "password_validation_in_progress",
 			Help: "Number of password validations currently in progress",
 		},
 	)
+
+	PolicyInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "password_policy_info",
+			Help: "Currently loaded password policy; always 1, identified by the version and hash labels",
+		},
+		[]string{"version", "hash"},
+	)
+
+	BreachRangeLookupsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "password_breach_range_lookups_total",
+			Help: "Total number of breached password range lookups by result (cache_hit, cache_miss, error)",
+		},
+		[]string{"result"},
+	)
+
+	BlocklistEntries = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "password_blocklist_entries",
+			Help: "Number of entries loaded from each password blocklist file",
+		},
+		[]string{"path"},
+	)
+
+	BlocklistFilterBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "password_blocklist_filter_bytes",
+			Help: "Memory held by the filter of each password blocklist file",
+		},
+		[]string{"path"},
+	)
+
+	BlocklistLoadDuration = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "password_blocklist_load_duration_seconds",
+			Help: "Time spent on the last load of each password blocklist file",
+		},
+		[]string{"path"},
+	)
+
+	APIRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "password_api_requests_total",
+			Help: "Total number of HTTP API requests by client, route and status code",
+		},
+		[]string{"client", "route", "code"},
+	)
+
+	RateLimitRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "password_rate_limit_rejected_total",
+			Help: "Total number of requests rejected by rate limits, by scope (ip, client, items) and client",
+		},
+		[]string{"scope", "client"},
+	)
+
+	PolicyReloadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "password_policy_reloads_total",
+			Help: "Total number of password policy reload attempts",
+		},
+		[]string{"result"},
+	)
+
+	TLSReloadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "password_tls_reloads_total",
+			Help: "Total number of TLS certificate loads that applied new files or failed",
+		},
+		[]string{"result"},
+	)
+
+	TLSCertificateExpiry = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "password_tls_certificate_expiry_timestamp_seconds",
+			Help: "Expiry time of the TLS certificate in use, in seconds since the Unix epoch",
+		},
+	)
 )
 
-func RecordValidation(isValid bool, errors []string) {
+// RecordValidation counts a validated password as valid or invalid. Record
+// each rule an invalid password broke with RecordViolation.
+func RecordValidation(isValid bool) {
 	if isValid {
 		RequestsTotal.WithLabelValues("valid").Inc()
 	} else {
 		RequestsTotal.WithLabelValues("invalid").Inc()
-
-		for _, err := range errors {
-			rule := extractRuleFromError(err)
-			ValidationErrorsTotal.WithLabelValues(rule).Inc()
-		}
 	}
 }
 
-func extractRuleFromError(errMsg string) string {
-	switch {
-	case strings.Contains(errMsg, "characters"):
-		return "min_length"
-	case strings.Contains(errMsg, "digit"):
-		return "digit"
-	case strings.Contains(errMsg, "lowercase"):
-		return "lowercase"
-	case strings.Contains(errMsg, "uppercase"):
-		return "uppercase"
-	case strings.Contains(errMsg, "special character"):
-		return "special_char"
-	case strings.Contains(errMsg, "repeated"):
-		return "no_duplicates"
-	case strings.Contains(errMsg, "whitespace"):
-		return "no_whitespace"
-	default:
-		return "unknown"
-	}
+// RecordViolation counts a broken rule by its rule name and violation code.
+func RecordViolation(rule, code string) {
+	ValidationErrorsTotal.WithLabelValues(rule, code).Inc()
+}
+
+// SetPolicyInfo records the policy currently in use, replacing the previous one.
+func SetPolicyInfo(version, hash string) {
+	PolicyInfo.Reset()
+	PolicyInfo.WithLabelValues(version, hash).Set(1)
+}
+
+// RecordBlocklistLoad reports the size and load time of a blocklist file.
+func RecordBlocklistLoad(path string, entries, filterBytes int, took time.Duration) {
+	BlocklistEntries.WithLabelValues(path).Set(float64(entries))
+	BlocklistFilterBytes.WithLabelValues(path).Set(float64(filterBytes))
+	BlocklistLoadDuration.WithLabelValues(path).Set(took.Seconds())
 }
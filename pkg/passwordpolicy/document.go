@@ -0,0 +1,176 @@
+package passwordpolicy
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/policy"
+)
+
+// Document is a declarative set of policies, in the format of the server's
+// POLICY_FILE. Build a Validator from it with NewFromDocument.
+type Document struct {
+	doc *policy.Document
+}
+
+// Version is the version declared by the document, if any.
+func (d *Document) Version() string {
+	return d.doc.Version
+}
+
+// Hash identifies the exact content the document was parsed from. It is
+// empty for Default.
+func (d *Document) Hash() string {
+	return d.doc.Hash
+}
+
+// RuleConfig is a rule of a document: its registered name and parameters.
+// A rule whose Enabled is false is skipped.
+type RuleConfig struct {
+	Name    string         `json:"name" yaml:"name"`
+	Enabled *bool          `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Params  map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Format is the encoding of a policy document.
+type Format string
+
+const (
+	FormatYAML = Format(policy.FormatYAML)
+	FormatJSON = Format(policy.FormatJSON)
+)
+
+// Factory builds a rule from the parameters declared for it in a document.
+type Factory func(p *Params) (Rule, error)
+
+// Params are the parameters of a rule in a document. Reading a parameter
+// with the wrong type is an error, and so is declaring a parameter the
+// factory never reads.
+type Params struct {
+	params *policy.Params
+}
+
+// Int returns the integer parameter key, or def when it is not declared.
+func (p *Params) Int(key string, def int) (int, error) {
+	return p.params.Int(key, def)
+}
+
+// String returns the string parameter key, or def when it is not declared.
+func (p *Params) String(key string, def string) (string, error) {
+	return p.params.String(key, def)
+}
+
+// Bool returns the boolean parameter key, or def when it is not declared.
+func (p *Params) Bool(key string, def bool) (bool, error) {
+	return p.params.Bool(key, def)
+}
+
+// Float returns the numeric parameter key, or def when it is not declared.
+func (p *Params) Float(key string, def float64) (float64, error) {
+	return p.params.Float(key, def)
+}
+
+// Duration returns the duration parameter key, written like "500ms", or def
+// when it is not declared.
+func (p *Params) Duration(key string, def time.Duration) (time.Duration, error) {
+	return p.params.Duration(key, def)
+}
+
+// Default returns the built-in policy: at least 9 characters with a digit,
+// a lowercase letter, an uppercase letter and a special character, and no
+// repeated characters.
+func Default() *Document {
+	return &Document{doc: policy.Default()}
+}
+
+// Load reads a policy document, picking the format from the file extension
+// (.yaml, .yml or .json).
+func Load(path string) (*Document, error) {
+	doc, err := policy.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{doc: doc}, nil
+}
+
+// Parse decodes a policy document, rejecting unknown fields, and checks that
+// every rule can be built.
+func Parse(data []byte, format Format) (*Document, error) {
+	doc, err := policy.Parse(data, policy.Format(format))
+	if err != nil {
+		return nil, err
+	}
+	return &Document{doc: doc}, nil
+}
+
+// NewFromDocument returns a Validator serving every policy of doc.
+func NewFromDocument(doc *Document, opts ...Option) (*Validator, error) {
+	service, err := doc.doc.NewService()
+	if err != nil {
+		return nil, err
+	}
+	return newValidator(service, opts), nil
+}
+
+// Reloader re-reads a policy file and swaps the policies of a Validator.
+// Invalid files are logged and ignored, so the previously loaded policies
+// stay active.
+type Reloader struct {
+	reloader *policy.Reloader
+}
+
+// NewReloader creates a reloader for a Validator built from doc, which was
+// loaded from the file at path.
+func NewReloader(path string, v *Validator, doc *Document) *Reloader {
+	return &Reloader{reloader: policy.NewReloader(path, v.service, doc.Hash())}
+}
+
+// Reload reads the policy file and, if its content changed, builds and
+// applies it. It reports whether a new policy was applied.
+func (r *Reloader) Reload() (bool, error) {
+	return r.reloader.Reload()
+}
+
+// Run reloads the policy whenever a value arrives on signals (typically
+// SIGHUP) and, if interval is positive, whenever the file content changes.
+// It returns when ctx is cancelled.
+func (r *Reloader) Run(ctx context.Context, signals <-chan os.Signal, interval time.Duration) {
+	r.reloader.Run(ctx, signals, interval)
+}
+
+// BuildRules builds the rules of a single policy.
+func BuildRules(configs []RuleConfig) ([]Rule, error) {
+	ruleConfigs := make([]policy.RuleConfig, len(configs))
+	for i, c := range configs {
+		ruleConfigs[i] = policy.RuleConfig{Name: c.Name, Enabled: c.Enabled, Params: c.Params}
+	}
+	validators, err := policy.BuildRules(ruleConfigs)
+	if err != nil {
+		return nil, err
+	}
+	built := make([]Rule, len(validators))
+	for i, validator := range validators {
+		built[i] = fromValidator(validator)
+	}
+	return built, nil
+}
+
+// RegisterRule makes a custom rule available to policy documents under name.
+// It is meant to be called from init functions and panics if the name is
+// already taken.
+func RegisterRule(name string, factory Factory) {
+	policy.Register(name, func(p *policy.Params) (domain.PasswordValidator, error) {
+		rule, err := factory(&Params{params: p})
+		if err != nil {
+			return nil, err
+		}
+		return toValidator(rule), nil
+	})
+}
+
+// RuleNames lists every rule that documents can reference.
+func RuleNames() []string {
+	return policy.RuleNames()
+}
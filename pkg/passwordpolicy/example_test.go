@@ -0,0 +1,119 @@
+package passwordpolicy_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/willherrera/itau-backend-challenge/pkg/passwordpolicy"
+)
+
+func Example() {
+	validator := passwordpolicy.New(
+		passwordpolicy.MinLength(12),
+		passwordpolicy.Digit(),
+		passwordpolicy.Uppercase(),
+	)
+
+	result := validator.Validate("correcthorse")
+	fmt.Println(result.IsValid)
+	for _, v := range result.Violations {
+		fmt.Println(v.Code, v.Message)
+	}
+	// Output:
+	// false
+	// NO_DIGIT password must contain at least one digit
+	// NO_UPPERCASE password must contain at least one uppercase letter
+}
+
+func ExampleParse() {
+	doc, err := passwordpolicy.Parse([]byte(`
+policies:
+  default:
+    rules:
+      - name: min_length
+        params:
+          length: 9
+      - name: digit
+  pin:
+    rules:
+      - name: min_length
+        params:
+          length: 4
+`), passwordpolicy.FormatYAML)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	validator, err := passwordpolicy.NewFromDocument(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := validator.ValidatePolicy("pin", "2024")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(validator.Policies(), result.Policy, result.IsValid)
+	// Output: [default pin] pin true
+}
+
+func ExampleValidator_ValidatePolicyContext() {
+	validator := passwordpolicy.New(passwordpolicy.PersonalInfo(4))
+
+	ctx := passwordpolicy.WithUserInfo(context.Background(), passwordpolicy.UserInfo{Username: "jsilva"})
+	result, err := validator.ValidatePolicyContext(ctx, passwordpolicy.DefaultPolicy, "Jsilva#Kp9wQ2zX")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(result.Violations[0].Code)
+	// Output: CONTAINS_PERSONAL_INFO
+}
+
+// noCompanyName is a custom rule rejecting passwords that contain the
+// company name.
+type noCompanyName struct {
+	name string
+}
+
+func (r noCompanyName) Validate(password string) error {
+	if strings.Contains(strings.ToLower(password), r.name) {
+		return passwordpolicy.NewViolation("CONTAINS_COMPANY_NAME", "no_company_name", "password must not contain the company name").
+			WithParam("company", r.name)
+	}
+	return nil
+}
+
+func ExampleRule() {
+	validator := passwordpolicy.New(passwordpolicy.MinLength(9), noCompanyName{name: "acme"})
+
+	result := validator.Validate("Acme2024!")
+	fmt.Println(result.Violations[0].Code, result.Violations[0].Params["company"])
+	// Output: CONTAINS_COMPANY_NAME acme
+}
+
+func ExampleRegisterRule() {
+	passwordpolicy.RegisterRule("no_company_name", func(p *passwordpolicy.Params) (passwordpolicy.Rule, error) {
+		name, err := p.String("company", "")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("parameter %q is required", "company")
+		}
+		return noCompanyName{name: strings.ToLower(name)}, nil
+	})
+
+	doc, err := passwordpolicy.Parse([]byte(`{"rules": [{"name": "no_company_name", "params": {"company": "ACME"}}]}`), passwordpolicy.FormatJSON)
+	if err != nil {
+		log.Fatal(err)
+	}
+	validator, err := passwordpolicy.NewFromDocument(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(validator.Validate("acme-rocks").IsValid)
+	// Output: false
+}
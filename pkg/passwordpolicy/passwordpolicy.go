@@ -0,0 +1,367 @@
+// Package passwordpolicy is the public Go API of the password validator, for
+// services that want to validate passwords in process instead of calling the
+// HTTP or gRPC API. It runs the same rules as the server in cmd/api and
+// reports the same violation codes.
+//
+// A Validator holds one or more named policies, each an ordered list of
+// rules. Policies are built from rule constructors such as MinLength, or
+// from a policy Document in the same YAML/JSON format the server reads from
+// POLICY_FILE. Custom rules implement Rule and can be made available to
+// documents with RegisterRule.
+//
+// The package follows semantic versioning: exported identifiers, violation
+// codes and the policy document format only change in backwards-compatible
+// ways within a major version. Its types are its own, so the server
+// internals can change without breaking callers; values are copied field by
+// field at the package boundary.
+package passwordpolicy
+
+import (
+	"context"
+
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/strength"
+	"github.com/willherrera/itau-backend-challenge/internal/generator"
+)
+
+// DefaultPolicy is the policy applied when the caller does not name one.
+const DefaultPolicy = application.DefaultPolicy
+
+var (
+	// ErrPolicyNotFound is returned when validating against a policy the
+	// Validator does not hold.
+	ErrPolicyNotFound = application.ErrPolicyNotFound
+	// ErrNoDefaultPolicy is returned when a set of policies has none named
+	// DefaultPolicy.
+	ErrNoDefaultPolicy = application.ErrNoDefaultPolicy
+)
+
+// Validator validates passwords against a set of named policies. It is safe
+// for concurrent use.
+type Validator struct {
+	service *application.PasswordService
+}
+
+func init() {
+	// Lets cmd/api run its HTTP and gRPC servers on a Validator without the
+	// service becoming part of this package's API.
+	application.RegisterServiceOf(func(validator any) *application.PasswordService {
+		if v, ok := validator.(*Validator); ok {
+			return v.service
+		}
+		return nil
+	})
+}
+
+// Option configures a Validator.
+type Option func(*Validator)
+
+// WithHistory makes every policy also reject a password the subject in the
+// validation context (see WithSubject) used recently. Record passwords in h
+// after each successful change.
+func WithHistory(h History) Option {
+	return func(v *Validator) {
+		v.service.SetHistory(h)
+	}
+}
+
+func newValidator(service *application.PasswordService, opts []Option) *Validator {
+	v := &Validator{service: service}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// New returns a Validator with a single, default policy made of rules.
+func New(rules ...Rule) *Validator {
+	return &Validator{service: application.NewPasswordService(toValidators(rules))}
+}
+
+// NewWithPolicies returns a Validator holding several named policies. One
+// of them must be named DefaultPolicy.
+func NewWithPolicies(policies map[string][]Rule, opts ...Option) (*Validator, error) {
+	validators := make(map[string][]domain.PasswordValidator, len(policies))
+	for name, rules := range policies {
+		validators[name] = toValidators(rules)
+	}
+	service, err := application.NewPasswordServiceWithPolicies(validators)
+	if err != nil {
+		return nil, err
+	}
+	return newValidator(service, opts), nil
+}
+
+// Validate checks the password against the default policy.
+func (v *Validator) Validate(password string) *Result {
+	return toResult(v.service.Validate(password))
+}
+
+// ValidatePolicy checks the password against the named policy, or the
+// default policy when name is empty.
+func (v *Validator) ValidatePolicy(name, password string) (*Result, error) {
+	result, err := v.service.ValidatePolicy(name, password)
+	if err != nil {
+		return nil, err
+	}
+	return toResult(result), nil
+}
+
+// ValidatePolicyContext is ValidatePolicy with a context, which carries
+// deadlines to rules that perform I/O and the UserInfo and subject set with
+// WithUserInfo and WithSubject.
+func (v *Validator) ValidatePolicyContext(ctx context.Context, name, password string) (*Result, error) {
+	result, err := v.service.ValidatePolicyContext(ctx, name, password)
+	if err != nil {
+		return nil, err
+	}
+	return toResult(result), nil
+}
+
+// ValidatePasswordChange checks newPassword against the named policy,
+// letting rules such as Similarity compare it with oldPassword.
+func (v *Validator) ValidatePasswordChange(ctx context.Context, name, oldPassword, newPassword string) (*Result, error) {
+	result, err := v.service.ValidatePasswordChange(ctx, name, oldPassword, newPassword)
+	if err != nil {
+		return nil, err
+	}
+	return toResult(result), nil
+}
+
+// ValidateBatch validates every item against the named policy using at most
+// workers goroutines (GOMAXPROCS when workers is not positive). Results are
+// in the order of items.
+func (v *Validator) ValidateBatch(ctx context.Context, name string, items []BatchItem, workers int) ([]*Result, error) {
+	batch := make([]application.BatchItem, len(items))
+	for i, item := range items {
+		batch[i] = item.toApplication()
+	}
+	results, err := v.service.ValidateBatch(ctx, name, batch, workers)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Result, len(results))
+	for i, result := range results {
+		out[i] = toResult(result)
+	}
+	return out, nil
+}
+
+// ValidateStream validates the items received from in with workers
+// goroutines and sends their results, in completion order, on the returned
+// channel. The channel is closed once in is closed and drained, or when ctx
+// ends; in the latter case some items are left without a result and the
+// caller should check ctx.Err().
+func (v *Validator) ValidateStream(ctx context.Context, name string, in <-chan BatchItem, workers int) (<-chan BatchResult, error) {
+	items := make(chan application.BatchItem)
+	results, err := v.service.ValidateStream(ctx, name, items, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(items)
+		for {
+			select {
+			case item, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case items <- item.toApplication():
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	out := make(chan BatchResult, cap(results))
+	go func() {
+		defer close(out)
+		for result := range results {
+			select {
+			case out <- BatchResult{ID: result.ID, Seq: result.Seq, Result: toResult(result.ValidationResult)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
+// GeneratePasswords returns count passwords that satisfy the named policy.
+func (v *Validator) GeneratePasswords(ctx context.Context, name string, opts GenerateOptions, count int) ([]string, error) {
+	return v.service.GeneratePasswords(ctx, name, generator.Options{
+		Mode:             opts.Mode,
+		Length:           opts.Length,
+		Words:            opts.Words,
+		ExcludeAmbiguous: opts.ExcludeAmbiguous,
+	}, count)
+}
+
+// Policies returns the names of the policies in sorted order.
+func (v *Validator) Policies() []string {
+	return v.service.Policies()
+}
+
+// Describe lists the rules of the named policy, in the order they are
+// applied. Rules that do not implement Describer are reported as unknown.
+func (v *Validator) Describe(name string) ([]RuleDescription, error) {
+	descriptions, err := v.service.Describe(name)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RuleDescription, len(descriptions))
+	for i, d := range descriptions {
+		out[i] = fromDomainDescription(d)
+	}
+	return out, nil
+}
+
+// Result is the outcome of a validation: whether the password is valid, the
+// violations in rule order and an estimate of its strength.
+type Result struct {
+	IsValid bool   `json:"isValid"`
+	Policy  string `json:"policy"`
+	// Errors are the messages of Violations.
+	Errors     []string    `json:"errors,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+	Strength   Strength    `json:"strength"`
+}
+
+// Strength is the estimated strength of a password, scored from 0 to 4.
+type Strength struct {
+	Score        int
+	Guesses      float64
+	GuessesLog10 float64
+	CrackTimes   CrackTimes
+	// CrackTimeDisplay is a human-readable OfflineSlowHashing estimate.
+	CrackTimeDisplay string
+	Warning          string
+}
+
+// CrackTimes estimates, in seconds, how long an attacker needs to guess the
+// password under different attack scenarios.
+type CrackTimes struct {
+	OnlineThrottling   float64 // 100 guesses per hour
+	OnlineNoThrottling float64 // 10 guesses per second
+	OfflineSlowHashing float64 // 10^4 guesses per second (bcrypt, argon2)
+	OfflineFastHashing float64 // 10^10 guesses per second (unsalted fast hashes)
+}
+
+func toResult(r *application.ValidationResult) *Result {
+	result := &Result{
+		IsValid:  r.IsValid,
+		Policy:   r.Policy,
+		Errors:   r.Errors,
+		Strength: toStrength(r.Strength),
+	}
+	if len(r.Violations) > 0 {
+		result.Violations = make([]Violation, len(r.Violations))
+		for i, v := range r.Violations {
+			result.Violations[i] = fromDomainViolation(v)
+		}
+	}
+	return result
+}
+
+func toStrength(s strength.Result) Strength {
+	return Strength{
+		Score:        s.Score,
+		Guesses:      s.Guesses,
+		GuessesLog10: s.GuessesLog10,
+		CrackTimes: CrackTimes{
+			OnlineThrottling:   s.CrackTimes.OnlineThrottling,
+			OnlineNoThrottling: s.CrackTimes.OnlineNoThrottling,
+			OfflineSlowHashing: s.CrackTimes.OfflineSlowHashing,
+			OfflineFastHashing: s.CrackTimes.OfflineFastHashing,
+		},
+		CrackTimeDisplay: s.CrackTimeDisplay,
+		Warning:          s.Warning,
+	}
+}
+
+// BatchItem is a password validated by Validator.ValidateBatch or
+// Validator.ValidateStream.
+type BatchItem struct {
+	ID  string // caller's identifier, returned with the result
+	Seq int    // position in the input, returned with the result
+
+	Password string
+	Subject  string
+	User     UserInfo
+}
+
+func (item BatchItem) toApplication() application.BatchItem {
+	return application.BatchItem{
+		ID:       item.ID,
+		Seq:      item.Seq,
+		Password: item.Password,
+		Subject:  item.Subject,
+		User:     item.User.toDomain(),
+	}
+}
+
+// BatchResult is the result of a BatchItem sent to Validator.ValidateStream.
+type BatchResult struct {
+	ID  string
+	Seq int
+	*Result
+}
+
+// GenerateOptions tune the passwords created by Validator.GeneratePasswords.
+type GenerateOptions struct {
+	Mode             string // ModeRandom (the default) or ModePassphrase
+	Length           int    // random mode only
+	Words            int    // passphrase mode only
+	ExcludeAmbiguous bool
+}
+
+// Modes of GenerateOptions.
+const (
+	ModeRandom     = generator.ModeRandom
+	ModePassphrase = generator.ModePassphrase
+)
+
+var (
+	// ErrInvalidOptions is returned by Validator.GeneratePasswords for
+	// options out of range.
+	ErrInvalidOptions = generator.ErrInvalidOptions
+	// ErrUnsatisfiable is returned by Validator.GeneratePasswords when the
+	// policy cannot be met with the given options.
+	ErrUnsatisfiable = generator.ErrUnsatisfiable
+)
+
+// UserInfo describes the account a password is chosen for, for rules such
+// as PersonalInfo. Attach it to the validation context with WithUserInfo.
+type UserInfo struct {
+	Username    string
+	Email       string
+	FirstName   string
+	LastName    string
+	CompanyName string
+}
+
+// WithUserInfo returns a copy of ctx carrying the user information.
+func WithUserInfo(ctx context.Context, user UserInfo) context.Context {
+	return domain.WithUserInfo(ctx, user.toDomain())
+}
+
+func (u UserInfo) toDomain() domain.UserInfo {
+	return domain.UserInfo{
+		Username:    u.Username,
+		Email:       u.Email,
+		FirstName:   u.FirstName,
+		LastName:    u.LastName,
+		CompanyName: u.CompanyName,
+	}
+}
+
+// WithSubject returns a copy of ctx identifying the subject whose password
+// is validated, for password history.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return domain.WithSubject(ctx, subject)
+}
@@ -0,0 +1,268 @@
+package passwordpolicy_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/pkg/passwordpolicy"
+)
+
+// deadlineRule reports whether it was called with the validation context.
+type deadlineRule struct{}
+
+func (deadlineRule) Validate(password string) error {
+	return errors.New("called without context")
+}
+
+func (deadlineRule) ValidateContext(ctx context.Context, password string) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("context has no deadline")
+	}
+	return nil
+}
+
+func TestValidator_ContextRule(t *testing.T) {
+	validator := passwordpolicy.New(deadlineRule{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := validator.ValidatePolicyContext(ctx, "", "whatever")
+	if err != nil {
+		t.Fatalf("ValidatePolicyContext() error: %v", err)
+	}
+	if !result.IsValid {
+		t.Errorf("Violations = %v, want the rule to receive the context", result.Violations)
+	}
+
+	result = validator.Validate("whatever")
+	if result.IsValid || result.Violations[0].Code != passwordpolicy.CodeUnknown {
+		t.Errorf("Violations = %v, want a plain error reported as %s", result.Violations, passwordpolicy.CodeUnknown)
+	}
+}
+
+func TestNewWithPolicies(t *testing.T) {
+	_, err := passwordpolicy.NewWithPolicies(map[string][]passwordpolicy.Rule{"pin": {passwordpolicy.MinLength(4)}})
+	if !errors.Is(err, passwordpolicy.ErrNoDefaultPolicy) {
+		t.Errorf("error = %v, want ErrNoDefaultPolicy", err)
+	}
+
+	validator, err := passwordpolicy.NewWithPolicies(map[string][]passwordpolicy.Rule{
+		passwordpolicy.DefaultPolicy: {passwordpolicy.MinLength(9)},
+	})
+	if err != nil {
+		t.Fatalf("NewWithPolicies() error: %v", err)
+	}
+	if _, err := validator.ValidatePolicy("pin", "1234"); !errors.Is(err, passwordpolicy.ErrPolicyNotFound) {
+		t.Errorf("error = %v, want ErrPolicyNotFound", err)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	validator, err := passwordpolicy.NewFromDocument(passwordpolicy.Default())
+	if err != nil {
+		t.Fatalf("NewFromDocument() error: %v", err)
+	}
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{"AbTp9!fok", nil},
+		{"AbTp9!foo", []string{passwordpolicy.CodeDuplicateChar}},
+		{"abtp9!fok", []string{passwordpolicy.CodeNoUppercase}},
+		{"AbTp9 fok", []string{passwordpolicy.CodeNoSpecialChar, passwordpolicy.CodeWhitespace}},
+	}
+	for _, tt := range tests {
+		var codes []string
+		for _, v := range validator.Validate(tt.password).Violations {
+			codes = append(codes, v.Code)
+		}
+		if !slices.Equal(codes, tt.want) {
+			t.Errorf("Validate(%q) codes = %v, want %v", tt.password, codes, tt.want)
+		}
+	}
+}
+
+func TestRuleNames(t *testing.T) {
+	names := passwordpolicy.RuleNames()
+	for _, rule := range []string{
+		passwordpolicy.RuleMinLength,
+		passwordpolicy.RuleDigit,
+		passwordpolicy.RuleLowercase,
+		passwordpolicy.RuleUppercase,
+		passwordpolicy.RuleSpecialChar,
+		passwordpolicy.RuleNoDuplicates,
+		passwordpolicy.RuleMinStrength,
+		passwordpolicy.RulePersonalInfo,
+		passwordpolicy.RuleSimilarity,
+		passwordpolicy.RuleBreached,
+		passwordpolicy.RuleBlocklist,
+	} {
+		if !slices.Contains(names, rule) {
+			t.Errorf("RuleNames() = %v, missing %s", names, rule)
+		}
+	}
+}
+
+func TestBuiltinRule_ReturnsViolation(t *testing.T) {
+	err := passwordpolicy.Digit().Validate("abcdef")
+
+	var v *passwordpolicy.Violation
+	if !errors.As(err, &v) || v.Code != passwordpolicy.CodeNoDigit {
+		t.Fatalf("Validate() error = %#v, want a *Violation with code %s", err, passwordpolicy.CodeNoDigit)
+	}
+	if !errors.Is(err, passwordpolicy.NewViolation(passwordpolicy.CodeNoDigit, "", "")) {
+		t.Error("errors.Is() = false, want violations to match by code")
+	}
+}
+
+// describedRule is a custom rule that describes itself.
+type describedRule struct{}
+
+func (describedRule) Validate(password string) error { return nil }
+
+func (describedRule) Describe() passwordpolicy.RuleDescription {
+	return passwordpolicy.RuleDescription{Rule: "described", Codes: []string{"DESCRIBED"}}
+}
+
+func TestValidator_Describe(t *testing.T) {
+	validator := passwordpolicy.New(passwordpolicy.MinLength(9), describedRule{}, deadlineRule{})
+
+	descriptions, err := validator.Describe("")
+	if err != nil {
+		t.Fatalf("Describe() error: %v", err)
+	}
+	var names []string
+	for _, d := range descriptions {
+		names = append(names, d.Rule)
+	}
+	if want := []string{passwordpolicy.RuleMinLength, "described", "unknown"}; !slices.Equal(names, want) {
+		t.Errorf("Describe() rules = %v, want %v", names, want)
+	}
+}
+
+func TestPasswordHistory(t *testing.T) {
+	history := passwordpolicy.NewMemoryHistory(2)
+	validator := passwordpolicy.New(passwordpolicy.PasswordHistory(history))
+
+	ctx := passwordpolicy.WithSubject(context.Background(), "alice")
+	if err := history.Record(ctx, "alice", "Old#Passw0rd"); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	result, err := validator.ValidatePolicyContext(ctx, "", "Old#Passw0rd")
+	if err != nil {
+		t.Fatalf("ValidatePolicyContext() error: %v", err)
+	}
+	if result.IsValid || result.Violations[0].Code != passwordpolicy.CodePasswordReused {
+		t.Errorf("Violations = %v, want %s", result.Violations, passwordpolicy.CodePasswordReused)
+	}
+}
+
+func TestWithHistory(t *testing.T) {
+	history := passwordpolicy.NewMemoryHistory(2)
+	validator, err := passwordpolicy.NewFromDocument(passwordpolicy.Default(), passwordpolicy.WithHistory(history))
+	if err != nil {
+		t.Fatalf("NewFromDocument() error: %v", err)
+	}
+
+	ctx := passwordpolicy.WithSubject(context.Background(), "alice")
+	if err := history.Record(ctx, "alice", "Old#Passw0rd"); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	result, err := validator.ValidatePolicyContext(ctx, "", "Old#Passw0rd")
+	if err != nil {
+		t.Fatalf("ValidatePolicyContext() error: %v", err)
+	}
+	if result.IsValid || result.Violations[len(result.Violations)-1].Code != passwordpolicy.CodePasswordReused {
+		t.Errorf("Violations = %v, want %s last", result.Violations, passwordpolicy.CodePasswordReused)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	write := func(minLength int) {
+		t.Helper()
+		data := fmt.Sprintf("rules:\n  - name: min_length\n    params: {length: %d}\n", minLength)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(4)
+
+	doc, err := passwordpolicy.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	validator, err := passwordpolicy.NewFromDocument(doc)
+	if err != nil {
+		t.Fatalf("NewFromDocument() error: %v", err)
+	}
+	reloader := passwordpolicy.NewReloader(path, validator, doc)
+
+	if reloaded, err := reloader.Reload(); err != nil || reloaded {
+		t.Fatalf("Reload() of an unchanged file = %v, %v; want false, nil", reloaded, err)
+	}
+
+	write(12)
+	if reloaded, err := reloader.Reload(); err != nil || !reloaded {
+		t.Fatalf("Reload() = %v, %v; want true, nil", reloaded, err)
+	}
+	if validator.Validate("short").IsValid {
+		t.Error("Validate() passed a password shorter than the reloaded minimum")
+	}
+}
+
+// breachSource serves a single breached password.
+type breachSource struct {
+	password string
+}
+
+func (s breachSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	sum := sha1.Sum([]byte(s.password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if hash[:5] != prefix {
+		return nil, nil
+	}
+	return map[string]int{hash[5:]: 42}, nil
+}
+
+func TestBreached(t *testing.T) {
+	validator := passwordpolicy.New(passwordpolicy.Breached(breachSource{password: "P@ssw0rd"}, time.Second, true))
+
+	if result := validator.Validate("P@ssw0rd"); result.IsValid || result.Violations[0].Code != passwordpolicy.CodeBreachedPassword {
+		t.Errorf("Violations = %v, want %s", result.Violations, passwordpolicy.CodeBreachedPassword)
+	}
+	if result := validator.Validate("Tr0ub4dor&3"); !result.IsValid {
+		t.Errorf("Violations = %v, want none", result.Violations)
+	}
+}
+
+func TestBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("letmein\nqwerty123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rule, err := passwordpolicy.Blocklist(path, passwordpolicy.BlocklistPlain, passwordpolicy.DefaultBlocklistFalsePositiveRate)
+	if err != nil {
+		t.Fatalf("Blocklist() error: %v", err)
+	}
+	if err := rule.Validate("qwerty123"); !errors.Is(err, passwordpolicy.NewViolation(passwordpolicy.CodeBlocklistedPassword, "", "")) {
+		t.Errorf("Validate() error = %v, want %s", err, passwordpolicy.CodeBlocklistedPassword)
+	}
+
+	if _, err := passwordpolicy.Blocklist(path, "csv", passwordpolicy.DefaultBlocklistFalsePositiveRate); err == nil {
+		t.Error("Blocklist() error = nil, want an error for an unknown format")
+	}
+}
@@ -0,0 +1,407 @@
+package passwordpolicy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/breach"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+)
+
+// Rule is a single password requirement. Validate returns nil when the
+// password satisfies it, and otherwise preferably a *Violation, so clients
+// get a stable code; other errors are reported with the UNKNOWN code.
+type Rule interface {
+	Validate(password string) error
+}
+
+// ContextRule is implemented by rules that perform I/O or read request data
+// from the context, such as UserInfo. The Validator calls ValidateContext
+// instead of Validate for them.
+type ContextRule interface {
+	Rule
+	ValidateContext(ctx context.Context, password string) error
+}
+
+// Describer is implemented by rules that describe themselves, so the active
+// policy can be published (e.g. by GET /api/v1/policy).
+type Describer interface {
+	Describe() RuleDescription
+}
+
+// RuleDescription documents a rule, with the codes and parameter names found
+// in its violations.
+type RuleDescription struct {
+	Rule        string
+	Codes       []string
+	Description string
+	Params      map[string]any
+}
+
+// Violation is the structured error of a broken rule. Code is the stable,
+// machine-readable identifier; Message is human-readable text.
+type Violation struct {
+	Code    string
+	Rule    string
+	Message string
+	Params  map[string]any
+	// Char and Position (in runes) locate the offending character, when
+	// the violation is tied to one.
+	Char     string
+	Position int
+}
+
+// NoPosition is the Position of violations not tied to a character.
+const NoPosition = domain.NoPosition
+
+// NewViolation returns a violation for a custom rule. Chain WithParam and At
+// to add details; each returns a copy and leaves its receiver unchanged.
+func NewViolation(code, rule, message string) *Violation {
+	return &Violation{
+		Code:     code,
+		Rule:     rule,
+		Message:  message,
+		Position: NoPosition,
+	}
+}
+
+// WithParam returns a copy of the violation with a rule parameter attached.
+func (v *Violation) WithParam(key string, value any) *Violation {
+	c := *v
+	c.Params = make(map[string]any, len(v.Params)+1)
+	for k, p := range v.Params {
+		c.Params[k] = p
+	}
+	c.Params[key] = value
+	return &c
+}
+
+// At returns a copy of the violation recording the offending character and
+// its position.
+func (v *Violation) At(char rune, position int) *Violation {
+	c := *v
+	c.Char = string(char)
+	c.Position = position
+	return &c
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// Is reports whether target is a violation with the same code.
+func (v *Violation) Is(target error) bool {
+	t, ok := target.(*Violation)
+	return ok && t.Code == v.Code
+}
+
+// Validate runs a single rule, through ValidateContext when it is a
+// ContextRule.
+func Validate(ctx context.Context, rule Rule, password string) error {
+	if r, ok := rule.(ContextRule); ok {
+		return r.ValidateContext(ctx, password)
+	}
+	return rule.Validate(password)
+}
+
+// Names of the built-in rules, as used in policy documents and in
+// Violation.Rule.
+const (
+	RuleMinLength       = rules.RuleMinLength
+	RuleDigit           = rules.RuleDigit
+	RuleLowercase       = rules.RuleLowercase
+	RuleUppercase       = rules.RuleUppercase
+	RuleSpecialChar     = rules.RuleSpecialChar
+	RuleNoDuplicates    = rules.RuleNoDuplicates
+	RuleMinStrength     = rules.RuleMinStrength
+	RulePersonalInfo    = rules.RulePersonalInfo
+	RuleSimilarity      = rules.RuleSimilarity
+	RuleBreached        = rules.RuleBreached
+	RuleBlocklist       = rules.RuleBlocklist
+	RulePasswordHistory = rules.RulePasswordHistory
+	// RuleNoWhitespace is the Violation.Rule of CodeWhitespace, which the
+	// NoDuplicates rule reports.
+	RuleNoWhitespace = rules.RuleNoWhitespace
+)
+
+// Violation codes of the built-in rules.
+const (
+	CodeMinLength              = rules.CodeMinLength
+	CodeNoDigit                = rules.CodeNoDigit
+	CodeNoLowercase            = rules.CodeNoLowercase
+	CodeNoUppercase            = rules.CodeNoUppercase
+	CodeNoSpecialChar          = rules.CodeNoSpecialChar
+	CodeDuplicateChar          = rules.CodeDuplicateChar
+	CodeWhitespace             = rules.CodeWhitespace
+	CodeWeakPassword           = rules.CodeWeakPassword
+	CodeContainsPersonalInfo   = rules.CodeContainsPersonalInfo
+	CodeSimilarToPrevious      = rules.CodeSimilarToPrevious
+	CodeBreachedPassword       = rules.CodeBreachedPassword
+	CodeBreachCheckUnavailable = rules.CodeBreachCheckUnavailable
+	CodeBlocklistedPassword    = rules.CodeBlocklistedPassword
+	CodePasswordReused         = rules.CodePasswordReused
+	CodeHistoryUnavailable     = rules.CodeHistoryUnavailable
+	CodeUnknown                = domain.CodeUnknown
+)
+
+// MinLength requires at least n characters.
+func MinLength(n int) Rule {
+	return builtin{rules.NewMinLengthValidator(n)}
+}
+
+// Digit requires at least one digit.
+func Digit() Rule {
+	return builtin{rules.NewDigitValidator()}
+}
+
+// Lowercase requires at least one lowercase letter.
+func Lowercase() Rule {
+	return builtin{rules.NewLowercaseValidator()}
+}
+
+// Uppercase requires at least one uppercase letter.
+func Uppercase() Rule {
+	return builtin{rules.NewUppercaseValidator()}
+}
+
+// SpecialChar requires at least one of the allowed characters.
+func SpecialChar(allowed string) Rule {
+	return builtin{rules.NewSpecialCharValidator(allowed)}
+}
+
+// NoDuplicates rejects repeated characters and whitespace.
+func NoDuplicates() Rule {
+	return builtin{rules.NewNoDuplicatesValidator()}
+}
+
+// MinStrength requires an estimated strength score of at least score (0-4).
+func MinStrength(score int) Rule {
+	return builtin{rules.NewMinStrengthValidator(score)}
+}
+
+// PersonalInfo rejects passwords containing the UserInfo of the validation
+// context, ignoring fragments shorter than minTokenLength.
+func PersonalInfo(minTokenLength int) Rule {
+	return builtin{rules.NewPersonalInfoValidator(minTokenLength)}
+}
+
+// Similarity rejects small variations of the previous password, which
+// Validator.ValidatePasswordChange puts in the context.
+func Similarity(minDistance int) Rule {
+	return builtin{rules.NewSimilarityValidator(minDistance)}
+}
+
+// BreachSource looks up breached password hashes by k-anonymity: given the
+// first five hex characters of a SHA-1 hash, it returns the occurrence count
+// of every known hash suffix (the remaining 35 upper-case hex characters)
+// sharing that prefix.
+type BreachSource interface {
+	Range(ctx context.Context, prefix string) (map[string]int, error)
+}
+
+// BreachAPI queries a Pwned Passwords compatible range API at url, or the
+// public Have I Been Pwned API when url is empty.
+func BreachAPI(url string, timeout time.Duration) BreachSource {
+	return breach.NewHTTPSource(url, timeout)
+}
+
+// BreachDirectory serves ranges from a local copy of the corpus laid out as
+// one "{PREFIX}.txt" file per hash prefix, as produced by the official
+// Pwned Passwords downloader.
+func BreachDirectory(dir string) (BreachSource, error) {
+	source, err := breach.NewDirectorySource(dir)
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// CachedBreachSource keeps up to size recent ranges of source in memory for
+// ttl. Errors are never cached.
+func CachedBreachSource(source BreachSource, ttl time.Duration, size int) BreachSource {
+	return breach.NewCachedSource(source, ttl, size)
+}
+
+// Breached rejects passwords found by source. Lookups are abandoned after
+// timeout (2s when not positive); the password is then accepted when
+// failOpen is set and rejected with CodeBreachCheckUnavailable otherwise.
+func Breached(source BreachSource, timeout time.Duration, failOpen bool) Rule {
+	return builtin{rules.NewBreachedValidator(source, timeout, failOpen)}
+}
+
+// BlocklistFormat is the layout of a blocklist file.
+type BlocklistFormat string
+
+const (
+	// BlocklistPlain is one plain-text password per line.
+	BlocklistPlain = BlocklistFormat(rules.BlocklistPlain)
+	// BlocklistSHA1 is one hex SHA-1 hash per line, optionally followed by
+	// ":COUNT" as in the Have I Been Pwned downloads.
+	BlocklistSHA1 = BlocklistFormat(rules.BlocklistSHA1)
+)
+
+// DefaultBlocklistFalsePositiveRate is the false-positive rate used by
+// policy documents that do not set one.
+const DefaultBlocklistFalsePositiveRate = rules.DefaultBlocklistFalsePositiveRate
+
+// Blocklist rejects passwords found in the list at path. The list is kept
+// in a Bloom filter, so a small fraction (falsePositiveRate) of passwords
+// not in it are rejected as well.
+func Blocklist(path string, format BlocklistFormat, falsePositiveRate float64) (Rule, error) {
+	validator, err := rules.LoadBlocklistValidator(path, rules.BlocklistFormat(format), falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+	return builtin{validator}, nil
+}
+
+// History remembers the passwords used by each subject. Record a password
+// after each successful change, and reject reuse with PasswordHistory.
+type History interface {
+	// Contains reports whether password is among the subject's last Depth
+	// passwords.
+	Contains(ctx context.Context, subject, password string) (bool, error)
+	Depth() int
+	Record(ctx context.Context, subject, password string) error
+}
+
+// NewMemoryHistory returns a History kept in memory that remembers the last
+// depth passwords of each subject, hashed with argon2id.
+func NewMemoryHistory(depth int) History {
+	return history.NewHistory(history.NewMemoryStore(), history.NewArgon2idHasher(history.DefaultArgon2Params), depth)
+}
+
+// PasswordHistory rejects a password the subject in the validation context
+// (see WithSubject) used recently. Passwords without a subject pass.
+func PasswordHistory(h History) Rule {
+	return builtin{rules.NewPasswordHistoryValidator(h)}
+}
+
+// builtin exposes a validator of the server as a Rule, reporting its
+// violations as *Violation.
+type builtin struct {
+	validator domain.PasswordValidator
+}
+
+func (b builtin) Validate(password string) error {
+	return fromDomainError(b.validator.Validate(password))
+}
+
+func (b builtin) ValidateContext(ctx context.Context, password string) error {
+	return fromDomainError(domain.ValidateContext(ctx, b.validator, password))
+}
+
+func (b builtin) Describe() RuleDescription {
+	return fromDomainDescription(domain.Describe(b.validator))
+}
+
+// customRule runs a Rule written against this package in the server's
+// validation pipeline, converting its *Violation errors.
+type customRule struct {
+	rule Rule
+}
+
+func (c customRule) Validate(password string) error {
+	return toDomainError(c.rule.Validate(password))
+}
+
+func (c customRule) Describe() domain.RuleDescription {
+	if d, ok := c.rule.(Describer); ok {
+		description := d.Describe()
+		return domain.RuleDescription{
+			Rule:        description.Rule,
+			Codes:       description.Codes,
+			Description: description.Description,
+			Params:      description.Params,
+		}
+	}
+	return domain.Describe(c.rule)
+}
+
+// customContextRule is a customRule for a ContextRule.
+type customContextRule struct {
+	customRule
+}
+
+func (c customContextRule) ValidateContext(ctx context.Context, password string) error {
+	return toDomainError(c.rule.(ContextRule).ValidateContext(ctx, password))
+}
+
+// toValidator returns the validator the server runs for rule.
+func toValidator(rule Rule) domain.PasswordValidator {
+	switch r := rule.(type) {
+	case builtin:
+		return r.validator
+	case ContextRule:
+		return customContextRule{customRule{r}}
+	default:
+		return customRule{r}
+	}
+}
+
+func toValidators(list []Rule) []domain.PasswordValidator {
+	validators := make([]domain.PasswordValidator, len(list))
+	for i, rule := range list {
+		validators[i] = toValidator(rule)
+	}
+	return validators
+}
+
+// fromValidator is the inverse of toValidator.
+func fromValidator(validator domain.PasswordValidator) Rule {
+	switch v := validator.(type) {
+	case customRule:
+		return v.rule
+	case customContextRule:
+		return v.rule
+	default:
+		return builtin{v}
+	}
+}
+
+func toDomainError(err error) error {
+	var v *Violation
+	if errors.As(err, &v) {
+		return &domain.Violation{
+			Code:     v.Code,
+			Rule:     v.Rule,
+			Message:  v.Message,
+			Params:   v.Params,
+			Char:     v.Char,
+			Position: v.Position,
+		}
+	}
+	return err
+}
+
+func fromDomainError(err error) error {
+	var v *domain.Violation
+	if errors.As(err, &v) {
+		violation := fromDomainViolation(*v)
+		return &violation
+	}
+	return err
+}
+
+func fromDomainViolation(v domain.Violation) Violation {
+	return Violation{
+		Code:     v.Code,
+		Rule:     v.Rule,
+		Message:  v.Message,
+		Params:   v.Params,
+		Char:     v.Char,
+		Position: v.Position,
+	}
+}
+
+func fromDomainDescription(d domain.RuleDescription) RuleDescription {
+	return RuleDescription{
+		Rule:        d.Rule,
+		Codes:       d.Codes,
+		Description: d.Description,
+		Params:      d.Params,
+	}
+}
@@ -3,17 +3,23 @@ package integration
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/gorilla/mux"
+	"github.com/willherrera/itau-backend-challenge/internal/api"
 	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
 	"github.com/willherrera/itau-backend-challenge/internal/api/middleware"
 	"github.com/willherrera/itau-backend-challenge/internal/api/models"
 	"github.com/willherrera/itau-backend-challenge/internal/application"
 	"github.com/willherrera/itau-backend-challenge/internal/domain"
 	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/health"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
 )
 
 func setupTestServer() *httptest.Server {
@@ -26,15 +32,19 @@ func setupTestServer() *httptest.Server {
 		rules.NewNoDuplicatesValidator(),
 	}
 
-	service := application.NewPasswordService(validators)
-	handler := handlers.NewPasswordHandler(service)
-
-	router := mux.NewRouter()
-	router.HandleFunc("/api/v1/validate-password", handler.ValidatePassword).Methods("POST")
-	router.HandleFunc("/health", handler.Health).Methods("GET")
-	router.Use(middleware.LoggingMiddleware)
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: validators,
+		"admin": {
+			rules.NewMinLengthValidator(14),
+			rules.NewDigitValidator(),
+			rules.NewPersonalInfoValidator(4),
+		},
+	})
+	return newTestServer(service)
+}
 
-	return httptest.NewServer(router)
+func newTestServer(service *application.PasswordService) *httptest.Server {
+	return httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service)))
 }
 
 func TestValidatePasswordEndpoint(t *testing.T) {
@@ -118,6 +128,10 @@ func TestValidatePasswordEndpoint(t *testing.T) {
 			if !tt.wantValid && len(response.Errors) == 0 {
 				t.Error("Expected errors for invalid password, got none")
 			}
+
+			if len(response.Violations) != len(response.Errors) {
+				t.Errorf("Got %d violations for %d errors", len(response.Violations), len(response.Errors))
+			}
 		})
 	}
 }
@@ -176,27 +190,1179 @@ func TestAllRequirementExamples(t *testing.T) {
 	}
 }
 
-func TestHealthEndpoint(t *testing.T) {
+func TestValidatePasswordViolations(t *testing.T) {
 	server := setupTestServer()
 	defer server.Close()
 
-	resp, err := http.Get(server.URL + "/health")
+	body, _ := json.Marshal(models.ValidatePasswordRequest{Password: "AbTp9!foo"})
+	resp, err := http.Post(
+		server.URL+"/api/v1/validate-password",
+		"application/json",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response models.ValidatePasswordResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Violations) != 1 {
+		t.Fatalf("Got %d violations, want 1: %+v", len(response.Violations), response.Violations)
+	}
+
+	v := response.Violations[0]
+	if v.Code != rules.CodeDuplicateChar || v.Rule != rules.RuleNoDuplicates {
+		t.Errorf("Violation code/rule = %s/%s, want %s/%s", v.Code, v.Rule, rules.CodeDuplicateChar, rules.RuleNoDuplicates)
+	}
+	if v.Char != "o" || v.Position == nil || *v.Position != 8 {
+		t.Errorf("Violation char/position = %q/%v, want \"o\"/8", v.Char, v.Position)
+	}
+}
+
+func TestValidatePasswordLocalized(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		locale         string
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{
+			name:         "english by default",
+			wantLanguage: "en",
+			wantMessage:  "password must have at least 9 characters",
+		},
+		{
+			name:           "accept-language",
+			acceptLanguage: "pt-BR,pt;q=0.9,en;q=0.8",
+			wantLanguage:   "pt-BR",
+			wantMessage:    "a senha deve ter pelo menos 9 caracteres",
+		},
+		{
+			name:           "locale field wins over the header",
+			acceptLanguage: "pt-BR",
+			locale:         "es",
+			wantLanguage:   "es",
+			wantMessage:    "la contraseña debe tener al menos 9 caracteres",
+		},
+		{
+			name:           "unsupported language falls back to english",
+			acceptLanguage: "fr-FR",
+			wantLanguage:   "en",
+			wantMessage:    "password must have at least 9 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(models.ValidatePasswordRequest{Password: "Ab1!", Locale: tt.locale})
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/validate-password", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.Header.Get("Content-Language"); got != tt.wantLanguage {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLanguage)
+			}
+
+			var response models.ValidatePasswordResponse
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Violations[0].Message != tt.wantMessage || response.Errors[0] != tt.wantMessage {
+				t.Errorf("Message = %q, errors[0] = %q, want %q", response.Violations[0].Message, response.Errors[0], tt.wantMessage)
+			}
+			if response.Violations[0].Code != rules.CodeMinLength {
+				t.Errorf("Code = %s, want %s regardless of the language", response.Violations[0].Code, rules.CodeMinLength)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordPersonalInfo(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		request   models.ValidatePasswordRequest
+		wantValid bool
+	}{
+		{
+			name:      "valid without user details",
+			request:   models.ValidatePasswordRequest{Password: "M4ria.Souza#2024"},
+			wantValid: true,
+		},
+		{
+			name:      "invalid contains first name",
+			request:   models.ValidatePasswordRequest{Password: "M4ria.Souza#2024", UserDetails: models.UserDetails{FirstName: "Maria", LastName: "Oliveira"}},
+			wantValid: false,
+		},
+		{
+			name:      "valid unrelated user details",
+			request:   models.ValidatePasswordRequest{Password: "M4ria.Souza#2024", UserDetails: models.UserDetails{Username: "pedro", Email: "pedro@example.com"}},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.request)
+			resp, err := http.Post(server.URL+"/api/v1/policies/admin/validate", "application/json", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var response models.ValidatePasswordResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+
+			if response.IsValid != tt.wantValid {
+				t.Fatalf("IsValid = %v, want %v. Violations: %+v", response.IsValid, tt.wantValid, response.Violations)
+			}
+			if !tt.wantValid && response.Violations[0].Code != rules.CodeContainsPersonalInfo {
+				t.Errorf("Violation code = %s, want %s", response.Violations[0].Code, rules.CodeContainsPersonalInfo)
+			}
+		})
+	}
+}
+
+func TestValidatePasswords(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	request := models.ValidatePasswordsRequest{
+		Items: []models.ValidatePasswordItem{
+			{ID: "a", Password: "AbTp9!fok"},
+			{ID: "b", Password: "AbTp9!foo"},
+			{ID: "c", Password: "aa"},
+			{Password: "Xk7#mQz2p"},
+		},
+	}
+	body, _ := json.Marshal(request)
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords", "application/json", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatalf("Failed to make request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
+		t.Fatalf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
 	}
 
-	var health models.HealthResponse
-	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	var response models.ValidatePasswordsResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	if response.Policy != "default" || response.Total != 4 || response.Valid != 2 || response.Invalid != 2 {
+		t.Errorf("Summary = %s total %d valid %d invalid %d, want default 4/2/2", response.Policy, response.Total, response.Valid, response.Invalid)
 	}
 
-	if health.Status != "healthy" {
-		t.Errorf("Health status = %s, want healthy", health.Status)
+	wantIDs := []string{"a", "b", "c", ""}
+	wantValid := []bool{true, false, false, true}
+	for i, result := range response.Results {
+		if result.ID != wantIDs[i] || result.IsValid != wantValid[i] {
+			t.Errorf("Results[%d] = %s valid %v, want %s valid %v", i, result.ID, result.IsValid, wantIDs[i], wantValid[i])
+		}
+	}
+	if codes := response.Results[1].Violations; len(codes) != 1 || codes[0].Code != rules.CodeDuplicateChar {
+		t.Errorf("Results[1].Violations = %+v, want DUPLICATE_CHAR", codes)
+	}
+}
+
+func TestValidatePasswords_Errors(t *testing.T) {
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+	})
+	handler := handlers.NewPasswordHandler(service)
+	handler.SetBatchLimits(2, 1)
+	server := httptest.NewServer(http.HandlerFunc(handler.ValidatePasswords))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"within limit", `{"items":[{"password":"AbTp9!fok"},{"password":"x"}]}`, http.StatusOK},
+		{"over item limit", `{"items":[{"password":"a"},{"password":"b"},{"password":"c"}]}`, http.StatusRequestEntityTooLarge},
+		{"body too large", `{"items":[{"password":"` + strings.Repeat("a", 9000) + `"}]}`, http.StatusRequestEntityTooLarge},
+		{"no items", `{"items":[]}`, http.StatusBadRequest},
+		{"missing password", `{"items":[{"id":"a"}]}`, http.StatusBadRequest},
+		{"unknown policy", `{"policy":"pin","items":[{"password":"x"}]}`, http.StatusNotFound},
+		{"invalid JSON", `{"items":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordStream(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	body := strings.Join([]string{
+		`{"id":"a","password":"AbTp9!fok"}`,
+		`{"id":"b","password":"AbTp9!foo"}`,
+		``,
+		`{"id":"c","password":"aa"}`,
+		`not json`,
+		`{"id":"d","password":"` + strings.Repeat("x", 5000) + `"}`,
+		`{"id":"e"}`,
+		`{"id":"f","password":"Xk7#mQz2p"}`,
+	}, "\n")
+
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords/stream", "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+	}
+
+	results := make(map[int]models.ValidatePasswordStreamResult)
+	var summary models.ValidatePasswordStreamSummary
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			t.Fatalf("Invalid NDJSON line: %v", err)
+		}
+		if bytes.HasPrefix(raw, []byte(`{"summary"`)) {
+			json.Unmarshal(raw, &summary)
+			continue
+		}
+		var result models.ValidatePasswordStreamResult
+		json.Unmarshal(raw, &result)
+		results[result.Line] = result
+	}
+
+	want := map[int]struct {
+		id      string
+		valid   bool
+		withErr bool
+	}{
+		1: {id: "a", valid: true},
+		2: {id: "b"},
+		4: {id: "c"},
+		5: {withErr: true},
+		6: {withErr: true},
+		7: {id: "e", withErr: true},
+		8: {id: "f", valid: true},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Got %d result lines, want %d: %+v", len(results), len(want), results)
+	}
+	for line, w := range want {
+		got := results[line]
+		if got.ID != w.id || got.IsValid != w.valid || (got.Error != "") != w.withErr {
+			t.Errorf("Line %d = %+v, want id %q valid %v error %v", line, got, w.id, w.valid, w.withErr)
+		}
+	}
+
+	s := summary.Summary
+	if s.Policy != "default" || s.Total != 7 || s.Valid != 2 || s.Invalid != 2 || s.Rejected != 3 {
+		t.Errorf("Summary = %+v, want default 7 total, 2 valid, 2 invalid, 3 rejected", s)
+	}
+	if s.Violations[rules.RuleNoDuplicates] != 2 || s.Violations[rules.RuleMinLength] != 1 {
+		t.Errorf("Summary.Violations = %v, want no_duplicates 2 and min_length 1", s.Violations)
+	}
+}
+
+func TestValidatePasswordStream_Errors(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords/stream", "application/json", strings.NewReader(`{"password":"x"}`))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusUnsupportedMediaType)
+	}
+
+	resp, err = http.Post(server.URL+"/api/v1/validate-passwords/stream?policy=pin", "application/x-ndjson", strings.NewReader(`{"password":"x"}`))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestValidatePasswordStream_MaxLines(t *testing.T) {
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+	})
+	handler := handlers.NewPasswordHandler(service)
+	handler.SetStreamMaxLines(2)
+	server := httptest.NewServer(api.NewRouter(handler))
+	defer server.Close()
+
+	body := strings.Repeat(`{"password":"AbTp9!fok"}`+"\n", 5)
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords/stream", "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var summary models.ValidatePasswordStreamSummary
+	json.Unmarshal([]byte(lines[len(lines)-1]), &summary)
+	if summary.Summary.Total != 2 || !strings.Contains(summary.Summary.Error, "first 2 lines") {
+		t.Errorf("Summary = %+v, want 2 lines read and an error", summary.Summary)
+	}
+}
+
+// streamSummary posts the stream fed by feed to server and returns the
+// summary line of the response.
+func streamSummary(t *testing.T, server *httptest.Server, feed func(w io.Writer)) models.StreamSummary {
+	t.Helper()
+
+	bodyReader, bodyWriter := io.Pipe()
+	defer bodyWriter.Close()
+	go feed(bodyWriter)
+
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords/stream", "application/x-ndjson", bodyReader)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var summary models.ValidatePasswordStreamSummary
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+		t.Fatalf("Last line %q is not a summary: %v", lines[len(lines)-1], err)
+	}
+	return summary.Summary
+}
+
+func TestValidatePasswordStream_LineTimeout(t *testing.T) {
+	service := application.NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+	handler := handlers.NewPasswordHandler(service)
+	handler.SetStreamTimeouts(200*time.Millisecond, time.Minute)
+	server := httptest.NewServer(api.NewRouter(handler))
+	defer server.Close()
+
+	// One line, then nothing until the client gives up.
+	summary := streamSummary(t, server, func(w io.Writer) {
+		io.WriteString(w, `{"password":"AbTp9!fok"}`+"\n")
+	})
+	if summary.Total != 1 || !strings.Contains(summary.Error, "No line was received") {
+		t.Errorf("Summary = %+v, want 1 line read and a timeout error", summary)
+	}
+}
+
+func TestValidatePasswordStream_MaxDuration(t *testing.T) {
+	service := application.NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+	handler := handlers.NewPasswordHandler(service)
+	handler.SetStreamTimeouts(time.Second, 300*time.Millisecond)
+	server := httptest.NewServer(api.NewRouter(handler))
+	defer server.Close()
+
+	// A line every 50ms, forever: only the duration limit ends the stream.
+	summary := streamSummary(t, server, func(w io.Writer) {
+		for {
+			if _, err := io.WriteString(w, `{"password":"AbTp9!fok"}`+"\n"); err != nil {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+	if summary.Total == 0 || !strings.Contains(summary.Error, "first 300ms") {
+		t.Errorf("Summary = %+v, want the lines of the first 300ms and an error", summary)
+	}
+}
+
+// TestValidatePasswordStream_Streaming checks that results arrive while the
+// request body is still open.
+func TestValidatePasswordStream_Streaming(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	bodyReader, bodyWriter := io.Pipe()
+	defer bodyWriter.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/validate-passwords/stream", bodyReader)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	go io.WriteString(bodyWriter, `{"id":"first","password":"AbTp9!fok"}`+"\n")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var first models.ValidatePasswordStreamResult
+	done := make(chan error, 1)
+	go func() { done <- json.NewDecoder(resp.Body).Decode(&first) }()
+
+	select {
+	case err := <-done:
+		if err != nil || first.ID != "first" || !first.IsValid {
+			t.Errorf("First result = %+v, %v", first, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No result received before the request body was closed")
+	}
+}
+
+func TestValidatePasswordChange(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		request        models.ValidatePasswordChangeRequest
+		wantStatus     int
+		wantValid      bool
+		wantViolations []string
+	}{
+		{
+			name:       "valid unrelated new password",
+			request:    models.ValidatePasswordChangeRequest{OldPassword: "Summer2024!", NewPassword: "AbTp9!fok"},
+			wantStatus: http.StatusOK,
+			wantValid:  true,
+		},
+		{
+			name:           "invalid incremented year",
+			request:        models.ValidatePasswordChangeRequest{OldPassword: "Winter#1986", NewPassword: "Winter#1987"},
+			wantStatus:     http.StatusOK,
+			wantViolations: []string{rules.CodeSimilarToPrevious},
+		},
+		{
+			name:           "policy rules and similarity are reported together",
+			request:        models.ValidatePasswordChangeRequest{OldPassword: "Summer2024!", NewPassword: "Summer2025!"},
+			wantStatus:     http.StatusOK,
+			wantViolations: []string{rules.CodeDuplicateChar, rules.CodeSimilarToPrevious},
+		},
+		{
+			name:       "missing old password",
+			request:    models.ValidatePasswordChangeRequest{NewPassword: "AbTp9!fok"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown policy",
+			request:    models.ValidatePasswordChangeRequest{OldPassword: "Summer2024!", NewPassword: "AbTp9!fok", Policy: "pin"},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.request)
+			resp, err := http.Post(server.URL+"/api/v1/validate-password-change", "application/json", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			var response models.ValidatePasswordResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+
+			if response.IsValid != tt.wantValid || len(response.Violations) != len(tt.wantViolations) {
+				t.Fatalf("IsValid = %v with violations %+v, want %v with %v", response.IsValid, response.Violations, tt.wantValid, tt.wantViolations)
+			}
+			for i, code := range tt.wantViolations {
+				if response.Violations[i].Code != code {
+					t.Errorf("Violations[%d].Code = %s, want %s", i, response.Violations[i].Code, code)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantPolicy string
+		wantCount  int
+	}{
+		{name: "empty body", body: "", wantStatus: http.StatusOK, wantPolicy: "default", wantCount: 1},
+		{name: "several random passwords", body: `{"count":5,"length":12,"excludeAmbiguous":true}`, wantStatus: http.StatusOK, wantPolicy: "default", wantCount: 5},
+		{name: "passphrase", body: `{"mode":"passphrase","words":3}`, wantStatus: http.StatusOK, wantPolicy: "default", wantCount: 1},
+		{name: "named policy", body: `{"policy":"admin","count":2}`, wantStatus: http.StatusOK, wantPolicy: "admin", wantCount: 2},
+		{name: "length below policy minimum", body: `{"length":8}`, wantStatus: http.StatusBadRequest},
+		{name: "count too large", body: `{"count":21}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown mode", body: `{"mode":"pin"}`, wantStatus: http.StatusBadRequest},
+		{name: "unsatisfiable length", body: `{"length":100}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "unknown policy", body: `{"policy":"pin"}`, wantStatus: http.StatusNotFound},
+		{name: "invalid JSON", body: `{"count":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/v1/generate-password", "application/json", bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			if cc := resp.Header.Get("Cache-Control"); cc != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", cc)
+			}
+
+			var response models.GeneratePasswordResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+
+			if response.Policy != tt.wantPolicy || len(response.Passwords) != tt.wantCount {
+				t.Fatalf("Response = %+v, want %d passwords for policy %s", response, tt.wantCount, tt.wantPolicy)
+			}
+			for _, password := range response.Passwords {
+				body, _ := json.Marshal(models.ValidatePasswordRequest{Password: password, Policy: tt.wantPolicy})
+				check, err := http.Post(server.URL+"/api/v1/validate-password", "application/json", bytes.NewBuffer(body))
+				if err != nil {
+					t.Fatalf("Failed to make request: %v", err)
+				}
+				var result models.ValidatePasswordResponse
+				json.NewDecoder(check.Body).Decode(&result)
+				check.Body.Close()
+
+				if !result.IsValid {
+					t.Errorf("Generated password %q is invalid: %v", password, result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePasswordStrength(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		password  string
+		wantValid bool
+		maxScore  int
+		minScore  int
+	}{
+		{password: "Password1!", wantValid: false, minScore: 0, maxScore: 1},
+		{password: "AbTp9!fok", wantValid: true, minScore: 3, maxScore: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			body, _ := json.Marshal(models.ValidatePasswordRequest{Password: tt.password})
+			resp, err := http.Post(server.URL+"/api/v1/validate-password", "application/json", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var response models.ValidatePasswordResponse
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response.IsValid != tt.wantValid {
+				t.Errorf("IsValid = %v, want %v", response.IsValid, tt.wantValid)
+			}
+			if response.Strength == nil {
+				t.Fatal("Expected strength in response")
+			}
+			if response.Strength.Score < tt.minScore || response.Strength.Score > tt.maxScore {
+				t.Errorf("Strength score = %d, want between %d and %d", response.Strength.Score, tt.minScore, tt.maxScore)
+			}
+			if response.Strength.CrackTimeDisplay == "" {
+				t.Error("Expected crack time display in strength")
+			}
+		})
+	}
+}
+
+func TestValidatePasswordWithPolicy(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		request    models.ValidatePasswordRequest
+		wantStatus int
+		wantValid  bool
+		wantPolicy string
+	}{
+		{
+			name:       "no policy uses default",
+			path:       "/api/v1/validate-password",
+			request:    models.ValidatePasswordRequest{Password: "AbTp9!fok"},
+			wantStatus: http.StatusOK,
+			wantValid:  true,
+			wantPolicy: "default",
+		},
+		{
+			name:       "policy field selects admin",
+			path:       "/api/v1/validate-password",
+			request:    models.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: "admin"},
+			wantStatus: http.StatusOK,
+			wantValid:  false,
+			wantPolicy: "admin",
+		},
+		{
+			name:       "policy route selects admin",
+			path:       "/api/v1/policies/admin/validate",
+			request:    models.ValidatePasswordRequest{Password: "abcdefghijklm1"},
+			wantStatus: http.StatusOK,
+			wantValid:  true,
+			wantPolicy: "admin",
+		},
+		{
+			name:       "unknown policy field",
+			path:       "/api/v1/validate-password",
+			request:    models.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: "pin"},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown policy route",
+			path:       "/api/v1/policies/pin/validate",
+			request:    models.ValidatePasswordRequest{Password: "AbTp9!fok"},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "policy field conflicts with route",
+			path:       "/api/v1/policies/admin/validate",
+			request:    models.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: "default"},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.request)
+
+			resp, err := http.Post(server.URL+tt.path, "application/json", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				var errResp models.ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+					t.Fatalf("Failed to decode error response: %v", err)
+				}
+				return
+			}
+
+			var response models.ValidatePasswordResponse
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.IsValid != tt.wantValid || response.Policy != tt.wantPolicy {
+				t.Errorf("IsValid/Policy = %v/%s, want %v/%s. Errors: %v",
+					response.IsValid, response.Policy, tt.wantValid, tt.wantPolicy, response.Errors)
+			}
+		})
+	}
+}
+
+func TestPolicyEndpoint(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/policy")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var policy models.PolicyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&policy); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	wantRules := []string{"min_length", "digit", "lowercase", "uppercase", "special_char", "no_duplicates"}
+	if policy.Policy != "default" || len(policy.Rules) != len(wantRules) {
+		t.Fatalf("Policy = %s with %d rules, want default with %d rules", policy.Policy, len(policy.Rules), len(wantRules))
+	}
+	for i, rule := range wantRules {
+		if policy.Rules[i].Rule != rule || len(policy.Rules[i].Codes) == 0 || policy.Rules[i].Description == "" {
+			t.Errorf("Rules[%d] = %+v, want described rule %s", i, policy.Rules[i], rule)
+		}
+	}
+
+	if got := policy.Rules[0].Params["minLength"]; got != float64(9) {
+		t.Errorf("min_length params minLength = %v, want 9", got)
+	}
+	if got := policy.Rules[4].Params["allowedChars"]; got != "!@#$%^&*()-+" {
+		t.Errorf("special_char params allowedChars = %v, want !@#$%%^&*()-+", got)
+	}
+}
+
+func TestPolicyEndpoint_NamedPolicy(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/policy?policy=admin")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var policy models.PolicyResponse
+	json.NewDecoder(resp.Body).Decode(&policy)
+	if policy.Policy != "admin" || len(policy.Rules) != 3 || policy.Rules[0].Params["minLength"] != float64(14) {
+		t.Errorf("Admin policy = %+v", policy)
+	}
+
+	resp, err = http.Get(server.URL + "/api/v1/policy?policy=pin")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestPasswordHistory(t *testing.T) {
+	service := application.NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+	service.SetHistory(history.NewHistory(history.NewMemoryStore(), history.NewBcryptHasher(4), 2))
+	server := newTestServer(service)
+	defer server.Close()
+
+	record := func(password string) int {
+		body, _ := json.Marshal(models.RecordPasswordRequest{Password: password})
+		resp, err := http.Post(server.URL+"/api/v1/subjects/user-42/history", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	validate := func(subject, password string) models.ValidatePasswordResponse {
+		body, _ := json.Marshal(models.ValidatePasswordRequest{Password: password, SubjectID: subject})
+		resp, err := http.Post(server.URL+"/api/v1/validate-password", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var response models.ValidatePasswordResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return response
+	}
+
+	for _, password := range []string{"First#Pass1", "Second#Pass2", "Third#Pass3"} {
+		if status := record(password); status != http.StatusNoContent {
+			t.Fatalf("Record status = %d, want %d", status, http.StatusNoContent)
+		}
+	}
+	if status := record(""); status != http.StatusBadRequest {
+		t.Errorf("Record empty password status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if status := record(strings.Repeat("Ab1#", 19)); status != http.StatusBadRequest {
+		t.Errorf("Record password over 72 bytes with bcrypt status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if status := record(strings.Repeat("x", 8192)); status != http.StatusBadRequest {
+		t.Errorf("Record oversized body status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	tests := []struct {
+		name      string
+		subject   string
+		password  string
+		wantValid bool
+	}{
+		{"invalid latest password", "user-42", "Third#Pass3", false},
+		{"invalid previous password", "user-42", "Second#Pass2", false},
+		{"valid password older than history depth", "user-42", "First#Pass1", true},
+		{"valid password of another subject", "user-7", "Third#Pass3", true},
+		{"valid without subject", "", "Third#Pass3", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := validate(tt.subject, tt.password)
+			if response.IsValid != tt.wantValid {
+				t.Fatalf("IsValid = %v, want %v. Violations: %+v", response.IsValid, tt.wantValid, response.Violations)
+			}
+			if !tt.wantValid && response.Violations[0].Code != rules.CodePasswordReused {
+				t.Errorf("Violation code = %s, want %s", response.Violations[0].Code, rules.CodePasswordReused)
+			}
+		})
+	}
+}
+
+func TestPasswordHistory_Disabled(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	body, _ := json.Marshal(models.RecordPasswordRequest{Password: "AbTp9!fok"})
+	resp, err := http.Post(server.URL+"/api/v1/subjects/user-42/history", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusNotImplemented)
+	}
+}
+
+func TestHealthEndpoint(t *testing.T) {
+	server := setupTestServer()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Status code = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var health models.HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if health.Status != "healthy" {
+		t.Errorf("Health status = %s, want healthy", health.Status)
+	}
+}
+
+func TestHealthProbes(t *testing.T) {
+	probe := health.NewProbe()
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+	})
+	startup := httptest.NewServer(api.NewStartupRouter(probe))
+	defer startup.Close()
+	server := httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service), api.WithProbe(probe)))
+	defer server.Close()
+
+	get := func(t *testing.T, url string) (int, models.HealthResponse) {
+		t.Helper()
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var health models.HealthResponse
+		json.NewDecoder(resp.Body).Decode(&health)
+		return resp.StatusCode, health
+	}
+
+	tests := []struct {
+		name       string
+		notReady   map[string]string
+		server     *httptest.Server
+		path       string
+		wantStatus int
+		wantChecks []string
+	}{
+		{"loading: live", map[string]string{"policies": "loading"}, startup, "/health/live", http.StatusOK, nil},
+		{"loading: ready", map[string]string{"policies": "loading"}, startup, "/health/ready", http.StatusServiceUnavailable, []string{"policies: loading"}},
+		{"loading: api", map[string]string{"policies": "loading"}, startup, "/api/v1/policy", http.StatusServiceUnavailable, nil},
+		{"ready", nil, server, "/health/ready", http.StatusOK, nil},
+		{"ready: legacy endpoint", nil, server, "/health", http.StatusOK, nil},
+		{"shutting down: ready", map[string]string{"shutdown": "draining connections"}, server, "/health/ready", http.StatusServiceUnavailable, []string{"shutdown: draining connections"}},
+		{"shutting down: legacy endpoint", map[string]string{"shutdown": "draining connections"}, server, "/health", http.StatusServiceUnavailable, []string{"shutdown: draining connections"}},
+		{"shutting down: live", map[string]string{"shutdown": "draining connections"}, server, "/health/live", http.StatusOK, nil},
+		{"shutting down: api still served", map[string]string{"shutdown": "draining connections"}, server, "/api/v1/policy", http.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for component, reason := range tt.notReady {
+				probe.NotReady(component, reason)
+				defer probe.Ready(component)
+			}
+
+			status, health := get(t, tt.server.URL+tt.path)
+			if status != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", status, tt.wantStatus)
+			}
+			if strings.HasPrefix(tt.path, "/health") && !slices.Equal(health.Checks, tt.wantChecks) {
+				t.Errorf("Checks = %v, want %v", health.Checks, tt.wantChecks)
+			}
+		})
+	}
+}
+
+func TestRouterOptions(t *testing.T) {
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+	})
+	server := httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service),
+		api.WithMetricsPath("/internal/metrics"),
+		api.WithoutSwagger(),
+		api.WithCORS(middleware.CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}}),
+	))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"metrics at the configured path", "/internal/metrics", "", http.StatusOK, ""},
+		{"metrics not at the default path", "/metrics", "", http.StatusNotFound, ""},
+		{"swagger disabled", "/swagger/index.html", "", http.StatusNotFound, ""},
+		{"allowed origin", "/api/v1/policy", "https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"other origin", "/api/v1/policy", "https://evil.example.com", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+	})
+	newServer := func(policy middleware.CORSPolicy) *httptest.Server {
+		if err := policy.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		return httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service), api.WithCORS(policy)))
+	}
+	listed := newServer(middleware.CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+		AllowedHeaders:   []string{"Content-Type", "X-API-Key"},
+		ExposedHeaders:   []string{"RateLimit-Remaining", "Retry-After"},
+		MaxAge:           10 * time.Minute,
+	})
+	defer listed.Close()
+	anyOrigin := newServer(middleware.CORSPolicy{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"Content-Type"}})
+	defer anyOrigin.Close()
+	none := newServer(middleware.CORSPolicy{})
+	defer none.Close()
+
+	type headers map[string]string
+	tests := []struct {
+		name       string
+		server     *httptest.Server
+		method     string
+		request    headers
+		wantStatus int
+		want       headers
+	}{
+		{"exact origin", listed, http.MethodGet, headers{"Origin": "https://app.example.com"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin":      "https://app.example.com",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Expose-Headers":    "RateLimit-Remaining, Retry-After",
+			"Vary":                             "Origin",
+		}},
+		{"exact origin ignores case", listed, http.MethodGet, headers{"Origin": "https://APP.example.com"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "https://APP.example.com",
+		}},
+		{"wildcard subdomain", listed, http.MethodGet, headers{"Origin": "https://eu.app.example.org"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "https://eu.app.example.org",
+		}},
+		{"wildcard excludes the apex", listed, http.MethodGet, headers{"Origin": "https://example.org"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "",
+			"Vary":                        "Origin",
+		}},
+		{"wildcard excludes look-alike domains", listed, http.MethodGet, headers{"Origin": "https://evilexample.org"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "",
+		}},
+		{"port must match", listed, http.MethodGet, headers{"Origin": "https://app.example.com:8443"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "",
+		}},
+		{"scheme must match", listed, http.MethodGet, headers{"Origin": "http://app.example.com"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin":      "",
+			"Access-Control-Allow-Credentials": "",
+		}},
+		{"no origin", listed, http.MethodGet, nil, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "",
+			"Vary":                        "Origin",
+		}},
+		{"preflight", listed, http.MethodOptions, headers{
+			"Origin":                         "https://app.example.com",
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "content-type, x-api-key",
+		}, http.StatusNoContent, headers{
+			"Access-Control-Allow-Origin":      "https://app.example.com",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Allow-Methods":     "GET, POST",
+			"Access-Control-Allow-Headers":     "Content-Type, X-API-Key",
+			"Access-Control-Max-Age":           "600",
+			"Access-Control-Expose-Headers":    "",
+		}},
+		{"preflight from other origin", listed, http.MethodOptions, headers{
+			"Origin":                        "https://evil.example.com",
+			"Access-Control-Request-Method": "POST",
+		}, http.StatusForbidden, headers{
+			"Access-Control-Allow-Origin":  "",
+			"Access-Control-Allow-Methods": "",
+		}},
+		{"preflight for other method", listed, http.MethodOptions, headers{
+			"Origin":                        "https://app.example.com",
+			"Access-Control-Request-Method": "DELETE",
+		}, http.StatusForbidden, headers{
+			"Access-Control-Allow-Origin": "",
+		}},
+		{"preflight for other header", listed, http.MethodOptions, headers{
+			"Origin":                         "https://app.example.com",
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "Content-Type, Authorization",
+		}, http.StatusForbidden, headers{
+			"Access-Control-Allow-Origin": "",
+		}},
+		{"any origin", anyOrigin, http.MethodGet, headers{"Origin": "https://anywhere.example.net"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin":      "*",
+			"Access-Control-Allow-Credentials": "",
+			"Vary":                             "",
+		}},
+		{"any origin preflight", anyOrigin, http.MethodOptions, headers{
+			"Origin":                        "https://anywhere.example.net",
+			"Access-Control-Request-Method": "POST",
+		}, http.StatusNoContent, headers{
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "Content-Type",
+			"Access-Control-Max-Age":       "",
+		}},
+		{"no origins allowed", none, http.MethodGet, headers{"Origin": "https://app.example.com"}, http.StatusOK, headers{
+			"Access-Control-Allow-Origin": "",
+		}},
+		{"no origins allowed preflight", none, http.MethodOptions, headers{
+			"Origin":                        "https://app.example.com",
+			"Access-Control-Request-Method": "GET",
+		}, http.StatusForbidden, headers{
+			"Access-Control-Allow-Origin": "",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.server.URL+"/api/v1/policy", nil)
+			for name, value := range tt.request {
+				req.Header.Set(name, value)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			for name, want := range tt.want {
+				if got := resp.Header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  middleware.CORSPolicy
+		wantErr string
+	}{
+		{"empty", middleware.CORSPolicy{}, ""},
+		{"valid origins", middleware.CORSPolicy{AllowedOrigins: []string{"*", "https://app.example.com", "http://localhost:3000", "https://*.example.com:8443"}}, ""},
+		{"credentials with listed origins", middleware.CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, ""},
+		{"credentials with any origin", middleware.CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}, `"*" cannot be combined with credentials`},
+		{"no scheme", middleware.CORSPolicy{AllowedOrigins: []string{"app.example.com"}}, `invalid origin "app.example.com"`},
+		{"other scheme", middleware.CORSPolicy{AllowedOrigins: []string{"ftp://app.example.com"}}, `invalid origin "ftp://app.example.com"`},
+		{"path", middleware.CORSPolicy{AllowedOrigins: []string{"https://app.example.com/"}}, `invalid origin "https://app.example.com/"`},
+		{"wildcard in the middle", middleware.CORSPolicy{AllowedOrigins: []string{"https://app.*.com"}}, `invalid origin "https://app.*.com"`},
+		{"bare wildcard host", middleware.CORSPolicy{AllowedOrigins: []string{"https://*"}}, `invalid origin "https://*"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
 	}
 }
 
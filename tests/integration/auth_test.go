@@ -0,0 +1,190 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api"
+	"github.com/willherrera/itau-backend-challenge/internal/api/grpcserver"
+	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/history"
+	passwordv1 "github.com/willherrera/itau-backend-challenge/pkg/api/password/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	fullAccessKey  = "pwv_full-access-key"
+	defaultOnlyKey = "pwv_default-only-key"
+)
+
+func testKeys(t *testing.T) *auth.Keys {
+	t.Helper()
+
+	keys, err := auth.Parse([]byte(`
+keys:
+  - client: admin-portal
+    hash: ` + auth.HashKey(fullAccessKey) + `
+  - client: checkout-web
+    hash: ` + auth.HashKey(defaultOnlyKey) + `
+    policies: [default]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestPasswordHistory_ScopedToClient(t *testing.T) {
+	service := application.NewPasswordService([]domain.PasswordValidator{rules.NewMinLengthValidator(9)})
+	service.SetHistory(history.NewHistory(history.NewMemoryStore(), history.NewBcryptHasher(4), 2))
+	server := httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service), api.WithAPIKeys(testKeys(t))))
+	defer server.Close()
+
+	post := func(key, path string, body any) *http.Response {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		return resp
+	}
+
+	resp := post(fullAccessKey, "/api/v1/subjects/user-42/history", models.RecordPasswordRequest{Password: "Third#Pass3"})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Record status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+
+	for _, tt := range []struct {
+		key       string
+		wantValid bool
+	}{
+		{fullAccessKey, false},
+		{defaultOnlyKey, true},
+	} {
+		resp := post(tt.key, "/api/v1/validate-password", models.ValidatePasswordRequest{Password: "Third#Pass3", SubjectID: "user-42"})
+		var response models.ValidatePasswordResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if response.IsValid != tt.wantValid {
+			t.Errorf("key %s: IsValid = %v, want %v", tt.key, response.IsValid, tt.wantValid)
+		}
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+		"admin":                   {rules.NewMinLengthValidator(14)},
+	})
+	server := httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service), api.WithAPIKeys(testKeys(t))))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		key        string
+		body       any
+		wantStatus int
+	}{
+		{"missing key", http.MethodPost, "/api/v1/validate-password", "", models.ValidatePasswordRequest{Password: "AbTp9!fok"}, http.StatusUnauthorized},
+		{"unknown key", http.MethodPost, "/api/v1/validate-password", "pwv_wrong", models.ValidatePasswordRequest{Password: "AbTp9!fok"}, http.StatusUnauthorized},
+		{"allowed policy", http.MethodPost, "/api/v1/validate-password", defaultOnlyKey, models.ValidatePasswordRequest{Password: "AbTp9!fok"}, http.StatusOK},
+		{"policy field not allowed", http.MethodPost, "/api/v1/validate-password", defaultOnlyKey, models.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: "admin"}, http.StatusForbidden},
+		{"policy path not allowed", http.MethodPost, "/api/v1/policies/admin/validate", defaultOnlyKey, models.ValidatePasswordRequest{Password: "AbTp9!fok"}, http.StatusForbidden},
+		{"key without policy restrictions", http.MethodPost, "/api/v1/policies/admin/validate", fullAccessKey, models.ValidatePasswordRequest{Password: "AbTp9!fok"}, http.StatusOK},
+		{"batch not allowed", http.MethodPost, "/api/v1/validate-passwords", defaultOnlyKey, models.ValidatePasswordsRequest{Policy: "admin", Items: []models.ValidatePasswordItem{{Password: "AbTp9!fok"}}}, http.StatusForbidden},
+		{"generate not allowed", http.MethodPost, "/api/v1/generate-password", defaultOnlyKey, models.GeneratePasswordRequest{Policy: "admin"}, http.StatusForbidden},
+		{"policy description not allowed", http.MethodGet, "/api/v1/policy?policy=admin", defaultOnlyKey, nil, http.StatusForbidden},
+		{"health is open", http.MethodGet, "/health", "", nil, http.StatusOK},
+		{"metrics are open", http.MethodGet, "/metrics", "", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			if tt.body != nil {
+				json.NewEncoder(&body).Encode(tt.body)
+			}
+			req, _ := http.NewRequest(tt.method, server.URL+tt.path, &body)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized || tt.wantStatus == http.StatusForbidden {
+				var errResp models.ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error != http.StatusText(tt.wantStatus) || errResp.Message == "" {
+					t.Errorf("Body = %+v (%v), want an ErrorResponse", errResp, err)
+				}
+			}
+			if tt.wantStatus == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header is missing")
+			}
+		})
+	}
+}
+
+func TestGRPCAPIKeyAuth(t *testing.T) {
+	client := passwordv1.NewPasswordServiceClient(setupGRPCServer(t, grpc.ChainUnaryInterceptor(grpcserver.AuthInterceptor(testKeys(t)))))
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		policy   string
+		wantCode codes.Code
+	}{
+		{"missing key", context.Background(), "", codes.Unauthenticated},
+		{"unknown key", withKey("pwv_wrong"), "", codes.Unauthenticated},
+		{"allowed policy", withKey(defaultOnlyKey), "", codes.OK},
+		{"policy not allowed", withKey(defaultOnlyKey), "admin", codes.PermissionDenied},
+		{"key without policy restrictions", withKey(fullAccessKey), "admin", codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ValidatePassword(tt.ctx, &passwordv1.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: tt.policy})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %s, want %s (%v)", got, tt.wantCode, err)
+			}
+		})
+	}
+
+	resp, err := client.ListPolicies(withKey(defaultOnlyKey), &passwordv1.ListPoliciesRequest{})
+	if err != nil {
+		t.Fatalf("ListPolicies() error: %v", err)
+	}
+	if !slices.Equal(resp.GetPolicies(), []string{application.DefaultPolicy}) {
+		t.Errorf("policies = %v, want only the policies of the key", resp.GetPolicies())
+	}
+}
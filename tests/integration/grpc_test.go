@@ -0,0 +1,256 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api/grpcserver"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	passwordv1 "github.com/willherrera/itau-backend-challenge/pkg/api/password/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func setupGRPCServer(t *testing.T, opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {
+			rules.NewMinLengthValidator(9),
+			rules.NewDigitValidator(),
+			rules.NewLowercaseValidator(),
+			rules.NewUppercaseValidator(),
+			rules.NewSpecialCharValidator("!@#$%^&*()-+"),
+			rules.NewNoDuplicatesValidator(),
+		},
+		"admin": {
+			rules.NewMinLengthValidator(14),
+			rules.NewPersonalInfoValidator(4),
+		},
+	})
+
+	listener := bufconn.Listen(1 << 20)
+	server, _ := grpcserver.New(service, opts...)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial gRPC server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCValidatePassword(t *testing.T) {
+	client := passwordv1.NewPasswordServiceClient(setupGRPCServer(t))
+
+	tests := []struct {
+		name           string
+		request        *passwordv1.ValidatePasswordRequest
+		wantCode       codes.Code
+		wantValid      bool
+		wantViolations []string
+	}{
+		{
+			name:      "valid password",
+			request:   &passwordv1.ValidatePasswordRequest{Password: "AbTp9!fok"},
+			wantCode:  codes.OK,
+			wantValid: true,
+		},
+		{
+			name:           "duplicate character",
+			request:        &passwordv1.ValidatePasswordRequest{Password: "AbTp9!foo"},
+			wantCode:       codes.OK,
+			wantViolations: []string{rules.CodeDuplicateChar},
+		},
+		{
+			name: "named policy with user info",
+			request: &passwordv1.ValidatePasswordRequest{
+				Password: "Jsilva#Kp9wQ2zX",
+				Policy:   "admin",
+				User:     &passwordv1.UserInfo{Username: "jsilva"},
+			},
+			wantCode:       codes.OK,
+			wantViolations: []string{rules.CodeContainsPersonalInfo},
+		},
+		{
+			name:     "empty password",
+			request:  &passwordv1.ValidatePasswordRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown policy",
+			request:  &passwordv1.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: "pin"},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.ValidatePassword(context.Background(), tt.request)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if resp.GetIsValid() != tt.wantValid || len(resp.GetViolations()) != len(tt.wantViolations) {
+				t.Fatalf("IsValid = %v with violations %v, want %v with %v", resp.GetIsValid(), resp.GetViolations(), tt.wantValid, tt.wantViolations)
+			}
+			for i, code := range tt.wantViolations {
+				if resp.GetViolations()[i].GetCode() != code {
+					t.Errorf("Violations[%d].Code = %s, want %s", i, resp.GetViolations()[i].GetCode(), code)
+				}
+			}
+			if resp.GetStrength() == nil {
+				t.Error("Strength is missing")
+			}
+		})
+	}
+}
+
+func TestGRPCViolationDetails(t *testing.T) {
+	client := passwordv1.NewPasswordServiceClient(setupGRPCServer(t))
+
+	resp, err := client.ValidatePassword(context.Background(), &passwordv1.ValidatePasswordRequest{Password: "AbTp9!foo"})
+	if err != nil {
+		t.Fatalf("ValidatePassword() error: %v", err)
+	}
+
+	v := resp.GetViolations()[0]
+	if v.GetChar() != "o" || v.Position == nil || v.GetPosition() != 8 {
+		t.Errorf("Violation = %v, want char o at position 8", v)
+	}
+	if first := v.GetParams().GetFields()["firstPosition"].GetNumberValue(); first != 7 {
+		t.Errorf("params.firstPosition = %v, want 7", first)
+	}
+}
+
+func TestGRPCLocalizedMessages(t *testing.T) {
+	client := passwordv1.NewPasswordServiceClient(setupGRPCServer(t))
+
+	resp, err := client.ValidatePassword(context.Background(), &passwordv1.ValidatePasswordRequest{Password: "AbTp9!foo", Locale: "pt-BR"})
+	if err != nil {
+		t.Fatalf("ValidatePassword() error: %v", err)
+	}
+	if want := "a senha não deve conter caracteres repetidos"; resp.GetViolations()[0].GetMessage() != want || resp.GetErrors()[0] != want {
+		t.Errorf("Message = %q, want %q", resp.GetViolations()[0].GetMessage(), want)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "es-MX, en;q=0.5")
+	resp, err = client.ValidatePassword(ctx, &passwordv1.ValidatePasswordRequest{Password: "AbTp9!foo"})
+	if err != nil {
+		t.Fatalf("ValidatePassword() error: %v", err)
+	}
+	if want := "la contraseña no debe contener caracteres repetidos"; resp.GetViolations()[0].GetMessage() != want {
+		t.Errorf("Message = %q, want %q", resp.GetViolations()[0].GetMessage(), want)
+	}
+}
+
+func TestGRPCValidatePasswordChange(t *testing.T) {
+	client := passwordv1.NewPasswordServiceClient(setupGRPCServer(t))
+
+	resp, err := client.ValidatePasswordChange(context.Background(), &passwordv1.ValidatePasswordChangeRequest{
+		OldPassword: "Winter#1986",
+		NewPassword: "Winter#1987",
+	})
+	if err != nil {
+		t.Fatalf("ValidatePasswordChange() error: %v", err)
+	}
+	if resp.GetIsValid() || resp.GetViolations()[0].GetCode() != rules.CodeSimilarToPrevious {
+		t.Errorf("Response = %v, want SIMILAR_TO_PREVIOUS", resp)
+	}
+
+	_, err = client.ValidatePasswordChange(context.Background(), &passwordv1.ValidatePasswordChangeRequest{NewPassword: "AbTp9!fok"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestGRPCPolicies(t *testing.T) {
+	client := passwordv1.NewPasswordServiceClient(setupGRPCServer(t))
+
+	list, err := client.ListPolicies(context.Background(), &passwordv1.ListPoliciesRequest{})
+	if err != nil {
+		t.Fatalf("ListPolicies() error: %v", err)
+	}
+	if got := list.GetPolicies(); len(got) != 2 || got[0] != "admin" || got[1] != "default" {
+		t.Errorf("Policies = %v, want [admin default]", got)
+	}
+
+	policy, err := client.GetPolicy(context.Background(), &passwordv1.GetPolicyRequest{})
+	if err != nil {
+		t.Fatalf("GetPolicy() error: %v", err)
+	}
+	if policy.GetPolicy() != "default" || len(policy.GetRules()) != 6 {
+		t.Fatalf("GetPolicy() = %v, want 6 rules of the default policy", policy)
+	}
+	if minLength := policy.GetRules()[0].GetParams().GetFields()["minLength"].GetNumberValue(); minLength != 9 {
+		t.Errorf("min_length params.minLength = %v, want 9", minLength)
+	}
+
+	_, err = client.GetPolicy(context.Background(), &passwordv1.GetPolicyRequest{Policy: "pin"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+func TestGRPCHealth(t *testing.T) {
+	client := healthpb.NewHealthClient(setupGRPCServer(t))
+
+	for _, service := range []string{"", "password.v1.PasswordService"} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) error: %v", service, err)
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Check(%q) = %v, want SERVING", service, resp.GetStatus())
+		}
+	}
+}
+
+func TestGRPCReflection(t *testing.T) {
+	client := reflectionpb.NewServerReflectionClient(setupGRPCServer(t))
+
+	stream, err := client.ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo() error: %v", err)
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error: %v", err)
+	}
+
+	found := false
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		if s.GetName() == "password.v1.PasswordService" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Reflection services = %v, want password.v1.PasswordService", resp.GetListServicesResponse().GetService())
+	}
+}
@@ -0,0 +1,274 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api"
+	"github.com/willherrera/itau-backend-challenge/internal/api/grpcserver"
+	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/ratelimit"
+	passwordv1 "github.com/willherrera/itau-backend-challenge/pkg/api/password/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// slowRefill is so low that buckets do not refill during a test.
+const slowRefill = 0.001
+
+func newRateLimitedServer(t *testing.T, keys *auth.Keys, limits api.RateLimits) *httptest.Server {
+	t.Helper()
+
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+	})
+	limits.Store = ratelimit.NewMemoryStore(0)
+	opts := []api.Option{api.WithRateLimits(limits)}
+	if keys != nil {
+		opts = append(opts, api.WithAPIKeys(keys))
+	}
+	server := httptest.NewServer(api.NewRouter(handlers.NewPasswordHandler(service), opts...))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func validateWith(t *testing.T, server *httptest.Server, header http.Header) *http.Response {
+	t.Helper()
+
+	body, _ := json.Marshal(models.ValidatePasswordRequest{Password: "AbTp9!fok"})
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/validate-password", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestRateLimit_PerIP(t *testing.T) {
+	server := newRateLimitedServer(t, nil, api.RateLimits{PerIP: ratelimit.Limit{Rate: slowRefill, Burst: 2}})
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		resp := validateWith(t, server, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d: Status = %d, want 200", i, resp.StatusCode)
+		}
+		if got := resp.Header.Get("RateLimit-Remaining"); got != wantRemaining || resp.Header.Get("RateLimit-Limit") != "2" {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %q", i, got, wantRemaining)
+		}
+	}
+
+	body, _ := json.Marshal(models.ValidatePasswordRequest{Password: "AbTp9!fok"})
+	resp, err := http.Post(server.URL+"/api/v1/validate-password", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Status = %d, want 429", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") != "1000" || resp.Header.Get("RateLimit-Reset") == "" {
+		t.Errorf("Retry-After = %q, RateLimit-Reset = %q, want the time to the next token", resp.Header.Get("Retry-After"), resp.Header.Get("RateLimit-Reset"))
+	}
+	var errResp models.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error != "Too Many Requests" {
+		t.Errorf("Body = %+v (%v), want an ErrorResponse", errResp, err)
+	}
+
+	// The operational endpoints are not limited.
+	health, err := http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("health Status = %d, want 200", health.StatusCode)
+	}
+}
+
+func TestRateLimit_TrustedProxies(t *testing.T) {
+	server := newRateLimitedServer(t, nil, api.RateLimits{
+		PerIP:          ratelimit.Limit{Rate: slowRefill, Burst: 1},
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")},
+	})
+
+	tests := []struct {
+		forwardedFor string
+		wantStatus   int
+	}{
+		{"203.0.113.7", http.StatusOK},
+		{"203.0.113.8", http.StatusOK},
+		{"203.0.113.7", http.StatusTooManyRequests},
+		// A client cannot escape its limit by prepending addresses.
+		{"198.51.100.1, 203.0.113.8", http.StatusTooManyRequests},
+		// Addresses of trusted proxies are skipped.
+		{"203.0.113.9, 127.0.0.2", http.StatusOK},
+		// IPv6 clients share the bucket of their /64.
+		{"2001:db8::1", http.StatusOK},
+		{"2001:db8::2", http.StatusTooManyRequests},
+		{"2001:db8:0:1::1", http.StatusOK},
+	}
+	for _, tt := range tests {
+		resp := validateWith(t, server, http.Header{"X-Forwarded-For": {tt.forwardedFor}})
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("X-Forwarded-For %q: Status = %d, want %d", tt.forwardedFor, resp.StatusCode, tt.wantStatus)
+		}
+	}
+}
+
+func validateBatch(t *testing.T, server *httptest.Server, n int) *http.Response {
+	t.Helper()
+
+	req := models.ValidatePasswordsRequest{Items: make([]models.ValidatePasswordItem, n)}
+	for i := range req.Items {
+		req.Items[i].Password = "AbTp9!fok"
+	}
+	body, _ := json.Marshal(req)
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestRateLimit_Items(t *testing.T) {
+	server := newRateLimitedServer(t, nil, api.RateLimits{Items: ratelimit.Limit{Rate: slowRefill, Burst: 3}})
+
+	if resp := validateBatch(t, server, 2); resp.StatusCode != http.StatusOK {
+		t.Fatalf("first batch: Status = %d, want 200", resp.StatusCode)
+	}
+	resp := validateBatch(t, server, 2)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("second batch: Status = %d, Retry-After = %q, want 429 with a retry time", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	// The request limits are separate: single validations go on.
+	if resp := validateWith(t, server, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("single validation: Status = %d, want 200", resp.StatusCode)
+	}
+	if resp := validateBatch(t, server, 1); resp.StatusCode != http.StatusOK {
+		t.Errorf("batch within the remaining tokens: Status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestRateLimit_ItemsNotChargedForUnknownPolicy(t *testing.T) {
+	server := newRateLimitedServer(t, nil, api.RateLimits{Items: ratelimit.Limit{Rate: slowRefill, Burst: 2}})
+
+	body := `{"policy":"pin","items":[{"password":"AbTp9!fok"},{"password":"AbTp9!fok"}]}`
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown policy: Status = %d, want 404", resp.StatusCode)
+	}
+
+	if resp := validateBatch(t, server, 2); resp.StatusCode != http.StatusOK {
+		t.Errorf("batch after an unknown policy: Status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestRateLimit_ItemsSlowDownStreams(t *testing.T) {
+	server := newRateLimitedServer(t, nil, api.RateLimits{Items: ratelimit.Limit{Rate: 20, Burst: 1}})
+
+	body := strings.Repeat(`{"password":"AbTp9!fok"}`+"\n", 4)
+	start := time.Now()
+	resp, err := http.Post(server.URL+"/api/v1/validate-passwords/stream", "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	elapsed := time.Since(start)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var summary models.ValidatePasswordStreamSummary
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil || summary.Summary.Valid != 4 || summary.Summary.Error != "" {
+		t.Errorf("summary = %s (%v), want the 4 passwords validated", lines[len(lines)-1], err)
+	}
+	// One token is available at once; the other three come every 50ms.
+	if elapsed < 140*time.Millisecond {
+		t.Errorf("stream took %v, want it slowed down to the item rate", elapsed)
+	}
+}
+
+func TestRateLimit_PerClientTier(t *testing.T) {
+	keys, err := auth.Parse([]byte(`
+keys:
+  - client: batch-job
+    hash: ` + auth.HashKey("pwv_batch") + `
+    tier: small
+  - client: admin-portal
+    hash: ` + auth.HashKey("pwv_admin") + `
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newRateLimitedServer(t, keys, api.RateLimits{
+		Tiers: ratelimit.Tiers{"small": {Rate: slowRefill, Burst: 1}},
+	})
+
+	tests := []struct {
+		key        string
+		wantStatus int
+	}{
+		{"pwv_batch", http.StatusOK},
+		{"pwv_batch", http.StatusTooManyRequests},
+		// Clients without a configured tier and no default tier are not limited.
+		{"pwv_admin", http.StatusOK},
+		{"pwv_admin", http.StatusOK},
+		// Unauthenticated requests are rejected before the client limit.
+		{"", http.StatusUnauthorized},
+	}
+	for i, tt := range tests {
+		resp := validateWith(t, server, http.Header{"X-Api-Key": {tt.key}})
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("request %d (%s): Status = %d, want %d", i, tt.key, resp.StatusCode, tt.wantStatus)
+		}
+	}
+}
+
+func TestGRPCRateLimit(t *testing.T) {
+	store := ratelimit.NewMemoryStore(0)
+	conn := setupGRPCServer(t, grpc.ChainUnaryInterceptor(
+		grpcserver.IPRateLimitInterceptor(store, ratelimit.Limit{Rate: slowRefill, Burst: 2}),
+		grpcserver.AuthInterceptor(testKeys(t)),
+		grpcserver.ClientRateLimitInterceptor(store, ratelimit.Tiers{ratelimit.DefaultTier: {Rate: slowRefill, Burst: 1}}),
+	))
+	client := passwordv1.NewPasswordServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", fullAccessKey)
+
+	if _, err := client.ValidatePassword(ctx, &passwordv1.ValidatePasswordRequest{Password: "AbTp9!fok"}); err != nil {
+		t.Fatalf("ValidatePassword() error: %v", err)
+	}
+
+	// The client limit is exhausted first, then the IP limit.
+	for _, key := range []string{fullAccessKey, defaultOnlyKey} {
+		var header metadata.MD
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+		_, err := client.ValidatePassword(ctx, &passwordv1.ValidatePasswordRequest{Password: "AbTp9!fok"}, grpc.Header(&header))
+		if status.Code(err) != codes.ResourceExhausted || len(header.Get("retry-after")) == 0 {
+			t.Errorf("%s: error = %v, header = %v, want RESOURCE_EXHAUSTED with retry-after", key, err, header)
+		}
+	}
+}
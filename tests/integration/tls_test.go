@@ -0,0 +1,273 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/willherrera/itau-backend-challenge/internal/api"
+	"github.com/willherrera/itau-backend-challenge/internal/api/grpcserver"
+	"github.com/willherrera/itau-backend-challenge/internal/api/handlers"
+	"github.com/willherrera/itau-backend-challenge/internal/api/models"
+	"github.com/willherrera/itau-backend-challenge/internal/application"
+	"github.com/willherrera/itau-backend-challenge/internal/auth"
+	"github.com/willherrera/itau-backend-challenge/internal/domain"
+	"github.com/willherrera/itau-backend-challenge/internal/domain/rules"
+	"github.com/willherrera/itau-backend-challenge/internal/tlsconfig"
+	passwordv1 "github.com/willherrera/itau-backend-challenge/pkg/api/password/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// mtlsFixture is a CA with a server certificate and the certificates of
+// two clients: billing-service, listed in the keys file, and reports, which
+// is trusted but unknown to it.
+type mtlsFixture struct {
+	certs   *tlsconfig.Certificates
+	roots   *x509.CertPool
+	billing tls.Certificate
+	reports tls.Certificate
+}
+
+func newMTLSFixture(t *testing.T) *mtlsFixture {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, template *x509.Certificate) (certPEM, keyPEM []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template.SerialNumber = big.NewInt(serial)
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	}
+	keyPair := func(certPEM, keyPEM []byte) tls.Certificate {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+
+	dir := t.TempDir()
+	files := map[string][]byte{"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})}
+	files["tls.crt"], files["tls.key"] = issue(2, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "password-validator"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	certs, err := tlsconfig.Load(tlsconfig.Options{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		ClientAuth:   tlsconfig.OptionalClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spiffe, _ := url.Parse("spiffe://payments/billing")
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	return &mtlsFixture{
+		certs: certs,
+		roots: roots,
+		billing: keyPair(issue(3, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "billing"},
+			URIs:        []*url.URL{spiffe},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})),
+		reports: keyPair(issue(4, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "reports"},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})),
+	}
+}
+
+// clientConfig returns the TLS configuration of a client presenting cert,
+// or no certificate when cert is nil.
+func (f *mtlsFixture) clientConfig(cert *tls.Certificate) *tls.Config {
+	config := &tls.Config{RootCAs: f.roots, ServerName: "localhost"}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+	return config
+}
+
+func mtlsKeys(t *testing.T) *auth.Keys {
+	t.Helper()
+
+	keys, err := auth.Parse([]byte(`
+keys:
+  - client: admin-portal
+    hash: ` + auth.HashKey(fullAccessKey) + `
+  - client: billing-service
+    certificate: uri:spiffe://payments/billing
+    policies: [default]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func mtlsService() *application.PasswordService {
+	service, _ := application.NewPasswordServiceWithPolicies(map[string][]domain.PasswordValidator{
+		application.DefaultPolicy: {rules.NewMinLengthValidator(9)},
+		"admin":                   {rules.NewMinLengthValidator(14)},
+	})
+	return service
+}
+
+func TestMutualTLS_HTTP(t *testing.T) {
+	fixture := newMTLSFixture(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{
+		Handler:   api.NewRouter(handlers.NewPasswordHandler(mtlsService()), api.WithAPIKeys(mtlsKeys(t))),
+		TLSConfig: fixture.certs.Config(),
+		ErrorLog:  log.New(io.Discard, "", 0),
+	}
+	go server.ServeTLS(listener, "", "")
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		cert       *tls.Certificate
+		key        string
+		policy     string
+		wantStatus int
+	}{
+		{"certificate identity", &fixture.billing, "", "", http.StatusOK},
+		{"certificate identity policies apply", &fixture.billing, "", "admin", http.StatusForbidden},
+		{"unknown certificate without key", &fixture.reports, "", "", http.StatusUnauthorized},
+		{"unknown certificate with key", &fixture.reports, fullAccessKey, "admin", http.StatusOK},
+		{"no certificate with key", nil, fullAccessKey, "", http.StatusOK},
+		{"no certificate without key", nil, "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: fixture.clientConfig(tt.cert)}}
+			body, _ := json.Marshal(models.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: tt.policy})
+			req, _ := http.NewRequest(http.MethodPost, "https://"+listener.Addr().String()+"/api/v1/validate-password", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMutualTLS_GRPC(t *testing.T) {
+	fixture := newMTLSFixture(t)
+
+	listener := bufconn.Listen(1 << 20)
+	server, _ := grpcserver.New(mtlsService(),
+		grpc.Creds(credentials.NewTLS(fixture.certs.Config())),
+		grpc.ChainUnaryInterceptor(grpcserver.AuthInterceptor(mtlsKeys(t))),
+	)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	dial := func(cert *tls.Certificate) passwordv1.PasswordServiceClient {
+		conn, err := grpc.NewClient("passthrough:///bufconn",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(credentials.NewTLS(fixture.clientConfig(cert))),
+		)
+		if err != nil {
+			t.Fatalf("Failed to dial gRPC server: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return passwordv1.NewPasswordServiceClient(conn)
+	}
+
+	tests := []struct {
+		name     string
+		cert     *tls.Certificate
+		policy   string
+		wantCode codes.Code
+	}{
+		{"certificate identity", &fixture.billing, "", codes.OK},
+		{"certificate identity policies apply", &fixture.billing, "admin", codes.PermissionDenied},
+		{"unknown certificate", &fixture.reports, "", codes.Unauthenticated},
+		{"no certificate", nil, "", codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dial(tt.cert).ValidatePassword(context.Background(), &passwordv1.ValidatePasswordRequest{Password: "AbTp9!fok", Policy: tt.policy})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+		})
+	}
+}